@@ -0,0 +1,190 @@
+package client
+
+import (
+	"github.com/xanzy/go-gitlab"
+)
+
+// MRApprovalState describes who approved a merge request and how many
+// approvals it still needs.
+type MRApprovalState struct {
+	// Approved is true when all the required approvals are given.
+	Approved bool
+
+	// ApprovedBy holds the usernames of the users who approved.
+	ApprovedBy []string
+
+	ApprovalsRequired int
+	ApprovalsLeft     int
+}
+
+// ApproveMR approves the merge request as the user owning the token.
+func (cli *Client) ApproveMR(pid interface{}, iid int) error {
+	_, _, err := cli.c.MergeRequestApprovals.ApproveMergeRequest(
+		pid, iid, &gitlab.ApproveMergeRequestOptions{},
+	)
+
+	return err
+}
+
+// UnapproveMR withdraws the approval given by the user owning the token.
+func (cli *Client) UnapproveMR(pid interface{}, iid int) error {
+	_, err := cli.c.MergeRequestApprovals.UnapproveMergeRequest(pid, iid)
+
+	return err
+}
+
+// GetMRApprovalState returns the approval state of the merge request.
+func (cli *Client) GetMRApprovalState(pid interface{}, iid int) (MRApprovalState, error) {
+	v, _, err := cli.c.MergeRequestApprovals.GetConfiguration(pid, iid)
+	if err != nil {
+		return MRApprovalState{}, err
+	}
+
+	r := MRApprovalState{
+		Approved:          v.Approved,
+		ApprovalsRequired: v.ApprovalsRequired,
+		ApprovalsLeft:     v.ApprovalsLeft,
+	}
+
+	for _, item := range v.ApprovedBy {
+		if item.User != nil {
+			r.ApprovedBy = append(r.ApprovedBy, item.User.Username)
+		}
+	}
+
+	return r, nil
+}
+
+// ApprovalRuleOptions are the options of the approval rules of the merge
+// requests and the projects.
+type ApprovalRuleOptions struct {
+	Name              string
+	ApprovalsRequired int
+
+	// Usernames and GroupIDs are the users and the groups eligible to
+	// approve.
+	Usernames []string
+	GroupIDs  []int
+}
+
+// approvers resolves the eligible approvers of the rule. The returned
+// slices are never nil, so that they are sent as empty lists.
+func (cli *Client) approvers(opts *ApprovalRuleOptions) (userIDs, groupIDs []int, err error) {
+	userIDs = make([]int, len(opts.Usernames))
+	for i, name := range opts.Usernames {
+		if userIDs[i], err = cli.userID(name); err != nil {
+			return
+		}
+	}
+
+	groupIDs = append([]int{}, opts.GroupIDs...)
+
+	return
+}
+
+// ListMRApprovalRules returns the approval rules of the merge request.
+func (cli *Client) ListMRApprovalRules(pid interface{}, iid int) ([]*gitlab.MergeRequestApprovalRule, error) {
+	v, _, err := cli.c.MergeRequestApprovals.GetApprovalRules(pid, iid)
+
+	return v, err
+}
+
+// CreateMRApprovalRule adds an approval rule to the merge request.
+func (cli *Client) CreateMRApprovalRule(
+	pid interface{}, iid int, opts ApprovalRuleOptions,
+) (*gitlab.MergeRequestApprovalRule, error) {
+	userIDs, groupIDs, err := cli.approvers(&opts)
+	if err != nil {
+		return nil, err
+	}
+
+	v, _, err := cli.c.MergeRequestApprovals.CreateApprovalRule(
+		pid, iid, &gitlab.CreateMergeRequestApprovalRuleOptions{
+			Name:              gitlab.Ptr(opts.Name),
+			ApprovalsRequired: gitlab.Ptr(opts.ApprovalsRequired),
+			UserIDs:           &userIDs,
+			GroupIDs:          &groupIDs,
+		},
+	)
+
+	return v, err
+}
+
+// UpdateMRApprovalRule replaces the settings of the approval rule of the
+// merge request.
+func (cli *Client) UpdateMRApprovalRule(
+	pid interface{}, iid, ruleID int, opts ApprovalRuleOptions,
+) (*gitlab.MergeRequestApprovalRule, error) {
+	userIDs, groupIDs, err := cli.approvers(&opts)
+	if err != nil {
+		return nil, err
+	}
+
+	v, _, err := cli.c.MergeRequestApprovals.UpdateApprovalRule(
+		pid, iid, ruleID, &gitlab.UpdateMergeRequestApprovalRuleOptions{
+			Name:              gitlab.Ptr(opts.Name),
+			ApprovalsRequired: gitlab.Ptr(opts.ApprovalsRequired),
+			UserIDs:           &userIDs,
+			GroupIDs:          &groupIDs,
+		},
+	)
+
+	return v, err
+}
+
+// ListProjectApprovalRules returns the approval rules of the project,
+// which apply to the new merge requests.
+func (cli *Client) ListProjectApprovalRules(pid interface{}) ([]*gitlab.ProjectApprovalRule, error) {
+	return CollectAll(func(opts *gitlab.ListOptions) ([]*gitlab.ProjectApprovalRule, *gitlab.Response, error) {
+		v := gitlab.GetProjectApprovalRulesListsOptions(*opts)
+
+		return cli.c.Projects.GetProjectApprovalRules(pid, &v)
+	})
+}
+
+// CreateProjectApprovalRule adds an approval rule to the project, which
+// applies to the merge requests targeting the protected branches whose IDs
+// are protectedBranchIDs, or to all of them if it is empty.
+func (cli *Client) CreateProjectApprovalRule(
+	pid interface{}, opts ApprovalRuleOptions, protectedBranchIDs []int,
+) (*gitlab.ProjectApprovalRule, error) {
+	userIDs, groupIDs, err := cli.approvers(&opts)
+	if err != nil {
+		return nil, err
+	}
+
+	branchIDs := append([]int{}, protectedBranchIDs...)
+
+	v, _, err := cli.c.Projects.CreateProjectApprovalRule(pid, &gitlab.CreateProjectLevelRuleOptions{
+		Name:               gitlab.Ptr(opts.Name),
+		ApprovalsRequired:  gitlab.Ptr(opts.ApprovalsRequired),
+		UserIDs:            &userIDs,
+		GroupIDs:           &groupIDs,
+		ProtectedBranchIDs: &branchIDs,
+	})
+
+	return v, err
+}
+
+// UpdateProjectApprovalRule replaces the settings of the approval rule of
+// the project. See CreateProjectApprovalRule for protectedBranchIDs.
+func (cli *Client) UpdateProjectApprovalRule(
+	pid interface{}, ruleID int, opts ApprovalRuleOptions, protectedBranchIDs []int,
+) (*gitlab.ProjectApprovalRule, error) {
+	userIDs, groupIDs, err := cli.approvers(&opts)
+	if err != nil {
+		return nil, err
+	}
+
+	branchIDs := append([]int{}, protectedBranchIDs...)
+
+	v, _, err := cli.c.Projects.UpdateProjectApprovalRule(pid, ruleID, &gitlab.UpdateProjectLevelRuleOptions{
+		Name:               gitlab.Ptr(opts.Name),
+		ApprovalsRequired:  gitlab.Ptr(opts.ApprovalsRequired),
+		UserIDs:            &userIDs,
+		GroupIDs:           &groupIDs,
+		ProtectedBranchIDs: &branchIDs,
+	})
+
+	return v, err
+}
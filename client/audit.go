@@ -0,0 +1,155 @@
+package client
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// AuditRecord describes a mutating request sent by the client.
+type AuditRecord struct {
+	Time time.Time `json:"time"`
+
+	// Operation is the API called, such as
+	// "PUT /projects/:id/merge_requests/:id/merge".
+	Operation string `json:"operation"`
+
+	// Project is the ID or the path of the project, and Target is the
+	// path of the resource in it, such as "merge_requests/12/merge". For
+	// the APIs outside projects, Project is empty and Target is the path.
+	Project string `json:"project,omitempty"`
+	Target  string `json:"target"`
+
+	// TokenID identifies the token the request was sent with, without
+	// revealing it. It is the beginning of the SHA-256 of the token.
+	TokenID string `json:"token_id,omitempty"`
+
+	// Status is the status code of the response, or 0 if the request
+	// failed without a response, which is described by Error.
+	Status int    `json:"status"`
+	Error  string `json:"error,omitempty"`
+
+	// DryRun is true if the request was skipped in the dry-run mode.
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// AuditSink stores the audit records. It must be safe for concurrent use.
+type AuditSink interface {
+	Record(AuditRecord) error
+}
+
+// WithAudit makes the client record every mutating request to sink,
+// including the ones skipped in the dry-run mode. The failures of sink are
+// logged by the standard logger of logrus.
+func WithAudit(sink AuditSink) Option {
+	return func(o *options) {
+		o.audit = sink
+	}
+}
+
+// jsonLinesSink writes each record as a line of JSON.
+type jsonLinesSink struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// NewJSONLinesAuditSink returns the sink which writes each record to w as
+// a line of JSON, such as to a file opened for appending.
+func NewJSONLinesAuditSink(w io.Writer) AuditSink {
+	return &jsonLinesSink{enc: json.NewEncoder(w)}
+}
+
+func (s *jsonLinesSink) Record(r AuditRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.enc.Encode(&r)
+}
+
+// auditTransport records the mutating requests sent by base.
+type auditTransport struct {
+	base   http.RoundTripper
+	sink   AuditSink
+	dryRun bool
+}
+
+func (t *auditTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	mutating, err := isMutating(req)
+	if err != nil || !mutating {
+		return t.base.RoundTrip(req)
+	}
+
+	r := AuditRecord{
+		Time:      time.Now(),
+		Operation: operation(req),
+		DryRun:    t.dryRun,
+	}
+	r.Project, r.Target = auditTarget(req.URL)
+
+	resp, err := t.base.RoundTrip(req)
+
+	sent := req
+	if err != nil {
+		r.Error = err.Error()
+	} else {
+		r.Status = resp.StatusCode
+
+		// The token may be set by the inner transports.
+		if resp.Request != nil {
+			sent = resp.Request
+		}
+	}
+
+	r.TokenID = tokenID(sent)
+
+	if err := t.sink.Record(r); err != nil {
+		logrus.WithError(err).WithField("operation", r.Operation).Error("failed to record audit")
+	}
+
+	return resp, err
+}
+
+// auditTarget splits the path of the API into the project and the path of
+// the resource in it.
+func auditTarget(u *url.URL) (project, target string) {
+	path := u.EscapedPath()
+	if i := strings.Index(path, "/api/v4/"); i >= 0 {
+		path = path[i+len("/api/v4/"):]
+	}
+
+	rest, ok := strings.CutPrefix(path, "projects/")
+	if !ok {
+		return "", path
+	}
+
+	project, target, _ = strings.Cut(rest, "/")
+	if v, err := url.PathUnescape(project); err == nil {
+		project = v
+	}
+
+	return project, target
+}
+
+// tokenID returns the fingerprint of the token the request is sent with.
+func tokenID(req *http.Request) string {
+	token := req.Header.Get("PRIVATE-TOKEN")
+	if token == "" {
+		token = strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+	}
+
+	if token == "" {
+		return ""
+	}
+
+	sum := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(sum[:6])
+}
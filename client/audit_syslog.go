@@ -0,0 +1,37 @@
+//go:build !windows && !plan9
+
+package client
+
+import (
+	"encoding/json"
+	"log/syslog"
+)
+
+// syslogSink sends each record to syslog as JSON.
+type syslogSink struct {
+	w *syslog.Writer
+}
+
+// NewSyslogAuditSink returns the sink which sends each record as JSON to
+// the local syslog daemon, with the tag and the facility of priority.
+func NewSyslogAuditSink(priority syslog.Priority, tag string) (AuditSink, error) {
+	w, err := syslog.New(priority, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	return &syslogSink{w: w}, nil
+}
+
+func (s *syslogSink) Record(r AuditRecord) error {
+	v, err := json.Marshal(&r)
+	if err != nil {
+		return err
+	}
+
+	if r.Error != "" || r.Status >= 400 {
+		return s.w.Warning(string(v))
+	}
+
+	return s.w.Info(string(v))
+}
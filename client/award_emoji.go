@@ -0,0 +1,119 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/xanzy/go-gitlab"
+)
+
+// AwardableKind is the kind of the item an award emoji is given to.
+type AwardableKind string
+
+// The kinds of the items which can be given award emoji.
+const (
+	AwardableIssue AwardableKind = "issue"
+	AwardableMR    AwardableKind = "merge_request"
+)
+
+// Awardable identifies an issue or a merge request, or a note on it when
+// NoteID is not 0, which award emoji are given to.
+type Awardable struct {
+	Kind   AwardableKind
+	IID    int
+	NoteID int
+}
+
+func (a Awardable) onNote() bool {
+	return a.NoteID != 0
+}
+
+func (a Awardable) invalid() error {
+	return fmt.Errorf("invalid kind %q of awardable", a.Kind)
+}
+
+// AddAwardEmoji gives the emoji called name, such as "eyes" or "thumbsup",
+// to the item as the user owning the token.
+func (cli *Client) AddAwardEmoji(pid interface{}, item Awardable, name string) (*gitlab.AwardEmoji, error) {
+	opts := &gitlab.CreateAwardEmojiOptions{Name: name}
+
+	var (
+		v   *gitlab.AwardEmoji
+		err error
+	)
+
+	switch {
+	case item.Kind == AwardableIssue && item.onNote():
+		v, _, err = cli.c.AwardEmoji.CreateIssuesAwardEmojiOnNote(pid, item.IID, item.NoteID, opts)
+
+	case item.Kind == AwardableIssue:
+		v, _, err = cli.c.AwardEmoji.CreateIssueAwardEmoji(pid, item.IID, opts)
+
+	case item.Kind == AwardableMR && item.onNote():
+		v, _, err = cli.c.AwardEmoji.CreateMergeRequestAwardEmojiOnNote(pid, item.IID, item.NoteID, opts)
+
+	case item.Kind == AwardableMR:
+		v, _, err = cli.c.AwardEmoji.CreateMergeRequestAwardEmoji(pid, item.IID, opts)
+
+	default:
+		err = item.invalid()
+	}
+
+	return v, err
+}
+
+// RemoveAwardEmoji removes the award emoji whose ID is awardID from the
+// item.
+func (cli *Client) RemoveAwardEmoji(pid interface{}, item Awardable, awardID int) error {
+	var err error
+
+	switch {
+	case item.Kind == AwardableIssue && item.onNote():
+		_, err = cli.c.AwardEmoji.DeleteIssuesAwardEmojiOnNote(pid, item.IID, item.NoteID, awardID)
+
+	case item.Kind == AwardableIssue:
+		_, err = cli.c.AwardEmoji.DeleteIssueAwardEmoji(pid, item.IID, awardID)
+
+	case item.Kind == AwardableMR && item.onNote():
+		_, err = cli.c.AwardEmoji.DeleteMergeRequestAwardEmojiOnNote(pid, item.IID, item.NoteID, awardID)
+
+	case item.Kind == AwardableMR:
+		_, err = cli.c.AwardEmoji.DeleteMergeRequestAwardEmoji(pid, item.IID, awardID)
+
+	default:
+		err = item.invalid()
+	}
+
+	return err
+}
+
+// ListAwardEmoji returns all the award emoji given to the item.
+func (cli *Client) ListAwardEmoji(pid interface{}, item Awardable) ([]*gitlab.AwardEmoji, error) {
+	var list PageFunc[*gitlab.AwardEmoji]
+
+	switch {
+	case item.Kind == AwardableIssue && item.onNote():
+		list = func(opts *gitlab.ListOptions) ([]*gitlab.AwardEmoji, *gitlab.Response, error) {
+			return cli.c.AwardEmoji.ListIssuesAwardEmojiOnNote(pid, item.IID, item.NoteID, (*gitlab.ListAwardEmojiOptions)(opts))
+		}
+
+	case item.Kind == AwardableIssue:
+		list = func(opts *gitlab.ListOptions) ([]*gitlab.AwardEmoji, *gitlab.Response, error) {
+			return cli.c.AwardEmoji.ListIssueAwardEmoji(pid, item.IID, (*gitlab.ListAwardEmojiOptions)(opts))
+		}
+
+	case item.Kind == AwardableMR && item.onNote():
+		list = func(opts *gitlab.ListOptions) ([]*gitlab.AwardEmoji, *gitlab.Response, error) {
+			return cli.c.AwardEmoji.ListMergeRequestAwardEmojiOnNote(pid, item.IID, item.NoteID, (*gitlab.ListAwardEmojiOptions)(opts))
+		}
+
+	case item.Kind == AwardableMR:
+		list = func(opts *gitlab.ListOptions) ([]*gitlab.AwardEmoji, *gitlab.Response, error) {
+			return cli.c.AwardEmoji.ListMergeRequestAwardEmoji(pid, item.IID, (*gitlab.ListAwardEmojiOptions)(opts))
+		}
+
+	default:
+		return nil, item.invalid()
+	}
+
+	return CollectAll(list)
+}
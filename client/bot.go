@@ -0,0 +1,27 @@
+package client
+
+// Bot is the account which the robot acts as.
+type Bot struct {
+	ID       int
+	Username string
+}
+
+// ResolveBot returns the account owning the token of cli. It is meant to
+// be called once at startup, and the result kept for the handlers.
+func ResolveBot(cli Interface) (Bot, error) {
+	u, err := cli.GetCurrentUser()
+	if err != nil {
+		return Bot{}, err
+	}
+
+	return Bot{ID: u.ID, Username: u.Username}, nil
+}
+
+// IsAuthor reports whether the event, such as a note event, is triggered by
+// the bot itself. The handlers of comments use it to avoid reacting to the
+// comments of the robot in a loop. e is one of the events AsEvent accepts.
+func (b Bot) IsAuthor(e interface{}) bool {
+	v, ok := AsEvent(e)
+
+	return ok && b.Username != "" && v.GetAuthor() == b.Username
+}
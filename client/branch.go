@@ -0,0 +1,133 @@
+package client
+
+import (
+	"github.com/xanzy/go-gitlab"
+)
+
+// GetBranch returns the branch.
+func (cli *Client) GetBranch(pid interface{}, branch string) (*gitlab.Branch, error) {
+	v, _, err := cli.c.Branches.GetBranch(pid, branch)
+
+	return v, err
+}
+
+// CreateBranch creates the branch from ref, which can be a branch, a tag
+// or a commit SHA.
+func (cli *Client) CreateBranch(pid interface{}, branch, ref string) (*gitlab.Branch, error) {
+	v, _, err := cli.c.Branches.CreateBranch(pid, &gitlab.CreateBranchOptions{
+		Branch: gitlab.Ptr(branch),
+		Ref:    gitlab.Ptr(ref),
+	})
+
+	return v, err
+}
+
+// DeleteBranch deletes the branch.
+func (cli *Client) DeleteBranch(pid interface{}, branch string) error {
+	_, err := cli.c.Branches.DeleteBranch(pid, branch)
+
+	return err
+}
+
+// ProtectBranchOptions are the options of ProtectBranch. The access
+// levels left nil default to maintainers on GitLab.
+type ProtectBranchOptions struct {
+	PushAccessLevel      *gitlab.AccessLevelValue
+	MergeAccessLevel     *gitlab.AccessLevelValue
+	UnprotectAccessLevel *gitlab.AccessLevelValue
+
+	AllowForcePush            bool
+	CodeOwnerApprovalRequired bool
+}
+
+// ProtectBranch protects the branch, which can also be a wildcard such as
+// "release-*".
+func (cli *Client) ProtectBranch(pid interface{}, branch string, opts ProtectBranchOptions) (*gitlab.ProtectedBranch, error) {
+	v, _, err := cli.c.ProtectedBranches.ProtectRepositoryBranches(
+		pid, &gitlab.ProtectRepositoryBranchesOptions{
+			Name:                      gitlab.Ptr(branch),
+			PushAccessLevel:           opts.PushAccessLevel,
+			MergeAccessLevel:          opts.MergeAccessLevel,
+			UnprotectAccessLevel:      opts.UnprotectAccessLevel,
+			AllowForcePush:            gitlab.Ptr(opts.AllowForcePush),
+			CodeOwnerApprovalRequired: gitlab.Ptr(opts.CodeOwnerApprovalRequired),
+		},
+	)
+
+	return v, err
+}
+
+// UpdateProtectedBranch updates the protection of the branch pb in place,
+// so that the branch stays protected meanwhile. The access levels of opts
+// left nil are kept, and the others replace the ones of the roles, while
+// the ones of the users, the groups and the deploy keys are kept.
+func (cli *Client) UpdateProtectedBranch(
+	pid interface{}, pb *gitlab.ProtectedBranch, opts ProtectBranchOptions,
+) (*gitlab.ProtectedBranch, error) {
+	v, _, err := cli.c.ProtectedBranches.UpdateProtectedBranch(
+		pid, pb.Name, &gitlab.UpdateProtectedBranchOptions{
+			AllowedToPush:             roleAccessUpdate(pb.PushAccessLevels, opts.PushAccessLevel),
+			AllowedToMerge:            roleAccessUpdate(pb.MergeAccessLevels, opts.MergeAccessLevel),
+			AllowedToUnprotect:        roleAccessUpdate(pb.UnprotectAccessLevels, opts.UnprotectAccessLevel),
+			AllowForcePush:            gitlab.Ptr(opts.AllowForcePush),
+			CodeOwnerApprovalRequired: gitlab.Ptr(opts.CodeOwnerApprovalRequired),
+		},
+	)
+
+	return v, err
+}
+
+// roleAccessUpdate returns the changes making level the only access level
+// of the roles, or nil if there is none to make.
+func roleAccessUpdate(
+	current []*gitlab.BranchAccessDescription, level *gitlab.AccessLevelValue,
+) *[]*gitlab.BranchPermissionOptions {
+	if level == nil {
+		return nil
+	}
+
+	var v []*gitlab.BranchPermissionOptions
+
+	found := false
+	for _, d := range current {
+		if d.UserID != 0 || d.GroupID != 0 || d.DeployKeyID != 0 {
+			continue
+		}
+
+		if d.AccessLevel == *level && !found {
+			found = true
+
+			continue
+		}
+
+		v = append(v, &gitlab.BranchPermissionOptions{ID: gitlab.Ptr(d.ID), Destroy: gitlab.Ptr(true)})
+	}
+
+	if !found {
+		v = append(v, &gitlab.BranchPermissionOptions{AccessLevel: level})
+	}
+
+	if len(v) == 0 {
+		return nil
+	}
+
+	return &v
+}
+
+// UnprotectBranch removes the protection of the branch.
+func (cli *Client) UnprotectBranch(pid interface{}, branch string) error {
+	_, err := cli.c.ProtectedBranches.UnprotectRepositoryBranches(pid, branch)
+
+	return err
+}
+
+// ListProtectedBranches returns all the protected branches of the project.
+func (cli *Client) ListProtectedBranches(pid interface{}) ([]*gitlab.ProtectedBranch, error) {
+	v := &gitlab.ListProtectedBranchesOptions{}
+
+	return CollectAll(func(opts *gitlab.ListOptions) ([]*gitlab.ProtectedBranch, *gitlab.Response, error) {
+		v.ListOptions = *opts
+
+		return cli.c.ProtectedBranches.ListProtectedBranches(pid, v)
+	})
+}
@@ -0,0 +1,76 @@
+package client
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/xanzy/go-gitlab"
+)
+
+func TestRoleAccessUpdate(t *testing.T) {
+	developer := gitlab.DeveloperPermissions
+	maintainer := gitlab.MaintainerPermissions
+
+	tests := []struct {
+		name    string
+		current []*gitlab.BranchAccessDescription
+		level   *gitlab.AccessLevelValue
+		want    string
+	}{
+		{
+			name:    "level not set",
+			current: []*gitlab.BranchAccessDescription{{ID: 1, AccessLevel: developer}},
+			want:    "null",
+		},
+		{
+			name:    "unchanged",
+			current: []*gitlab.BranchAccessDescription{{ID: 1, AccessLevel: maintainer}},
+			level:   &maintainer,
+			want:    "null",
+		},
+		{
+			name:    "changed",
+			current: []*gitlab.BranchAccessDescription{{ID: 1, AccessLevel: developer}},
+			level:   &maintainer,
+			want:    `[{"id":1,"_destroy":true},{"access_level":40}]`,
+		},
+		{
+			name: "users, groups and deploy keys kept",
+			current: []*gitlab.BranchAccessDescription{
+				{ID: 1, AccessLevel: developer, UserID: 7},
+				{ID: 2, AccessLevel: developer, GroupID: 8},
+				{ID: 3, AccessLevel: developer, DeployKeyID: 9},
+				{ID: 4, AccessLevel: developer},
+			},
+			level: &maintainer,
+			want:  `[{"id":4,"_destroy":true},{"access_level":40}]`,
+		},
+		{
+			name: "other roles removed",
+			current: []*gitlab.BranchAccessDescription{
+				{ID: 1, AccessLevel: developer},
+				{ID: 2, AccessLevel: maintainer},
+			},
+			level: &maintainer,
+			want:  `[{"id":1,"_destroy":true}]`,
+		},
+		{
+			name:  "unprotected to no one",
+			level: gitlab.Ptr(gitlab.NoPermissions),
+			want:  `[{"access_level":0}]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := json.Marshal(roleAccessUpdate(tt.current, tt.level))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(b) != tt.want {
+				t.Errorf("got %s, want %s", b, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,264 @@
+package client
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"regexp"
+	"sync"
+)
+
+// Cache stores the responses of GET requests, to send conditional requests
+// with their ETags.
+type Cache interface {
+	Get(key string) (*CachedResponse, bool)
+	Set(key string, v *CachedResponse)
+}
+
+// CachedResponse is a response stored in Cache.
+type CachedResponse struct {
+	ETag   string
+	Header http.Header
+	Body   []byte
+}
+
+// CacheOptions configures the cache of the responses.
+type CacheOptions struct {
+	// Cache stores the responses.
+	Cache Cache
+
+	// Cacheable reports whether the response of the GET request can be
+	// cached. It is DefaultCacheable if nil.
+	Cacheable func(req *http.Request) bool
+
+	// MaxBodySize caps the size of the bodies which are cached. The
+	// larger responses are streamed as they are. It is 256KB if zero.
+	MaxBodySize int64
+}
+
+// WithCache makes the client send the GET requests with If-None-Match if
+// their responses are in the cache, and serve the cached response when
+// GitLab answers 304 Not Modified, which is much cheaper for both sides
+// than transferring the response again. The responses are cached by the
+// URL, the Sudo header and the token which authenticates the request, so
+// the users of the client never see the responses of each other.
+func WithCache(opts CacheOptions) Option {
+	return func(o *options) {
+		o.cache = &opts
+	}
+}
+
+var cacheablePaths = []*regexp.Regexp{
+	regexp.MustCompile(`/projects/[^/]+(/repository/files/[^/]+(/raw)?|/members(/all)?)?$`),
+	regexp.MustCompile(`/groups/[^/]+/members(/all)?$`),
+}
+
+// DefaultCacheable reports whether req reads a project, a file of a
+// repository or the members of a project or a group, which the robots
+// read again and again while they rarely change.
+func DefaultCacheable(req *http.Request) bool {
+	p := req.URL.EscapedPath()
+
+	for _, re := range cacheablePaths {
+		if re.MatchString(p) {
+			return true
+		}
+	}
+
+	return false
+}
+
+const defaultMaxCachedBodySize = 256 << 10
+
+// newCacheTransport returns the cacheTransport on top of base, or base if
+// the cache is not configured. It must be under the transport which
+// authenticates the requests, to tell the users apart.
+func newCacheTransport(base http.RoundTripper, opts *CacheOptions) http.RoundTripper {
+	if opts == nil || opts.Cache == nil {
+		return base
+	}
+
+	t := &cacheTransport{
+		base:        base,
+		cache:       opts.Cache,
+		cacheable:   opts.Cacheable,
+		maxBodySize: opts.MaxBodySize,
+	}
+
+	if t.cacheable == nil {
+		t.cacheable = DefaultCacheable
+	}
+
+	if t.maxBodySize <= 0 {
+		t.maxBodySize = defaultMaxCachedBodySize
+	}
+
+	return t
+}
+
+type cacheTransport struct {
+	base        http.RoundTripper
+	cache       Cache
+	cacheable   func(*http.Request) bool
+	maxBodySize int64
+}
+
+func (t *cacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet || req.Header.Get("Range") != "" || !t.cacheable(req) {
+		return t.base.RoundTrip(req)
+	}
+
+	key := cacheKey(req)
+
+	cached, ok := t.cache.Get(key)
+	if ok {
+		req = req.Clone(req.Context())
+		req.Header.Set("If-None-Match", cached.ETag)
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && ok:
+		resp.Body.Close()
+
+		return cached.toResponse(req, resp.Header), nil
+
+	case resp.StatusCode == http.StatusOK && resp.Header.Get("ETag") != "" &&
+		resp.ContentLength <= t.maxBodySize:
+
+		return t.store(key, resp)
+
+	default:
+		return resp, nil
+	}
+}
+
+// cacheKey returns the key of the response of req, which tells apart the
+// users by the Sudo header and a hash of the token, so the token is not
+// kept in the cache.
+func cacheKey(req *http.Request) string {
+	token := req.Header.Get("PRIVATE-TOKEN")
+	if token == "" {
+		token = req.Header.Get("Authorization")
+	}
+
+	sum := sha256.Sum256([]byte(token))
+
+	return req.URL.String() + " " + req.Header.Get("Sudo") + " " + hex.EncodeToString(sum[:8])
+}
+
+func (t *cacheTransport) store(key string, resp *http.Response) (*http.Response, error) {
+	body, err := io.ReadAll(io.LimitReader(resp.Body, t.maxBodySize+1))
+	if err != nil {
+		resp.Body.Close()
+
+		return nil, err
+	}
+
+	if int64(len(body)) > t.maxBodySize {
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+
+		return resp, nil
+	}
+
+	resp.Body.Close()
+
+	t.cache.Set(key, &CachedResponse{
+		ETag:   resp.Header.Get("ETag"),
+		Header: resp.Header.Clone(),
+		Body:   body,
+	})
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	return resp, nil
+}
+
+// toResponse returns the cached response to req, with the headers of the
+// 304 response, such as the rate limit, replacing the cached ones.
+func (c *CachedResponse) toResponse(req *http.Request, fresh http.Header) *http.Response {
+	header := c.Header.Clone()
+	for k, v := range fresh {
+		if k != "Content-Length" {
+			header[k] = v
+		}
+	}
+
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(c.Body)),
+		ContentLength: int64(len(c.Body)),
+		Request:       req,
+	}
+}
+
+// NewMemoryCache returns a Cache in memory which keeps at most maxEntries
+// responses, evicting the least recently used ones.
+func NewMemoryCache(maxEntries int) Cache {
+	return &memoryCache{
+		maxEntries: maxEntries,
+		items:      make(map[string]*list.Element),
+		order:      list.New(),
+	}
+}
+
+type memoryCacheEntry struct {
+	key   string
+	value *CachedResponse
+}
+
+type memoryCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	items      map[string]*list.Element
+	order      *list.List
+}
+
+func (c *memoryCache) Get(key string) (*CachedResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	c.order.MoveToFront(e)
+
+	return e.Value.(*memoryCacheEntry).value, true
+}
+
+func (c *memoryCache) Set(key string, v *CachedResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.items[key]; ok {
+		e.Value.(*memoryCacheEntry).value = v
+		c.order.MoveToFront(e)
+
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&memoryCacheEntry{key: key, value: v})
+
+	if c.maxEntries > 0 && c.order.Len() > c.maxEntries {
+		e := c.order.Back()
+		c.order.Remove(e)
+		delete(c.items, e.Value.(*memoryCacheEntry).key)
+	}
+}
@@ -0,0 +1,124 @@
+package client
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestCacheTransport(t *testing.T) {
+	type get struct {
+		path   string
+		token  string
+		sudo   string
+		cached bool
+	}
+
+	tests := []struct {
+		name string
+		body string
+		gets []get
+	}{
+		{
+			name: "not modified",
+			body: "{}",
+			gets: []get{
+				{path: "/api/v4/projects/a%2Fb", token: "t"},
+				{path: "/api/v4/projects/a%2Fb", token: "t", cached: true},
+			},
+		},
+		{
+			name: "files and members",
+			body: "{}",
+			gets: []get{
+				{path: "/api/v4/projects/1/repository/files/OWNERS/raw", token: "t"},
+				{path: "/api/v4/projects/1/repository/files/OWNERS/raw", token: "t", cached: true},
+				{path: "/api/v4/groups/g/members/all", token: "t"},
+				{path: "/api/v4/groups/g/members/all", token: "t", cached: true},
+			},
+		},
+		{
+			name: "not cacheable",
+			body: "{}",
+			gets: []get{
+				{path: "/api/v4/projects/1/jobs/2/trace", token: "t"},
+				{path: "/api/v4/projects/1/jobs/2/trace", token: "t"},
+			},
+		},
+		{
+			name: "other users",
+			body: "{}",
+			gets: []get{
+				{path: "/api/v4/projects/1", token: "t"},
+				{path: "/api/v4/projects/1", token: "u"},
+				{path: "/api/v4/projects/1", token: "t", sudo: "bob"},
+				{path: "/api/v4/projects/1", token: "u", cached: true},
+			},
+		},
+		{
+			name: "too large",
+			body: strings.Repeat("x", defaultMaxCachedBodySize+1),
+			gets: []get{
+				{path: "/api/v4/projects/1", token: "t"},
+				{path: "/api/v4/projects/1", token: "t"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var conditional bool
+
+			base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				conditional = req.Header.Get("If-None-Match") == `"v1"`
+
+				resp := response(http.StatusOK)
+				resp.Header.Set("ETag", `"v1"`)
+				resp.Header.Set("RateLimit-Remaining", "99")
+				resp.ContentLength = -1
+				resp.Body = io.NopCloser(strings.NewReader(tt.body))
+
+				if conditional {
+					resp.StatusCode = http.StatusNotModified
+					resp.Header.Set("RateLimit-Remaining", "98")
+					resp.Body = io.NopCloser(strings.NewReader(""))
+				}
+
+				return resp, nil
+			})
+
+			rt := newCacheTransport(base, &CacheOptions{Cache: NewMemoryCache(10)})
+
+			for i, g := range tt.gets {
+				req, _ := http.NewRequest(http.MethodGet, "https://gitlab.com"+g.path, nil)
+				req.Header.Set("PRIVATE-TOKEN", g.token)
+				if g.sudo != "" {
+					req.Header.Set("Sudo", g.sudo)
+				}
+
+				resp, err := rt.RoundTrip(req)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				body, _ := io.ReadAll(resp.Body)
+				resp.Body.Close()
+
+				if conditional != g.cached {
+					t.Errorf("get %d: got conditional %v, want %v", i, conditional, g.cached)
+				}
+
+				if resp.StatusCode != http.StatusOK || string(body) != tt.body {
+					t.Errorf("get %d: got %d with %d bytes, want 200 with %d bytes",
+						i, resp.StatusCode, len(body), len(tt.body))
+				}
+
+				if g.cached && resp.Header.Get("RateLimit-Remaining") != "98" {
+					t.Errorf("get %d: got the rate limit %q of the cached response",
+						i, resp.Header.Get("RateLimit-Remaining"))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	defaultCircuitFailures = 5
+	defaultCircuitCoolDown = 30 * time.Second
+)
+
+// ErrCircuitOpen is returned without sending the request while GitLab is
+// considered down by the circuit breaker.
+var ErrCircuitOpen = errors.New("circuit breaker is open, gitlab is unavailable")
+
+// CircuitBreakerOptions configure the circuit breaker of the client.
+type CircuitBreakerOptions struct {
+	// Failures is how many requests in a row, after the retries, must
+	// fail by 5xx or a network error for the circuit to open. It is 5 if
+	// zero.
+	Failures int
+
+	// CoolDown is how long the requests fail fast once the circuit opens.
+	// A single request is sent after it, which closes the circuit if it
+	// succeeds and opens it again otherwise. It is 30 seconds if zero.
+	CoolDown time.Duration
+
+	// OnStateChange, if not nil, is called when the circuit opens or
+	// closes.
+	OnStateChange func(open bool)
+}
+
+// WithCircuitBreaker makes the client fail fast by ErrCircuitOpen for a
+// while when GitLab keeps failing, instead of sending more requests to it.
+func WithCircuitBreaker(opts CircuitBreakerOptions) Option {
+	return func(o *options) {
+		o.circuitBreaker = &opts
+	}
+}
+
+// CircuitOpen reports whether the requests fail fast by ErrCircuitOpen
+// now. It is always false without WithCircuitBreaker.
+func (cli *Client) CircuitOpen() bool {
+	return cli.breaker != nil && cli.breaker.isOpen()
+}
+
+// circuitBreaker fails the requests fast while open.
+type circuitBreaker struct {
+	base http.RoundTripper
+	opts CircuitBreakerOptions
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func newCircuitBreaker(base http.RoundTripper, opts CircuitBreakerOptions) *circuitBreaker {
+	if opts.Failures <= 0 {
+		opts.Failures = defaultCircuitFailures
+	}
+
+	if opts.CoolDown <= 0 {
+		opts.CoolDown = defaultCircuitCoolDown
+	}
+
+	return &circuitBreaker{base: base, opts: opts}
+}
+
+func (b *circuitBreaker) isOpen() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return !b.openedAt.IsZero() && (b.probing || time.Since(b.openedAt) < b.opts.CoolDown)
+}
+
+func (b *circuitBreaker) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := b.allow(); err != nil {
+		return nil, err
+	}
+
+	resp, err := b.base.RoundTrip(req)
+
+	switch {
+	case err != nil && req.Context().Err() != nil:
+		// Canceled by the caller, which says nothing about GitLab.
+		b.release()
+
+	case err != nil || resp.StatusCode >= http.StatusInternalServerError:
+		b.fail()
+
+	default:
+		b.succeed()
+	}
+
+	return resp, err
+}
+
+// allow returns ErrCircuitOpen if the request should fail fast. Only one
+// request is allowed when the cool-down ends.
+func (b *circuitBreaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.openedAt.IsZero() {
+		return nil
+	}
+
+	if b.probing || time.Since(b.openedAt) < b.opts.CoolDown {
+		return ErrCircuitOpen
+	}
+
+	b.probing = true
+
+	return nil
+}
+
+func (b *circuitBreaker) release() {
+	b.mu.Lock()
+	b.probing = false
+	b.mu.Unlock()
+}
+
+func (b *circuitBreaker) fail() {
+	b.mu.Lock()
+
+	b.failures++
+
+	opened := b.openedAt.IsZero() && b.failures >= b.opts.Failures
+	if opened || b.probing {
+		b.openedAt = time.Now()
+		b.probing = false
+	}
+
+	b.mu.Unlock()
+
+	if opened {
+		b.notify(true)
+	}
+}
+
+func (b *circuitBreaker) succeed() {
+	b.mu.Lock()
+
+	closed := !b.openedAt.IsZero()
+
+	b.failures = 0
+	b.openedAt = time.Time{}
+	b.probing = false
+
+	b.mu.Unlock()
+
+	if closed {
+		b.notify(false)
+	}
+}
+
+func (b *circuitBreaker) notify(open bool) {
+	if b.opts.OnStateChange != nil {
+		b.opts.OnStateChange(open)
+	}
+}
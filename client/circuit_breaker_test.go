@@ -0,0 +1,119 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	errReset := errors.New("connection reset")
+
+	// Each step is the result of a request: a status code, 0 for a
+	// network error, or -1 for the request canceled by the caller.
+	tests := []struct {
+		name     string
+		steps    []int
+		cooled   bool
+		wantSent int
+		wantOpen bool
+		changes  []bool
+	}{
+		{name: "successes", steps: []int{200, 404, 200}, wantSent: 3},
+		{name: "below the failures", steps: []int{500, 0}, wantSent: 2},
+		{name: "reset by a success", steps: []int{500, 502, 200, 500, 0}, wantSent: 5},
+		{name: "open", steps: []int{500, 502, 0, 200, 200}, wantSent: 3, wantOpen: true, changes: []bool{true}},
+		{name: "cancellation ignored", steps: []int{500, -1, 502, -1, 200}, wantSent: 5},
+		{name: "probe succeeds", steps: []int{500, 502, 503, 200, 200}, cooled: true, wantSent: 5, changes: []bool{true, false}},
+		{name: "probe fails", steps: []int{500, 502, 503, 500}, cooled: true, wantSent: 4, wantOpen: true, changes: []bool{true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var changes []bool
+
+			coolDown := time.Hour
+			if tt.cooled {
+				coolDown = time.Nanosecond
+			}
+
+			sent, step := 0, 0
+			b := newCircuitBreaker(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				sent++
+
+				switch code := tt.steps[step]; code {
+				case 0:
+					return nil, errReset
+				case -1:
+					return nil, req.Context().Err()
+				default:
+					return response(code), nil
+				}
+			}), CircuitBreakerOptions{
+				Failures:      3,
+				CoolDown:      coolDown,
+				OnStateChange: func(open bool) { changes = append(changes, open) },
+			})
+
+			for step = range tt.steps {
+				ctx, cancel := context.WithCancel(context.Background())
+				if tt.steps[step] == -1 {
+					cancel()
+				}
+
+				req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://gitlab.example.com/api/v4/projects", nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				resp, err := b.RoundTrip(req)
+				if err == nil {
+					resp.Body.Close()
+				}
+
+				cancel()
+			}
+
+			if sent != tt.wantSent {
+				t.Errorf("got %d requests sent, want %d", sent, tt.wantSent)
+			}
+
+			// The circuit opened again by a failed probe must not have
+			// cooled down yet.
+			b.opts.CoolDown = time.Hour
+
+			if open := b.isOpen(); open != tt.wantOpen {
+				t.Errorf("got open %v, want %v", open, tt.wantOpen)
+			}
+
+			if !slices.Equal(changes, tt.changes) {
+				t.Errorf("got state changes %v, want %v", changes, tt.changes)
+			}
+		})
+	}
+}
+
+func TestCircuitBreakerFailsFast(t *testing.T) {
+	b := newCircuitBreaker(roundTripFunc(func(*http.Request) (*http.Response, error) {
+		return response(http.StatusServiceUnavailable), nil
+	}), CircuitBreakerOptions{Failures: 1, CoolDown: time.Hour})
+
+	for i, want := range []error{nil, ErrCircuitOpen, ErrCircuitOpen} {
+		req, err := http.NewRequest(http.MethodGet, "https://gitlab.example.com/api/v4/projects", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp, err := b.RoundTrip(req)
+		if err == nil {
+			resp.Body.Close()
+		}
+
+		if !errors.Is(err, want) {
+			t.Errorf("request %d: got %v, want %v", i, err, want)
+		}
+	}
+}
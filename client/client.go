@@ -0,0 +1,142 @@
+// Package client wraps go-gitlab with the operations robots commonly need.
+//
+// Every method that takes a pid accepts either the numeric ID of a project
+// or its full path, such as "group/project", the same as go-gitlab does.
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/xanzy/go-gitlab"
+)
+
+// Client is the GitLab API client used by robots.
+type Client struct {
+	c *gitlab.Client
+
+	rateLimiter *rateLimitTransport
+	breaker     *circuitBreaker
+
+	// perms is nil if the permission cache is not enabled.
+	perms *permissionCache
+}
+
+// NewClient creates a client for the GitLab instance at host which
+// authenticates with the token returned by getToken. getToken is called
+// on every request, so that the token can be rotated, such as by
+// TokenFile, except that the first token of the pool is the one returned
+// on creating the client if WithTokenPool is set.
+func NewClient(getToken func() []byte, host string, opts ...Option) (*Client, error) {
+	o := newOptions(opts)
+
+	token := string(getToken())
+
+	base := newCacheTransport(o.transport.newTransport(), o.cache)
+	if len(o.poolTokens) > 0 {
+		pool, err := newTokenPoolTransport(
+			base, append([]string{token}, o.poolTokens...), o.rotation,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		base = pool
+	} else {
+		base = &tokenTransport{base: base, getToken: getToken}
+	}
+
+	return newClient(base, o, func(hc *http.Client) (*gitlab.Client, error) {
+		return gitlab.NewClient(
+			token, gitlab.WithBaseURL(host), gitlab.WithHTTPClient(hc), gitlab.WithoutRetries(),
+		)
+	})
+}
+
+// newClient chains the transports configured by o on top of base, which
+// authenticates the requests, and creates the go-gitlab client with it.
+// The transports do the retries, so create should disable the ones of
+// go-gitlab.
+func newClient(
+	base http.RoundTripper, o *options, create func(*http.Client) (*gitlab.Client, error),
+) (*Client, error) {
+	if o.metrics != nil {
+		m, err := newClientMetrics(o.metrics)
+		if err != nil {
+			return nil, err
+		}
+
+		base = &metricsTransport{base: base, metrics: m}
+	}
+
+	var breaker *circuitBreaker
+
+	base = newRetryTransport(base, o.retry)
+	if o.circuitBreaker != nil {
+		breaker = newCircuitBreaker(base, *o.circuitBreaker)
+		base = breaker
+	}
+
+	rl := newRateLimitTransport(base, o.rateLimit)
+
+	var transport http.RoundTripper = rl
+	if o.dryRun != nil {
+		transport = &dryRunTransport{base: transport, log: o.dryRun}
+	}
+
+	if o.audit != nil {
+		transport = &auditTransport{base: transport, sink: o.audit, dryRun: o.dryRun != nil}
+	}
+
+	c, err := create(&http.Client{
+		Transport: transport,
+		Timeout:   o.transport.Timeout,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	cli := &Client{c: c, rateLimiter: rl, breaker: breaker}
+	if o.permissionTTL > 0 {
+		cli.perms = newPermissionCache(o.permissionTTL)
+	}
+
+	return cli, nil
+}
+
+// optional returns a pointer to v, or nil if v is the zero value, which
+// lets go-gitlab omit the option from the request.
+func optional[T comparable](v T) *T {
+	var zero T
+	if v == zero {
+		return nil
+	}
+
+	return &v
+}
+
+// labelOptions returns the labels as the option of the list APIs, or nil
+// if there are none.
+func labelOptions(labels []string) *gitlab.LabelOptions {
+	if len(labels) == 0 {
+		return nil
+	}
+
+	v := gitlab.LabelOptions(labels)
+
+	return &v
+}
+
+// projectPath returns the escaped form of pid used in the URL of the APIs
+// which go-gitlab doesn't cover.
+func projectPath(pid interface{}) (string, error) {
+	switch v := pid.(type) {
+	case int:
+		return strconv.Itoa(v), nil
+	case string:
+		return gitlab.PathEscape(v), nil
+	default:
+		return "", fmt.Errorf("invalid project id %#v, it must be an int or a string", pid)
+	}
+}
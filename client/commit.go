@@ -0,0 +1,140 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/xanzy/go-gitlab"
+)
+
+// MRCommit is a commit of a merge request.
+type MRCommit struct {
+	SHA            string
+	AuthorName     string
+	AuthorEmail    string
+	CommitterName  string
+	CommitterEmail string
+	Message        string
+}
+
+// ListMRCommits returns all the commits of the merge request.
+func (cli *Client) ListMRCommits(pid interface{}, iid int) ([]MRCommit, error) {
+	v, err := CollectAll(func(opts *gitlab.ListOptions) ([]*gitlab.Commit, *gitlab.Response, error) {
+		return cli.c.MergeRequests.GetMergeRequestCommits(
+			pid, iid, (*gitlab.GetMergeRequestCommitsOptions)(opts),
+		)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]MRCommit, len(v))
+	for i, c := range v {
+		r[i] = MRCommit{
+			SHA:            c.ID,
+			AuthorName:     c.AuthorName,
+			AuthorEmail:    c.AuthorEmail,
+			CommitterName:  c.CommitterName,
+			CommitterEmail: c.CommitterEmail,
+			Message:        c.Message,
+		}
+	}
+
+	return r, nil
+}
+
+// SetCommitStatus reports the state of the external check called name on
+// the commit. The state should be one of pending, running, success, failed
+// and canceled.
+func (cli *Client) SetCommitStatus(
+	pid interface{}, sha string, state gitlab.BuildStateValue,
+	name, targetURL, description string,
+) error {
+	_, _, err := cli.c.Commits.SetCommitStatus(pid, sha, &gitlab.SetCommitStatusOptions{
+		State:       state,
+		Name:        gitlab.Ptr(name),
+		TargetURL:   optional(targetURL),
+		Description: optional(description),
+	})
+
+	return err
+}
+
+// GetCommitStatuses returns all the statuses reported on the commit.
+func (cli *Client) GetCommitStatuses(pid interface{}, sha string) ([]*gitlab.CommitStatus, error) {
+	v := &gitlab.GetCommitStatusesOptions{All: gitlab.Ptr(true)}
+
+	return CollectAll(func(opts *gitlab.ListOptions) ([]*gitlab.CommitStatus, *gitlab.Response, error) {
+		v.ListOptions = *opts
+
+		return cli.c.Commits.GetCommitStatuses(pid, sha, v)
+	})
+}
+
+var (
+	// ErrCommitConflict is returned by CherryPickCommit and RevertCommit
+	// when the commit can't be applied to the branch without conflicts.
+	ErrCommitConflict = errors.New("commit conflicts with the branch")
+
+	// ErrCommitEmpty is returned by CherryPickCommit and RevertCommit when
+	// applying the commit changes nothing, for example because the branch
+	// has it, or has reverted it, already.
+	ErrCommitEmpty = errors.New("commit changes nothing on the branch")
+)
+
+// CherryPickCommit applies the commit to the target branch and returns the
+// new commit. The error wraps ErrCommitConflict or ErrCommitEmpty if the
+// commit can't be applied.
+func (cli *Client) CherryPickCommit(pid interface{}, sha, targetBranch string) (*gitlab.Commit, error) {
+	v, _, err := cli.c.Commits.CherryPickCommit(pid, sha, &gitlab.CherryPickCommitOptions{
+		Branch: gitlab.Ptr(targetBranch),
+	})
+	if err != nil {
+		return nil, classifyCommitError(err)
+	}
+
+	return v, nil
+}
+
+// classifyCommitError wraps err by the error_code GitLab reports when it
+// fails to cherry-pick or revert a commit.
+func classifyCommitError(err error) error {
+	var resp *gitlab.ErrorResponse
+	if !errors.As(err, &resp) || resp.Response.StatusCode != http.StatusBadRequest {
+		return err
+	}
+
+	var body struct {
+		ErrorCode string `json:"error_code"`
+	}
+	if json.Unmarshal(resp.Body, &body) != nil {
+		return err
+	}
+
+	switch body.ErrorCode {
+	case "conflict":
+		return fmt.Errorf("%w: %w", ErrCommitConflict, err)
+
+	case "empty":
+		return fmt.Errorf("%w: %w", ErrCommitEmpty, err)
+
+	default:
+		return err
+	}
+}
+
+// RevertCommit commits the revert of the commit to the branch and returns
+// the new commit. The error wraps ErrCommitConflict or ErrCommitEmpty if
+// the commit can't be reverted.
+func (cli *Client) RevertCommit(pid interface{}, sha, branch string) (*gitlab.Commit, error) {
+	v, _, err := cli.c.Commits.RevertCommit(pid, sha, &gitlab.RevertCommitOptions{
+		Branch: gitlab.Ptr(branch),
+	})
+	if err != nil {
+		return nil, classifyCommitError(err)
+	}
+
+	return v, nil
+}
@@ -0,0 +1,104 @@
+package client
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/xanzy/go-gitlab"
+)
+
+// ChangedFile describes a file changed by a merge request.
+type ChangedFile struct {
+	// Path is the path of the file after the change. It is the old path
+	// for a deleted file.
+	Path    string
+	OldPath string
+
+	Added   bool
+	Deleted bool
+	Renamed bool
+
+	AddedLines   int
+	RemovedLines int
+}
+
+// GetMRChangedFiles returns all the files changed by the merge request.
+func (cli *Client) GetMRChangedFiles(pid interface{}, iid int) ([]ChangedFile, error) {
+	diffs, err := cli.listMRDiffs(pid, iid)
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]ChangedFile, len(diffs))
+	for i, d := range diffs {
+		r[i] = toChangedFile(d)
+	}
+
+	return r, nil
+}
+
+// listMRDiffs lists the diffs by the diffs API and falls back to the
+// deprecated changes API on the instances which don't support the former.
+func (cli *Client) listMRDiffs(pid interface{}, iid int) ([]*gitlab.MergeRequestDiff, error) {
+	unsupported := false
+
+	r, err := CollectAll(func(opts *gitlab.ListOptions) ([]*gitlab.MergeRequestDiff, *gitlab.Response, error) {
+		v, resp, err := cli.c.MergeRequests.ListMergeRequestDiffs(
+			pid, iid, &gitlab.ListMergeRequestDiffsOptions{ListOptions: *opts},
+		)
+
+		unsupported = err != nil && opts.Page == 1 &&
+			resp != nil && resp.StatusCode == http.StatusNotFound
+
+		return v, resp, err
+	})
+	if unsupported {
+		return cli.getMRChanges(pid, iid)
+	}
+
+	return r, err
+}
+
+func (cli *Client) getMRChanges(pid interface{}, iid int) ([]*gitlab.MergeRequestDiff, error) {
+	mr, _, err := cli.c.MergeRequests.GetMergeRequestChanges(
+		pid, iid, &gitlab.GetMergeRequestChangesOptions{AccessRawDiffs: gitlab.Ptr(true)},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return mr.Changes, nil
+}
+
+func toChangedFile(d *gitlab.MergeRequestDiff) ChangedFile {
+	r := ChangedFile{
+		Path:    d.NewPath,
+		OldPath: d.OldPath,
+		Added:   d.NewFile,
+		Deleted: d.DeletedFile,
+		Renamed: d.RenamedFile,
+	}
+
+	if d.DeletedFile {
+		r.Path = d.OldPath
+	}
+
+	r.AddedLines, r.RemovedLines = countDiffLines(d.Diff)
+
+	return r
+}
+
+// countDiffLines counts the added and removed lines of a diff returned by
+// GitLab, which contains the hunks only, without the file headers.
+func countDiffLines(diff string) (added, removed int) {
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "+"):
+			added++
+		case strings.HasPrefix(line, "-"):
+			removed++
+		}
+	}
+
+	return
+}
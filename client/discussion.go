@@ -0,0 +1,102 @@
+package client
+
+import (
+	"github.com/xanzy/go-gitlab"
+)
+
+// DiffPosition locates an inline comment on the diff of a merge request.
+// Set NewLine to comment on an added or unchanged line, and OldLine to
+// comment on a removed line. Both are set for an unchanged line.
+type DiffPosition struct {
+	// OldPath defaults to NewPath.
+	OldPath string
+	NewPath string
+
+	OldLine int
+	NewLine int
+}
+
+// CreateMRDiscussion starts a thread on the merge request. The thread is
+// an inline comment on the latest diff of the merge request if pos is not
+// nil.
+func (cli *Client) CreateMRDiscussion(pid interface{}, iid int, body string, pos *DiffPosition) (*gitlab.Discussion, error) {
+	opts := &gitlab.CreateMergeRequestDiscussionOptions{Body: gitlab.Ptr(body)}
+
+	if pos != nil {
+		mr, _, err := cli.c.MergeRequests.GetMergeRequest(pid, iid, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		oldPath := pos.OldPath
+		if oldPath == "" {
+			oldPath = pos.NewPath
+		}
+
+		opts.Position = &gitlab.PositionOptions{
+			BaseSHA:      gitlab.Ptr(mr.DiffRefs.BaseSha),
+			HeadSHA:      gitlab.Ptr(mr.DiffRefs.HeadSha),
+			StartSHA:     gitlab.Ptr(mr.DiffRefs.StartSha),
+			NewPath:      gitlab.Ptr(pos.NewPath),
+			OldPath:      gitlab.Ptr(oldPath),
+			PositionType: gitlab.Ptr("text"),
+			NewLine:      optional(pos.NewLine),
+			OldLine:      optional(pos.OldLine),
+		}
+	}
+
+	v, _, err := cli.c.Discussions.CreateMergeRequestDiscussion(pid, iid, opts)
+
+	return v, err
+}
+
+// ReplyToDiscussion adds a note to the thread of the merge request.
+func (cli *Client) ReplyToDiscussion(pid interface{}, iid int, discussionID, body string) (*gitlab.Note, error) {
+	v, _, err := cli.c.Discussions.AddMergeRequestDiscussionNote(
+		pid, iid, discussionID,
+		&gitlab.AddMergeRequestDiscussionNoteOptions{Body: gitlab.Ptr(body)},
+	)
+
+	return v, err
+}
+
+// ResolveDiscussion resolves the thread of the merge request, or unresolves
+// it if resolved is false.
+func (cli *Client) ResolveDiscussion(pid interface{}, iid int, discussionID string, resolved bool) error {
+	_, _, err := cli.c.Discussions.ResolveMergeRequestDiscussion(
+		pid, iid, discussionID,
+		&gitlab.ResolveMergeRequestDiscussionOptions{Resolved: gitlab.Ptr(resolved)},
+	)
+
+	return err
+}
+
+// LockIssueDiscussion locks the discussion of the issue, so that only the
+// project members can comment on it.
+func (cli *Client) LockIssueDiscussion(pid interface{}, iid int) error {
+	return cli.updateIssue(pid, iid, &gitlab.UpdateIssueOptions{
+		DiscussionLocked: gitlab.Ptr(true),
+	})
+}
+
+// UnlockIssueDiscussion unlocks the discussion of the issue.
+func (cli *Client) UnlockIssueDiscussion(pid interface{}, iid int) error {
+	return cli.updateIssue(pid, iid, &gitlab.UpdateIssueOptions{
+		DiscussionLocked: gitlab.Ptr(false),
+	})
+}
+
+// LockMRDiscussion locks the discussion of the merge request, so that only
+// the project members can comment on it.
+func (cli *Client) LockMRDiscussion(pid interface{}, iid int) error {
+	return cli.updateMR(pid, iid, &gitlab.UpdateMergeRequestOptions{
+		DiscussionLocked: gitlab.Ptr(true),
+	})
+}
+
+// UnlockMRDiscussion unlocks the discussion of the merge request.
+func (cli *Client) UnlockMRDiscussion(pid interface{}, iid int) error {
+	return cli.updateMR(pid, iid, &gitlab.UpdateMergeRequestOptions{
+		DiscussionLocked: gitlab.Ptr(false),
+	})
+}
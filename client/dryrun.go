@@ -0,0 +1,162 @@
+package client
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// WithDryRun makes the client log the requests which would change anything
+// on GitLab instead of sending them, and pretend they succeed. The methods
+// return empty results for such requests. The requests reading GitLab are
+// sent as usual. The standard logger of logrus is used if log is nil.
+func WithDryRun(log *logrus.Entry) Option {
+	return func(o *options) {
+		if log == nil {
+			log = logrus.NewEntry(logrus.StandardLogger())
+		}
+
+		o.dryRun = log
+	}
+}
+
+// dryRunTransport answers the mutating requests without sending them.
+type dryRunTransport struct {
+	base http.RoundTripper
+	log  *logrus.Entry
+}
+
+func (t *dryRunTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	mutating, err := isMutating(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if !mutating {
+		return t.base.RoundTrip(req)
+	}
+
+	if req.Body != nil {
+		req.Body.Close()
+	}
+
+	t.log.WithFields(logrus.Fields{
+		"method": req.Method,
+		"url":    req.URL.String(),
+	}).Info("dry run, skip the request")
+
+	status, body := http.StatusOK, "{}"
+
+	switch {
+	case req.Method == http.MethodPost && isGraphQL(req):
+		body = `{"data":null}`
+
+	case req.Method == http.MethodPost:
+		status = http.StatusCreated
+
+	case req.Method == http.MethodDelete:
+		status, body = http.StatusNoContent, ""
+	}
+
+	return &http.Response{
+		Status:        http.StatusText(status),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {"application/json"}},
+		Body:          io.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// isMutating reports whether the request may change anything on GitLab.
+// The GraphQL requests are mutating only if their documents have any
+// mutation operations, whichever of them is run.
+func isMutating(req *http.Request) (bool, error) {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false, nil
+	}
+
+	if !isGraphQL(req) || req.GetBody == nil {
+		return true, nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return false, err
+	}
+	defer body.Close()
+
+	var v graphQLRequest
+	if err := json.NewDecoder(body).Decode(&v); err != nil {
+		return true, nil
+	}
+
+	return hasMutation(v.Query), nil
+}
+
+// hasMutation reports whether the GraphQL document has a mutation
+// operation, which is the keyword mutation at the top level out of the
+// comments and the strings. It may report a fragment or an operation
+// named mutation as well, which errs on the side of not sending it.
+func hasMutation(doc string) bool {
+	depth := 0
+
+	for i := 0; i < len(doc); i++ {
+		switch c := doc[i]; {
+		case c == '#':
+			for i < len(doc) && doc[i] != '\n' && doc[i] != '\r' {
+				i++
+			}
+
+		case strings.HasPrefix(doc[i:], `"""`):
+			end := strings.Index(doc[i+3:], `"""`)
+			if end < 0 {
+				return false
+			}
+
+			i += end + 5
+
+		case c == '"':
+			for i++; i < len(doc) && doc[i] != '"' && doc[i] != '\n'; i++ {
+				if doc[i] == '\\' {
+					i++
+				}
+			}
+
+		case c == '{':
+			depth++
+
+		case c == '}':
+			depth--
+
+		case isNameStart(c):
+			j := i + 1
+			for j < len(doc) && (isNameStart(doc[j]) || '0' <= doc[j] && doc[j] <= '9') {
+				j++
+			}
+
+			if depth == 0 && doc[i:j] == "mutation" {
+				return true
+			}
+
+			i = j - 1
+		}
+	}
+
+	return false
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+func isGraphQL(req *http.Request) bool {
+	return strings.HasSuffix(req.URL.Path, "/api/graphql")
+}
@@ -0,0 +1,64 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestIsMutating(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		query  string
+		want   bool
+	}{
+		{name: "get", method: http.MethodGet, path: "/api/v4/projects", want: false},
+		{name: "post", method: http.MethodPost, path: "/api/v4/projects/1/issues", want: true},
+		{name: "put", method: http.MethodPut, path: "/api/v4/projects/1/issues/1", want: true},
+		{name: "delete", method: http.MethodDelete, path: "/api/v4/projects/1/labels/1", want: true},
+		{name: "query", query: `query { currentUser { username } }`, want: false},
+		{name: "shorthand query", query: `{ project(fullPath: "a/b") { id } }`, want: false},
+		{name: "mutation", query: `mutation { createNote(input: {}) { errors } }`, want: true},
+		{name: "named mutation", query: `mutation CreateNote($body: String!) { createNote(input: {body: $body}) { errors } }`, want: true},
+		{name: "leading comment", query: "# create the note\nmutation { createNote(input: {}) { errors } }", want: true},
+		{name: "leading fragment", query: "fragment F on Note { id }\nmutation { createNote(input: {}) { note { ...F } } }", want: true},
+		{name: "query then mutation", query: "query Q { currentUser { id } }\nmutation M { createNote(input: {}) { errors } }", want: true},
+		{name: "mutation in comment", query: "# mutation { x }\nquery { currentUser { id } }", want: false},
+		{name: "mutation in string", query: `query { project(fullPath: "mutation") { id } }`, want: false},
+		{name: "mutation in escaped string", query: `query { project(fullPath: "a\" mutation") { id } }`, want: false},
+		{name: "mutation in block string", query: `query { project(fullPath: """ mutation """) { id } }`, want: false},
+		{name: "mutation field", query: `query { mutation { id } }`, want: false},
+		{name: "mutation prefix", query: `query mutations { currentUser { id } }`, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method, path := tt.method, tt.path
+			if method == "" {
+				method, path = http.MethodPost, "/api/graphql"
+			}
+
+			b, err := json.Marshal(graphQLRequest{Query: tt.query})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			req, err := http.NewRequest(method, "https://gitlab.example.com"+path, bytes.NewReader(b))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := isMutating(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
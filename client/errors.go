@@ -0,0 +1,143 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+
+	"github.com/xanzy/go-gitlab"
+)
+
+// ErrorClass is the class of the failure of a call, which tells the
+// handlers how to react to it, such as retrying the transient ones later.
+type ErrorClass int
+
+const (
+	// ErrorUnknown is any other failure, such as a bad request.
+	ErrorUnknown ErrorClass = iota
+
+	// ErrorNotFound means the project, the user or the other resource
+	// doesn't exist, or is invisible to the token.
+	ErrorNotFound
+
+	// ErrorForbidden means the token is invalid or not allowed to do it.
+	ErrorForbidden
+
+	// ErrorConflict means the resource is in a state which doesn't allow
+	// it, such as a merge request having conflicts.
+	ErrorConflict
+
+	// ErrorRateLimited means the rate limit was exceeded, even after the
+	// retries of the client.
+	ErrorRateLimited
+
+	// ErrorTransient means GitLab failed or was unreachable, and it may
+	// succeed later.
+	ErrorTransient
+)
+
+func (c ErrorClass) String() string {
+	switch c {
+	case ErrorNotFound:
+		return "not found"
+	case ErrorForbidden:
+		return "forbidden"
+	case ErrorConflict:
+		return "conflict"
+	case ErrorRateLimited:
+		return "rate limited"
+	case ErrorTransient:
+		return "transient"
+	default:
+		return "unknown"
+	}
+}
+
+// ClassOf returns the class of err returned by the client. It is
+// ErrorUnknown if err is nil.
+func ClassOf(err error) ErrorClass {
+	switch {
+	case err == nil:
+		return ErrorUnknown
+
+	case errors.Is(err, gitlab.ErrNotFound), errors.Is(err, ErrUserNotFound):
+		return ErrorNotFound
+
+	case errors.Is(err, ErrTagProtected):
+		return ErrorForbidden
+
+	case errors.Is(err, ErrMRConflict), errors.Is(err, ErrCommitConflict),
+		errors.Is(err, ErrLabelsConflict):
+		return ErrorConflict
+
+	case errors.Is(err, ErrCircuitOpen):
+		return ErrorTransient
+	}
+
+	var resp *gitlab.ErrorResponse
+	if errors.As(err, &resp) && resp.Response != nil {
+		return classOfStatus(resp.Response.StatusCode)
+	}
+
+	if errors.Is(err, context.Canceled) {
+		return ErrorUnknown
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, context.DeadlineExceeded) {
+
+		return ErrorTransient
+	}
+
+	return ErrorUnknown
+}
+
+func classOfStatus(code int) ErrorClass {
+	switch {
+	case code == http.StatusNotFound:
+		return ErrorNotFound
+
+	case code == http.StatusUnauthorized, code == http.StatusForbidden:
+		return ErrorForbidden
+
+	case code == http.StatusConflict, code == http.StatusPreconditionFailed:
+		return ErrorConflict
+
+	case code == http.StatusTooManyRequests:
+		return ErrorRateLimited
+
+	case code >= http.StatusInternalServerError:
+		return ErrorTransient
+
+	default:
+		return ErrorUnknown
+	}
+}
+
+// IsNotFound reports whether err is of ErrorNotFound.
+func IsNotFound(err error) bool {
+	return ClassOf(err) == ErrorNotFound
+}
+
+// IsForbidden reports whether err is of ErrorForbidden.
+func IsForbidden(err error) bool {
+	return ClassOf(err) == ErrorForbidden
+}
+
+// IsConflict reports whether err is of ErrorConflict.
+func IsConflict(err error) bool {
+	return ClassOf(err) == ErrorConflict
+}
+
+// IsRateLimited reports whether err is of ErrorRateLimited.
+func IsRateLimited(err error) bool {
+	return ClassOf(err) == ErrorRateLimited
+}
+
+// IsTransient reports whether err is of ErrorTransient.
+func IsTransient(err error) bool {
+	return ClassOf(err) == ErrorTransient
+}
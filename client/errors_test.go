@@ -0,0 +1,55 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/xanzy/go-gitlab"
+)
+
+func errorResponse(code int) error {
+	return &gitlab.ErrorResponse{Response: &http.Response{StatusCode: code}}
+}
+
+func TestClassOf(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want ErrorClass
+	}{
+		{name: "nil", err: nil, want: ErrorUnknown},
+		{name: "not found", err: gitlab.ErrNotFound, want: ErrorNotFound},
+		{name: "user not found", err: fmt.Errorf("find alice: %w", ErrUserNotFound), want: ErrorNotFound},
+		{name: "tag protected", err: ErrTagProtected, want: ErrorForbidden},
+		{name: "mr conflict", err: ErrMRConflict, want: ErrorConflict},
+		{name: "commit conflict", err: ErrCommitConflict, want: ErrorConflict},
+		{name: "circuit open", err: ErrCircuitOpen, want: ErrorTransient},
+		{name: "404", err: errorResponse(404), want: ErrorNotFound},
+		{name: "401", err: errorResponse(401), want: ErrorForbidden},
+		{name: "403", err: errorResponse(403), want: ErrorForbidden},
+		{name: "409", err: errorResponse(409), want: ErrorConflict},
+		{name: "412", err: errorResponse(412), want: ErrorConflict},
+		{name: "429", err: errorResponse(429), want: ErrorRateLimited},
+		{name: "500", err: errorResponse(500), want: ErrorTransient},
+		{name: "503 wrapped", err: fmt.Errorf("update: %w", errorResponse(503)), want: ErrorTransient},
+		{name: "400", err: errorResponse(400), want: ErrorUnknown},
+		{name: "canceled", err: context.Canceled, want: ErrorUnknown},
+		{name: "deadline", err: context.DeadlineExceeded, want: ErrorTransient},
+		{name: "network", err: &net.OpError{Op: "dial", Err: errors.New("refused")}, want: ErrorTransient},
+		{name: "unexpected eof", err: io.ErrUnexpectedEOF, want: ErrorTransient},
+		{name: "other", err: errors.New("other"), want: ErrorUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ClassOf(tt.err); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
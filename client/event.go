@@ -0,0 +1,134 @@
+package client
+
+import (
+	"strings"
+
+	"github.com/xanzy/go-gitlab"
+)
+
+// Event is the common information of the webhook events, so that the code
+// shared by the handlers of several kinds of events can be written once.
+type Event interface {
+	// GetOrg returns the full path of the namespace of the project, such
+	// as "group/subgroup", and GetRepo returns the path of the project in
+	// it.
+	GetOrg() string
+	GetRepo() string
+	GetProjectID() int
+
+	// GetAuthor returns the username of the user who triggered the event.
+	GetAuthor() string
+
+	// GetAction returns what happened, such as "open" for a merge request
+	// event. It is "push" and "tag_push" for the push and the tag events,
+	// and the status of the pipeline for a pipeline event.
+	GetAction() string
+
+	// GetHTMLURL returns the web URL of the object of the event. It is the
+	// URL of the head commit for a push event and of the tag for a tag
+	// event.
+	GetHTMLURL() string
+}
+
+// AsEvent returns the Event of e, which is one of the pointers to
+// gitlab.MergeEvent, IssueEvent, PushEvent, TagEvent, PipelineEvent,
+// MergeCommentEvent, IssueCommentEvent, CommitCommentEvent and
+// SnippetCommentEvent. It returns false for the other types.
+func AsEvent(e interface{}) (Event, bool) {
+	var v event
+	var path string
+
+	switch e := e.(type) {
+	case *gitlab.MergeEvent:
+		path, v.projectID = e.Project.PathWithNamespace, e.Project.ID
+		v.author = eventUsername(e.User)
+		v.action, v.htmlURL = e.ObjectAttributes.Action, e.ObjectAttributes.URL
+
+	case *gitlab.IssueEvent:
+		path, v.projectID = e.Project.PathWithNamespace, e.Project.ID
+		v.author = eventUsername(e.User)
+		v.action, v.htmlURL = e.ObjectAttributes.Action, e.ObjectAttributes.URL
+
+	case *gitlab.PushEvent:
+		path, v.projectID = e.Project.PathWithNamespace, e.ProjectID
+		v.author = e.UserUsername
+		v.action, v.htmlURL = "push", e.Project.WebURL+"/-/commit/"+e.After
+
+	case *gitlab.TagEvent:
+		path, v.projectID = e.Project.PathWithNamespace, e.ProjectID
+		v.author = e.UserUsername
+		v.action = "tag_push"
+		v.htmlURL = e.Project.WebURL + "/-/tags/" + strings.TrimPrefix(e.Ref, "refs/tags/")
+
+	case *gitlab.PipelineEvent:
+		path, v.projectID = e.Project.PathWithNamespace, e.Project.ID
+		v.author = eventUsername(e.User)
+		v.action, v.htmlURL = e.ObjectAttributes.Status, e.ObjectAttributes.URL
+
+	case *gitlab.MergeCommentEvent:
+		path, v.projectID = e.Project.PathWithNamespace, e.ProjectID
+		v.author = eventUsername(e.User)
+		v.action, v.htmlURL = string(e.ObjectAttributes.Action), e.ObjectAttributes.URL
+
+	case *gitlab.IssueCommentEvent:
+		path, v.projectID = e.Project.PathWithNamespace, e.ProjectID
+		if e.User != nil {
+			v.author = e.User.Username
+		}
+		v.action, v.htmlURL = string(e.ObjectAttributes.Action), e.ObjectAttributes.URL
+
+	case *gitlab.CommitCommentEvent:
+		path, v.projectID = e.Project.PathWithNamespace, e.ProjectID
+		if e.User != nil {
+			v.author = e.User.Username
+		}
+		v.action, v.htmlURL = string(e.ObjectAttributes.Action), e.ObjectAttributes.URL
+
+	case *gitlab.SnippetCommentEvent:
+		path, v.projectID = e.Project.PathWithNamespace, e.ProjectID
+		v.author = eventUsername(e.User)
+		v.action, v.htmlURL = string(e.ObjectAttributes.Action), e.ObjectAttributes.URL
+
+	default:
+		return nil, false
+	}
+
+	v.org, v.repo = splitProjectPath(path)
+
+	return &v, true
+}
+
+type event struct {
+	org       string
+	repo      string
+	projectID int
+	author    string
+	action    string
+	htmlURL   string
+}
+
+func (v *event) GetOrg() string     { return v.org }
+func (v *event) GetRepo() string    { return v.repo }
+func (v *event) GetProjectID() int  { return v.projectID }
+func (v *event) GetAuthor() string  { return v.author }
+func (v *event) GetAction() string  { return v.action }
+func (v *event) GetHTMLURL() string { return v.htmlURL }
+
+// splitProjectPath splits the full path of a project into the path of the
+// namespace and the path of the project.
+func splitProjectPath(path string) (org, repo string) {
+	i := strings.LastIndex(path, "/")
+	if i < 0 {
+		return "", path
+	}
+
+	return path[:i], path[i+1:]
+}
+
+func eventUsername(u *gitlab.EventUser) string {
+	if u == nil {
+		return ""
+	}
+
+	return u.Username
+}
@@ -0,0 +1,114 @@
+package client
+
+import (
+	"github.com/xanzy/go-gitlab"
+)
+
+// The actions of the merge request events.
+const (
+	MRActionOpen   = "open"
+	MRActionClose  = "close"
+	MRActionReopen = "reopen"
+	MRActionUpdate = "update"
+	MRActionMerge  = "merge"
+
+	// MRActionApproval and MRActionUnapproval are sent when a user
+	// approves or withdraws the approval, while MRActionApproved and
+	// MRActionUnapproved are sent when the merge request gets or loses
+	// all the approvals it requires.
+	MRActionApproval   = "approval"
+	MRActionUnapproval = "unapproval"
+	MRActionApproved   = "approved"
+	MRActionUnapproved = "unapproved"
+)
+
+// The actions of the issue events.
+const (
+	IssueActionOpen   = "open"
+	IssueActionClose  = "close"
+	IssueActionReopen = "reopen"
+	IssueActionUpdate = "update"
+)
+
+// IsMROpened reports whether the merge request is just created. It is
+// false when it is reopened.
+func IsMROpened(e *gitlab.MergeEvent) bool {
+	return e.ObjectAttributes.Action == MRActionOpen
+}
+
+// IsMRReopened reports whether the closed merge request is reopened.
+func IsMRReopened(e *gitlab.MergeEvent) bool {
+	return e.ObjectAttributes.Action == MRActionReopen
+}
+
+// IsMRClosed reports whether the merge request is closed without merging.
+func IsMRClosed(e *gitlab.MergeEvent) bool {
+	return e.ObjectAttributes.Action == MRActionClose
+}
+
+// IsMRMerged reports whether the merge request is merged.
+func IsMRMerged(e *gitlab.MergeEvent) bool {
+	return e.ObjectAttributes.Action == MRActionMerge
+}
+
+// IsMRUpdatedWithNewCommits reports whether commits are pushed to the
+// source branch of the merge request, including by a force push or a
+// rebase. GitLab sets oldrev only in such case of the update events.
+func IsMRUpdatedWithNewCommits(e *gitlab.MergeEvent) bool {
+	return e.ObjectAttributes.Action == MRActionUpdate && e.ObjectAttributes.OldRev != ""
+}
+
+// IsMRTargetBranchChanged reports whether the target branch of the merge
+// request is changed.
+func IsMRTargetBranchChanged(e *gitlab.MergeEvent) bool {
+	c := &e.Changes.TargetBranch
+
+	return e.ObjectAttributes.Action == MRActionUpdate && c.Previous != "" && c.Previous != c.Current
+}
+
+// IsMRMarkedReady reports whether the draft merge request is marked as
+// ready.
+func IsMRMarkedReady(e *gitlab.MergeEvent) bool {
+	return e.ObjectAttributes.Action == MRActionUpdate &&
+		e.Changes.Draft.Previous && !e.Changes.Draft.Current
+}
+
+// IsMRMarkedDraft reports whether the merge request is marked as draft.
+func IsMRMarkedDraft(e *gitlab.MergeEvent) bool {
+	return e.ObjectAttributes.Action == MRActionUpdate &&
+		!e.Changes.Draft.Previous && e.Changes.Draft.Current
+}
+
+// IsMRLabelsChanged reports whether labels are added to or removed from
+// the merge request. See MRLabelsDelta for what are changed.
+func IsMRLabelsChanged(e *gitlab.MergeEvent) bool {
+	if e.ObjectAttributes.Action != MRActionUpdate {
+		return false
+	}
+
+	d := MRLabelsDelta(e)
+
+	return len(d.Added) > 0 || len(d.Removed) > 0
+}
+
+// IsMRApproved reports whether the merge request gets all the approvals it
+// requires.
+func IsMRApproved(e *gitlab.MergeEvent) bool {
+	return e.ObjectAttributes.Action == MRActionApproved
+}
+
+// IsIssueOpened reports whether the issue is just created. It is false
+// when it is reopened.
+func IsIssueOpened(e *gitlab.IssueEvent) bool {
+	return e.ObjectAttributes.Action == IssueActionOpen
+}
+
+// IsIssueReopened reports whether the closed issue is reopened.
+func IsIssueReopened(e *gitlab.IssueEvent) bool {
+	return e.ObjectAttributes.Action == IssueActionReopen
+}
+
+// IsIssueClosed reports whether the issue is closed.
+func IsIssueClosed(e *gitlab.IssueEvent) bool {
+	return e.ObjectAttributes.Action == IssueActionClose
+}
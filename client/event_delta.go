@@ -0,0 +1,94 @@
+package client
+
+import (
+	"slices"
+
+	"github.com/xanzy/go-gitlab"
+)
+
+// Delta is what an event added to and removed from a set, such as the
+// labels of a merge request. Both are empty if the set is not changed.
+type Delta struct {
+	Added   []string
+	Removed []string
+}
+
+// MRLabelsDelta returns the titles of the labels added to and removed from
+// the merge request by the event.
+func MRLabelsDelta(e *gitlab.MergeEvent) Delta {
+	c := &e.Changes.Labels
+
+	return delta(labelTitles(c.Previous), labelTitles(c.Current))
+}
+
+// MRAssigneesDelta returns the usernames of the assignees added to and
+// removed from the merge request by the event.
+func MRAssigneesDelta(e *gitlab.MergeEvent) Delta {
+	c := &e.Changes.Assignees
+
+	return delta(usernames(c.Previous), usernames(c.Current))
+}
+
+// MRReviewersDelta returns the usernames of the reviewers added to and
+// removed from the merge request by the event.
+func MRReviewersDelta(e *gitlab.MergeEvent) Delta {
+	c := &e.Changes.Reviewers
+
+	return delta(usernames(c.Previous), usernames(c.Current))
+}
+
+// IssueLabelsDelta returns the titles of the labels added to and removed
+// from the issue by the event.
+func IssueLabelsDelta(e *gitlab.IssueEvent) Delta {
+	c := &e.Changes.Labels
+
+	return delta(labelTitles(c.Previous), labelTitles(c.Current))
+}
+
+// IssueAssigneesDelta returns the usernames of the assignees added to and
+// removed from the issue by the event.
+func IssueAssigneesDelta(e *gitlab.IssueEvent) Delta {
+	c := &e.Changes.Assignees
+
+	return delta(usernames(c.Previous), usernames(c.Current))
+}
+
+func delta(previous, current []string) Delta {
+	var r Delta
+
+	for _, v := range current {
+		if !slices.Contains(previous, v) {
+			r.Added = append(r.Added, v)
+		}
+	}
+
+	for _, v := range previous {
+		if !slices.Contains(current, v) {
+			r.Removed = append(r.Removed, v)
+		}
+	}
+
+	return r
+}
+
+func labelTitles(labels []*gitlab.EventLabel) []string {
+	r := make([]string, 0, len(labels))
+	for _, v := range labels {
+		if v != nil {
+			r = append(r, v.Title)
+		}
+	}
+
+	return r
+}
+
+func usernames(users []*gitlab.EventUser) []string {
+	r := make([]string, 0, len(users))
+	for _, v := range users {
+		if v != nil {
+			r = append(r, v.Username)
+		}
+	}
+
+	return r
+}
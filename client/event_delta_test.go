@@ -0,0 +1,61 @@
+package client
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/xanzy/go-gitlab"
+)
+
+func eventLabels(titles ...string) []*gitlab.EventLabel {
+	r := make([]*gitlab.EventLabel, len(titles))
+	for i, v := range titles {
+		r[i] = &gitlab.EventLabel{Title: v}
+	}
+
+	return r
+}
+
+func TestMRLabelsDelta(t *testing.T) {
+	tests := []struct {
+		name     string
+		previous []*gitlab.EventLabel
+		current  []*gitlab.EventLabel
+		want     Delta
+	}{
+		{name: "not changed", want: Delta{}},
+		{name: "added", current: eventLabels("lgtm"), want: Delta{Added: []string{"lgtm"}}},
+		{name: "removed", previous: eventLabels("lgtm"), want: Delta{Removed: []string{"lgtm"}}},
+		{
+			name:     "added and removed",
+			previous: eventLabels("bug", "lgtm"),
+			current:  eventLabels("bug", "approved", "kind/feature"),
+			want:     Delta{Added: []string{"approved", "kind/feature"}, Removed: []string{"lgtm"}},
+		},
+		{
+			name:     "reordered",
+			previous: eventLabels("bug", "lgtm"),
+			current:  eventLabels("lgtm", "bug"),
+			want:     Delta{},
+		},
+		{
+			name:     "nil label",
+			previous: []*gitlab.EventLabel{nil},
+			current:  eventLabels("bug"),
+			want:     Delta{Added: []string{"bug"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &gitlab.MergeEvent{}
+			e.Changes.Labels.Previous = tt.previous
+			e.Changes.Labels.Current = tt.current
+
+			got := MRLabelsDelta(e)
+			if !slices.Equal(got.Added, tt.want.Added) || !slices.Equal(got.Removed, tt.want.Removed) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/xanzy/go-gitlab"
+)
+
+// NoteableType is the type of the object a note is made on.
+type NoteableType string
+
+const (
+	NoteableIssue        NoteableType = "Issue"
+	NoteableMergeRequest NoteableType = "MergeRequest"
+	NoteableCommit       NoteableType = "Commit"
+	NoteableSnippet      NoteableType = "Snippet"
+)
+
+// ParseNoteEvent decodes the payload of a note event by the type of the
+// object the note is made on. The event is a *gitlab.IssueCommentEvent,
+// *gitlab.MergeCommentEvent, *gitlab.CommitCommentEvent or
+// *gitlab.SnippetCommentEvent accordingly.
+func ParseNoteEvent(payload []byte) (NoteableType, interface{}, error) {
+	var v struct {
+		ObjectAttributes struct {
+			NoteableType NoteableType `json:"noteable_type"`
+		} `json:"object_attributes"`
+	}
+
+	if err := json.Unmarshal(payload, &v); err != nil {
+		return "", nil, err
+	}
+
+	t := v.ObjectAttributes.NoteableType
+
+	var e interface{}
+	switch t {
+	case NoteableIssue:
+		e = new(gitlab.IssueCommentEvent)
+	case NoteableMergeRequest:
+		e = new(gitlab.MergeCommentEvent)
+	case NoteableCommit:
+		e = new(gitlab.CommitCommentEvent)
+	case NoteableSnippet:
+		e = new(gitlab.SnippetCommentEvent)
+	default:
+		return t, nil, fmt.Errorf("unknown noteable type %q of note event", t)
+	}
+
+	if err := json.Unmarshal(payload, e); err != nil {
+		return t, nil, err
+	}
+
+	return t, e, nil
+}
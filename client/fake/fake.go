@@ -0,0 +1,1466 @@
+// Package fake provides an in-memory implementation of client.Interface
+// for testing the handlers of robots without a GitLab instance.
+package fake
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"github.com/xanzy/go-gitlab"
+
+	"github.com/opensourceways/robot-gitlab-lib/client"
+)
+
+// Call is a call of a method of Client.
+type Call struct {
+	Method string
+	Args   []interface{}
+}
+
+// Client records the calls of its methods. Each method returns what the
+// function field named after it returns, such as GetBranchFunc for
+// GetBranch, or the zero values if the field is nil.
+type Client struct {
+	ApproveMRFunc                 func(pid interface{}, iid int) error
+	UnapproveMRFunc               func(pid interface{}, iid int) error
+	GetMRApprovalStateFunc        func(pid interface{}, iid int) (client.MRApprovalState, error)
+	ListMRApprovalRulesFunc       func(pid interface{}, iid int) ([]*gitlab.MergeRequestApprovalRule, error)
+	CreateMRApprovalRuleFunc      func(pid interface{}, iid int, opts client.ApprovalRuleOptions) (*gitlab.MergeRequestApprovalRule, error)
+	UpdateMRApprovalRuleFunc      func(pid interface{}, iid, ruleID int, opts client.ApprovalRuleOptions) (*gitlab.MergeRequestApprovalRule, error)
+	ListProjectApprovalRulesFunc  func(pid interface{}) ([]*gitlab.ProjectApprovalRule, error)
+	CreateProjectApprovalRuleFunc func(pid interface{}, opts client.ApprovalRuleOptions, protectedBranchIDs []int) (*gitlab.ProjectApprovalRule, error)
+	UpdateProjectApprovalRuleFunc func(pid interface{}, ruleID int, opts client.ApprovalRuleOptions, protectedBranchIDs []int) (*gitlab.ProjectApprovalRule, error)
+
+	AddAwardEmojiFunc    func(pid interface{}, item client.Awardable, name string) (*gitlab.AwardEmoji, error)
+	RemoveAwardEmojiFunc func(pid interface{}, item client.Awardable, awardID int) error
+	ListAwardEmojiFunc   func(pid interface{}, item client.Awardable) ([]*gitlab.AwardEmoji, error)
+
+	GetBranchFunc             func(pid interface{}, branch string) (*gitlab.Branch, error)
+	CreateBranchFunc          func(pid interface{}, branch, ref string) (*gitlab.Branch, error)
+	DeleteBranchFunc          func(pid interface{}, branch string) error
+	ProtectBranchFunc         func(pid interface{}, branch string, opts client.ProtectBranchOptions) (*gitlab.ProtectedBranch, error)
+	UpdateProtectedBranchFunc func(pid interface{}, pb *gitlab.ProtectedBranch, opts client.ProtectBranchOptions) (*gitlab.ProtectedBranch, error)
+	UnprotectBranchFunc       func(pid interface{}, branch string) error
+	ListProtectedBranchesFunc func(pid interface{}) ([]*gitlab.ProtectedBranch, error)
+
+	CircuitOpenFunc func() bool
+
+	ListMRCommitsFunc     func(pid interface{}, iid int) ([]client.MRCommit, error)
+	SetCommitStatusFunc   func(pid interface{}, sha string, state gitlab.BuildStateValue, name, targetURL, description string) error
+	GetCommitStatusesFunc func(pid interface{}, sha string) ([]*gitlab.CommitStatus, error)
+	CherryPickCommitFunc  func(pid interface{}, sha, targetBranch string) (*gitlab.Commit, error)
+	RevertCommitFunc      func(pid interface{}, sha, branch string) (*gitlab.Commit, error)
+
+	GetMRChangedFilesFunc func(pid interface{}, iid int) ([]client.ChangedFile, error)
+
+	CreateMRDiscussionFunc    func(pid interface{}, iid int, body string, pos *client.DiffPosition) (*gitlab.Discussion, error)
+	ReplyToDiscussionFunc     func(pid interface{}, iid int, discussionID, body string) (*gitlab.Note, error)
+	ResolveDiscussionFunc     func(pid interface{}, iid int, discussionID string, resolved bool) error
+	LockIssueDiscussionFunc   func(pid interface{}, iid int) error
+	UnlockIssueDiscussionFunc func(pid interface{}, iid int) error
+	LockMRDiscussionFunc      func(pid interface{}, iid int) error
+	UnlockMRDiscussionFunc    func(pid interface{}, iid int) error
+
+	CreateFileFunc func(pid interface{}, path string, content []byte, opts client.FileCommitOptions) error
+	UpdateFileFunc func(pid interface{}, path string, content []byte, opts client.FileCommitOptions) error
+	DeleteFileFunc func(pid interface{}, path string, opts client.FileCommitOptions) error
+
+	GraphQLFunc func(query string, variables map[string]interface{}, result interface{}) error
+
+	ListGroupProjectsFunc func(gid interface{}, includeSubgroups bool) ([]*gitlab.Project, error)
+
+	ListProjectHooksFunc  func(pid interface{}) ([]*gitlab.ProjectHook, error)
+	AddProjectHookFunc    func(pid interface{}, opts client.HookOptions) (*gitlab.ProjectHook, error)
+	EditProjectHookFunc   func(pid interface{}, hookID int, opts client.HookOptions) (*gitlab.ProjectHook, error)
+	DeleteProjectHookFunc func(pid interface{}, hookID int) error
+	ListGroupHooksFunc    func(gid interface{}) ([]*gitlab.GroupHook, error)
+	AddGroupHookFunc      func(gid interface{}, opts client.HookOptions) (*gitlab.GroupHook, error)
+	EditGroupHookFunc     func(gid interface{}, hookID int, opts client.HookOptions) (*gitlab.GroupHook, error)
+	DeleteGroupHookFunc   func(gid interface{}, hookID int) error
+
+	GetIssueFunc             func(pid interface{}, iid int) (*gitlab.Issue, error)
+	GetIssueDetailsFunc      func(pid interface{}, iid int) (*gitlab.Issue, error)
+	ListIssuesFunc           func(pid interface{}, opts client.ListIssuesOptions) ([]*gitlab.Issue, error)
+	ListGroupIssuesFunc      func(gid interface{}, opts client.ListIssuesOptions) ([]*gitlab.Issue, error)
+	CloseIssueFunc           func(pid interface{}, iid int) error
+	ReopenIssueFunc          func(pid interface{}, iid int) error
+	SetIssueDueDateFunc      func(pid interface{}, iid int, due time.Time) error
+	ClearIssueDueDateFunc    func(pid interface{}, iid int) error
+	SubscribeToIssueFunc     func(pid interface{}, iid int, username string) error
+	UnsubscribeFromIssueFunc func(pid interface{}, iid int, username string) error
+
+	LinkIssuesFunc     func(pid interface{}, iid int, targetPID interface{}, targetIID int, linkType client.IssueLinkType) error
+	ListIssueLinksFunc func(pid interface{}, iid int) ([]*gitlab.IssueRelation, error)
+
+	ListPipelineJobsFunc func(pid interface{}, pipelineID int, includeRetried bool) ([]*gitlab.Job, error)
+	GetJobTraceFunc      func(pid interface{}, jobID int, limit int) ([]byte, error)
+
+	ListLabelsFunc     func(pid interface{}) ([]*gitlab.Label, error)
+	CreateLabelFunc    func(pid interface{}, opts client.LabelOptions) (*gitlab.Label, error)
+	UpdateLabelFunc    func(pid interface{}, name string, opts client.LabelOptions) (*gitlab.Label, error)
+	DeleteLabelFunc    func(pid interface{}, name string) error
+	SetIssueLabelsFunc func(pid interface{}, iid int, update func(current []string) []string) error
+	SetMRLabelsFunc    func(pid interface{}, iid int, update func(current []string) []string) error
+
+	ListProjectMembersFunc    func(pid interface{}) ([]*gitlab.ProjectMember, error)
+	GetUserPermissionFunc     func(pid interface{}, username string) (gitlab.AccessLevelValue, error)
+	IsProjectMemberFunc       func(pid interface{}, username string) (bool, error)
+	HasWriteAccessFunc        func(pid interface{}, username string) (bool, error)
+	IsMaintainerFunc          func(pid interface{}, username string) (bool, error)
+	InvalidatePermissionsFunc func(username string)
+
+	GetMRFunc                func(pid interface{}, iid int) (*gitlab.MergeRequest, error)
+	GetMRDetailsFunc         func(pid interface{}, iid int) (*gitlab.MergeRequest, error)
+	ListMRsFunc              func(pid interface{}, opts client.ListMRsOptions) ([]*gitlab.MergeRequest, error)
+	ListOpenMRsTargetingFunc func(pid interface{}, branch string) ([]*gitlab.MergeRequest, error)
+	MergeMRFunc              func(pid interface{}, iid int, opts client.MergeMROptions) error
+	CloseMRFunc              func(pid interface{}, iid int) error
+	ReopenMRFunc             func(pid interface{}, iid int) error
+	UpdateMRTitleFunc        func(pid interface{}, iid int, title string) error
+	UpdateMRDescriptionFunc  func(pid interface{}, iid int, desc string) error
+	SetMRDraftFunc           func(pid interface{}, iid int) error
+	SetMRReadyFunc           func(pid interface{}, iid int) error
+	RebaseMRFunc             func(pid interface{}, iid int) error
+	WaitForRebaseFunc        func(pid interface{}, iid int, before *gitlab.MergeRequest, timeout time.Duration) error
+
+	CreateMilestoneFunc   func(pid interface{}, title, description string, dueDate *time.Time) (*gitlab.Milestone, error)
+	ListMilestonesFunc    func(pid interface{}, opts client.ListMilestonesOptions) ([]*gitlab.Milestone, error)
+	SetIssueMilestoneFunc func(pid interface{}, iid, milestoneID int) error
+	SetMRMilestoneFunc    func(pid interface{}, iid, milestoneID int) error
+
+	ListIssueNotesFunc     func(pid interface{}, iid int) ([]*gitlab.Note, error)
+	CreateIssueCommentFunc func(pid interface{}, iid int, body string) (*gitlab.Note, error)
+	UpdateIssueCommentFunc func(pid interface{}, iid, noteID int, body string) (*gitlab.Note, error)
+	DeleteIssueCommentFunc func(pid interface{}, iid, noteID int) error
+	ListMRNotesFunc        func(pid interface{}, iid int) ([]*gitlab.Note, error)
+	CreateMRCommentFunc    func(pid interface{}, iid int, body string) (*gitlab.Note, error)
+	UpdateMRCommentFunc    func(pid interface{}, iid, noteID int, body string) (*gitlab.Note, error)
+	DeleteMRCommentFunc    func(pid interface{}, iid, noteID int) error
+
+	CreatePipelineFunc func(pid interface{}, ref string, variables map[string]string) (*gitlab.Pipeline, error)
+	RetryPipelineFunc  func(pid interface{}, pipelineID int) (*gitlab.Pipeline, error)
+	CancelPipelineFunc func(pid interface{}, pipelineID int) (*gitlab.Pipeline, error)
+
+	CreateProjectFunc        func(opts client.CreateProjectOptions) (*gitlab.Project, error)
+	ForkProjectFunc          func(pid interface{}, opts client.ForkProjectOptions) (*gitlab.Project, error)
+	ListProjectEventsFunc    func(pid interface{}, after time.Time) ([]*gitlab.ProjectEvent, error)
+	ListNewProjectEventsFunc func(pid interface{}, lastID, limit int) ([]*gitlab.ProjectEvent, error)
+
+	RateLimitFunc func() client.RateLimitState
+
+	CreateReleaseFunc func(pid interface{}, tag string, opts client.ReleaseOptions) (*gitlab.Release, error)
+	UpdateReleaseFunc func(pid interface{}, tag string, opts client.ReleaseOptions) (*gitlab.Release, error)
+	ListReleasesFunc  func(pid interface{}) ([]*gitlab.Release, error)
+
+	GetPathContentFunc     func(pid interface{}, path, ref string) ([]byte, error)
+	GetDirectoryTreeFunc   func(pid interface{}, path, ref string) ([]*gitlab.TreeNode, error)
+	ListRepositoryTreeFunc func(pid interface{}, path, ref string, recursive bool) ([]*gitlab.TreeNode, error)
+	DownloadArchiveFunc    func(pid interface{}, ref, format string, w io.Writer) error
+	CompareFunc            func(pid interface{}, from, to string) (*gitlab.Compare, error)
+
+	SearchIssuesFunc        func(pid interface{}, query string, limit int) ([]*gitlab.Issue, error)
+	SearchMergeRequestsFunc func(pid interface{}, query string, limit int) ([]*gitlab.MergeRequest, error)
+	SearchCodeFunc          func(pid interface{}, query, ref string, limit int) ([]*gitlab.Blob, error)
+
+	CreateTagFunc func(pid interface{}, tag, ref, message string) (*gitlab.Tag, error)
+	DeleteTagFunc func(pid interface{}, tag string) error
+
+	AddIssueSpentTimeFunc    func(pid interface{}, iid int, duration, summary string) (*gitlab.TimeStats, error)
+	SetIssueTimeEstimateFunc func(pid interface{}, iid int, duration string) (*gitlab.TimeStats, error)
+	AddMRSpentTimeFunc       func(pid interface{}, iid int, duration, summary string) (*gitlab.TimeStats, error)
+	SetMRTimeEstimateFunc    func(pid interface{}, iid int, duration string) (*gitlab.TimeStats, error)
+
+	GetUserByUsernameFunc func(username string) (*gitlab.User, error)
+	GetUserByIDFunc       func(id int) (*gitlab.User, error)
+	GetCurrentUserFunc    func() (*gitlab.User, error)
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+var _ client.Interface = (*Client)(nil)
+
+// Calls returns the calls made so far, in order.
+func (f *Client) Calls() []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]Call(nil), f.calls...)
+}
+
+// CallsOf returns the calls of the method, in order.
+func (f *Client) CallsOf(method string) []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var r []Call
+	for _, c := range f.calls {
+		if c.Method == method {
+			r = append(r, c)
+		}
+	}
+
+	return r
+}
+
+// Reset forgets the calls made so far.
+func (f *Client) Reset() {
+	f.mu.Lock()
+	f.calls = nil
+	f.mu.Unlock()
+}
+
+func (f *Client) record(method string, args ...interface{}) {
+	f.mu.Lock()
+	f.calls = append(f.calls, Call{Method: method, Args: args})
+	f.mu.Unlock()
+}
+
+func (f *Client) ApproveMR(pid interface{}, iid int) error {
+	f.record("ApproveMR", pid, iid)
+
+	if f.ApproveMRFunc != nil {
+		return f.ApproveMRFunc(pid, iid)
+	}
+
+	return nil
+}
+
+func (f *Client) UnapproveMR(pid interface{}, iid int) error {
+	f.record("UnapproveMR", pid, iid)
+
+	if f.UnapproveMRFunc != nil {
+		return f.UnapproveMRFunc(pid, iid)
+	}
+
+	return nil
+}
+
+func (f *Client) GetMRApprovalState(pid interface{}, iid int) (client.MRApprovalState, error) {
+	f.record("GetMRApprovalState", pid, iid)
+
+	if f.GetMRApprovalStateFunc != nil {
+		return f.GetMRApprovalStateFunc(pid, iid)
+	}
+
+	return client.MRApprovalState{}, nil
+}
+
+func (f *Client) ListMRApprovalRules(pid interface{}, iid int) ([]*gitlab.MergeRequestApprovalRule, error) {
+	f.record("ListMRApprovalRules", pid, iid)
+
+	if f.ListMRApprovalRulesFunc != nil {
+		return f.ListMRApprovalRulesFunc(pid, iid)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) CreateMRApprovalRule(pid interface{}, iid int, opts client.ApprovalRuleOptions) (*gitlab.MergeRequestApprovalRule, error) {
+	f.record("CreateMRApprovalRule", pid, iid, opts)
+
+	if f.CreateMRApprovalRuleFunc != nil {
+		return f.CreateMRApprovalRuleFunc(pid, iid, opts)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) UpdateMRApprovalRule(pid interface{}, iid, ruleID int, opts client.ApprovalRuleOptions) (*gitlab.MergeRequestApprovalRule, error) {
+	f.record("UpdateMRApprovalRule", pid, iid, ruleID, opts)
+
+	if f.UpdateMRApprovalRuleFunc != nil {
+		return f.UpdateMRApprovalRuleFunc(pid, iid, ruleID, opts)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) ListProjectApprovalRules(pid interface{}) ([]*gitlab.ProjectApprovalRule, error) {
+	f.record("ListProjectApprovalRules", pid)
+
+	if f.ListProjectApprovalRulesFunc != nil {
+		return f.ListProjectApprovalRulesFunc(pid)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) CreateProjectApprovalRule(pid interface{}, opts client.ApprovalRuleOptions, protectedBranchIDs []int) (*gitlab.ProjectApprovalRule, error) {
+	f.record("CreateProjectApprovalRule", pid, opts, protectedBranchIDs)
+
+	if f.CreateProjectApprovalRuleFunc != nil {
+		return f.CreateProjectApprovalRuleFunc(pid, opts, protectedBranchIDs)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) UpdateProjectApprovalRule(pid interface{}, ruleID int, opts client.ApprovalRuleOptions, protectedBranchIDs []int) (*gitlab.ProjectApprovalRule, error) {
+	f.record("UpdateProjectApprovalRule", pid, ruleID, opts, protectedBranchIDs)
+
+	if f.UpdateProjectApprovalRuleFunc != nil {
+		return f.UpdateProjectApprovalRuleFunc(pid, ruleID, opts, protectedBranchIDs)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) AddAwardEmoji(pid interface{}, item client.Awardable, name string) (*gitlab.AwardEmoji, error) {
+	f.record("AddAwardEmoji", pid, item, name)
+
+	if f.AddAwardEmojiFunc != nil {
+		return f.AddAwardEmojiFunc(pid, item, name)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) RemoveAwardEmoji(pid interface{}, item client.Awardable, awardID int) error {
+	f.record("RemoveAwardEmoji", pid, item, awardID)
+
+	if f.RemoveAwardEmojiFunc != nil {
+		return f.RemoveAwardEmojiFunc(pid, item, awardID)
+	}
+
+	return nil
+}
+
+func (f *Client) ListAwardEmoji(pid interface{}, item client.Awardable) ([]*gitlab.AwardEmoji, error) {
+	f.record("ListAwardEmoji", pid, item)
+
+	if f.ListAwardEmojiFunc != nil {
+		return f.ListAwardEmojiFunc(pid, item)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) GetBranch(pid interface{}, branch string) (*gitlab.Branch, error) {
+	f.record("GetBranch", pid, branch)
+
+	if f.GetBranchFunc != nil {
+		return f.GetBranchFunc(pid, branch)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) CreateBranch(pid interface{}, branch, ref string) (*gitlab.Branch, error) {
+	f.record("CreateBranch", pid, branch, ref)
+
+	if f.CreateBranchFunc != nil {
+		return f.CreateBranchFunc(pid, branch, ref)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) DeleteBranch(pid interface{}, branch string) error {
+	f.record("DeleteBranch", pid, branch)
+
+	if f.DeleteBranchFunc != nil {
+		return f.DeleteBranchFunc(pid, branch)
+	}
+
+	return nil
+}
+
+func (f *Client) ProtectBranch(pid interface{}, branch string, opts client.ProtectBranchOptions) (*gitlab.ProtectedBranch, error) {
+	f.record("ProtectBranch", pid, branch, opts)
+
+	if f.ProtectBranchFunc != nil {
+		return f.ProtectBranchFunc(pid, branch, opts)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) UpdateProtectedBranch(pid interface{}, pb *gitlab.ProtectedBranch, opts client.ProtectBranchOptions) (*gitlab.ProtectedBranch, error) {
+	f.record("UpdateProtectedBranch", pid, pb, opts)
+
+	if f.UpdateProtectedBranchFunc != nil {
+		return f.UpdateProtectedBranchFunc(pid, pb, opts)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) UnprotectBranch(pid interface{}, branch string) error {
+	f.record("UnprotectBranch", pid, branch)
+
+	if f.UnprotectBranchFunc != nil {
+		return f.UnprotectBranchFunc(pid, branch)
+	}
+
+	return nil
+}
+
+func (f *Client) ListProtectedBranches(pid interface{}) ([]*gitlab.ProtectedBranch, error) {
+	f.record("ListProtectedBranches", pid)
+
+	if f.ListProtectedBranchesFunc != nil {
+		return f.ListProtectedBranchesFunc(pid)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) CircuitOpen() bool {
+	f.record("CircuitOpen")
+
+	if f.CircuitOpenFunc != nil {
+		return f.CircuitOpenFunc()
+	}
+
+	return false
+}
+
+func (f *Client) ListMRCommits(pid interface{}, iid int) ([]client.MRCommit, error) {
+	f.record("ListMRCommits", pid, iid)
+
+	if f.ListMRCommitsFunc != nil {
+		return f.ListMRCommitsFunc(pid, iid)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) SetCommitStatus(pid interface{}, sha string, state gitlab.BuildStateValue, name, targetURL, description string) error {
+	f.record("SetCommitStatus", pid, sha, state, name, targetURL, description)
+
+	if f.SetCommitStatusFunc != nil {
+		return f.SetCommitStatusFunc(pid, sha, state, name, targetURL, description)
+	}
+
+	return nil
+}
+
+func (f *Client) GetCommitStatuses(pid interface{}, sha string) ([]*gitlab.CommitStatus, error) {
+	f.record("GetCommitStatuses", pid, sha)
+
+	if f.GetCommitStatusesFunc != nil {
+		return f.GetCommitStatusesFunc(pid, sha)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) CherryPickCommit(pid interface{}, sha, targetBranch string) (*gitlab.Commit, error) {
+	f.record("CherryPickCommit", pid, sha, targetBranch)
+
+	if f.CherryPickCommitFunc != nil {
+		return f.CherryPickCommitFunc(pid, sha, targetBranch)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) RevertCommit(pid interface{}, sha, branch string) (*gitlab.Commit, error) {
+	f.record("RevertCommit", pid, sha, branch)
+
+	if f.RevertCommitFunc != nil {
+		return f.RevertCommitFunc(pid, sha, branch)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) GetMRChangedFiles(pid interface{}, iid int) ([]client.ChangedFile, error) {
+	f.record("GetMRChangedFiles", pid, iid)
+
+	if f.GetMRChangedFilesFunc != nil {
+		return f.GetMRChangedFilesFunc(pid, iid)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) CreateMRDiscussion(pid interface{}, iid int, body string, pos *client.DiffPosition) (*gitlab.Discussion, error) {
+	f.record("CreateMRDiscussion", pid, iid, body, pos)
+
+	if f.CreateMRDiscussionFunc != nil {
+		return f.CreateMRDiscussionFunc(pid, iid, body, pos)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) ReplyToDiscussion(pid interface{}, iid int, discussionID, body string) (*gitlab.Note, error) {
+	f.record("ReplyToDiscussion", pid, iid, discussionID, body)
+
+	if f.ReplyToDiscussionFunc != nil {
+		return f.ReplyToDiscussionFunc(pid, iid, discussionID, body)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) ResolveDiscussion(pid interface{}, iid int, discussionID string, resolved bool) error {
+	f.record("ResolveDiscussion", pid, iid, discussionID, resolved)
+
+	if f.ResolveDiscussionFunc != nil {
+		return f.ResolveDiscussionFunc(pid, iid, discussionID, resolved)
+	}
+
+	return nil
+}
+
+func (f *Client) LockIssueDiscussion(pid interface{}, iid int) error {
+	f.record("LockIssueDiscussion", pid, iid)
+
+	if f.LockIssueDiscussionFunc != nil {
+		return f.LockIssueDiscussionFunc(pid, iid)
+	}
+
+	return nil
+}
+
+func (f *Client) UnlockIssueDiscussion(pid interface{}, iid int) error {
+	f.record("UnlockIssueDiscussion", pid, iid)
+
+	if f.UnlockIssueDiscussionFunc != nil {
+		return f.UnlockIssueDiscussionFunc(pid, iid)
+	}
+
+	return nil
+}
+
+func (f *Client) LockMRDiscussion(pid interface{}, iid int) error {
+	f.record("LockMRDiscussion", pid, iid)
+
+	if f.LockMRDiscussionFunc != nil {
+		return f.LockMRDiscussionFunc(pid, iid)
+	}
+
+	return nil
+}
+
+func (f *Client) UnlockMRDiscussion(pid interface{}, iid int) error {
+	f.record("UnlockMRDiscussion", pid, iid)
+
+	if f.UnlockMRDiscussionFunc != nil {
+		return f.UnlockMRDiscussionFunc(pid, iid)
+	}
+
+	return nil
+}
+
+func (f *Client) CreateFile(pid interface{}, path string, content []byte, opts client.FileCommitOptions) error {
+	f.record("CreateFile", pid, path, content, opts)
+
+	if f.CreateFileFunc != nil {
+		return f.CreateFileFunc(pid, path, content, opts)
+	}
+
+	return nil
+}
+
+func (f *Client) UpdateFile(pid interface{}, path string, content []byte, opts client.FileCommitOptions) error {
+	f.record("UpdateFile", pid, path, content, opts)
+
+	if f.UpdateFileFunc != nil {
+		return f.UpdateFileFunc(pid, path, content, opts)
+	}
+
+	return nil
+}
+
+func (f *Client) DeleteFile(pid interface{}, path string, opts client.FileCommitOptions) error {
+	f.record("DeleteFile", pid, path, opts)
+
+	if f.DeleteFileFunc != nil {
+		return f.DeleteFileFunc(pid, path, opts)
+	}
+
+	return nil
+}
+
+func (f *Client) GraphQL(query string, variables map[string]interface{}, result interface{}) error {
+	f.record("GraphQL", query, variables, result)
+
+	if f.GraphQLFunc != nil {
+		return f.GraphQLFunc(query, variables, result)
+	}
+
+	return nil
+}
+
+func (f *Client) ListGroupProjects(gid interface{}, includeSubgroups bool) ([]*gitlab.Project, error) {
+	f.record("ListGroupProjects", gid, includeSubgroups)
+
+	if f.ListGroupProjectsFunc != nil {
+		return f.ListGroupProjectsFunc(gid, includeSubgroups)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) ListProjectHooks(pid interface{}) ([]*gitlab.ProjectHook, error) {
+	f.record("ListProjectHooks", pid)
+
+	if f.ListProjectHooksFunc != nil {
+		return f.ListProjectHooksFunc(pid)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) AddProjectHook(pid interface{}, opts client.HookOptions) (*gitlab.ProjectHook, error) {
+	f.record("AddProjectHook", pid, opts)
+
+	if f.AddProjectHookFunc != nil {
+		return f.AddProjectHookFunc(pid, opts)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) EditProjectHook(pid interface{}, hookID int, opts client.HookOptions) (*gitlab.ProjectHook, error) {
+	f.record("EditProjectHook", pid, hookID, opts)
+
+	if f.EditProjectHookFunc != nil {
+		return f.EditProjectHookFunc(pid, hookID, opts)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) DeleteProjectHook(pid interface{}, hookID int) error {
+	f.record("DeleteProjectHook", pid, hookID)
+
+	if f.DeleteProjectHookFunc != nil {
+		return f.DeleteProjectHookFunc(pid, hookID)
+	}
+
+	return nil
+}
+
+func (f *Client) ListGroupHooks(gid interface{}) ([]*gitlab.GroupHook, error) {
+	f.record("ListGroupHooks", gid)
+
+	if f.ListGroupHooksFunc != nil {
+		return f.ListGroupHooksFunc(gid)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) AddGroupHook(gid interface{}, opts client.HookOptions) (*gitlab.GroupHook, error) {
+	f.record("AddGroupHook", gid, opts)
+
+	if f.AddGroupHookFunc != nil {
+		return f.AddGroupHookFunc(gid, opts)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) EditGroupHook(gid interface{}, hookID int, opts client.HookOptions) (*gitlab.GroupHook, error) {
+	f.record("EditGroupHook", gid, hookID, opts)
+
+	if f.EditGroupHookFunc != nil {
+		return f.EditGroupHookFunc(gid, hookID, opts)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) DeleteGroupHook(gid interface{}, hookID int) error {
+	f.record("DeleteGroupHook", gid, hookID)
+
+	if f.DeleteGroupHookFunc != nil {
+		return f.DeleteGroupHookFunc(gid, hookID)
+	}
+
+	return nil
+}
+
+func (f *Client) GetIssue(pid interface{}, iid int) (*gitlab.Issue, error) {
+	f.record("GetIssue", pid, iid)
+
+	if f.GetIssueFunc != nil {
+		return f.GetIssueFunc(pid, iid)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) GetIssueDetails(pid interface{}, iid int) (*gitlab.Issue, error) {
+	f.record("GetIssueDetails", pid, iid)
+
+	if f.GetIssueDetailsFunc != nil {
+		return f.GetIssueDetailsFunc(pid, iid)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) ListIssues(pid interface{}, opts client.ListIssuesOptions) ([]*gitlab.Issue, error) {
+	f.record("ListIssues", pid, opts)
+
+	if f.ListIssuesFunc != nil {
+		return f.ListIssuesFunc(pid, opts)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) ListGroupIssues(gid interface{}, opts client.ListIssuesOptions) ([]*gitlab.Issue, error) {
+	f.record("ListGroupIssues", gid, opts)
+
+	if f.ListGroupIssuesFunc != nil {
+		return f.ListGroupIssuesFunc(gid, opts)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) CloseIssue(pid interface{}, iid int) error {
+	f.record("CloseIssue", pid, iid)
+
+	if f.CloseIssueFunc != nil {
+		return f.CloseIssueFunc(pid, iid)
+	}
+
+	return nil
+}
+
+func (f *Client) ReopenIssue(pid interface{}, iid int) error {
+	f.record("ReopenIssue", pid, iid)
+
+	if f.ReopenIssueFunc != nil {
+		return f.ReopenIssueFunc(pid, iid)
+	}
+
+	return nil
+}
+
+func (f *Client) SetIssueDueDate(pid interface{}, iid int, due time.Time) error {
+	f.record("SetIssueDueDate", pid, iid, due)
+
+	if f.SetIssueDueDateFunc != nil {
+		return f.SetIssueDueDateFunc(pid, iid, due)
+	}
+
+	return nil
+}
+
+func (f *Client) ClearIssueDueDate(pid interface{}, iid int) error {
+	f.record("ClearIssueDueDate", pid, iid)
+
+	if f.ClearIssueDueDateFunc != nil {
+		return f.ClearIssueDueDateFunc(pid, iid)
+	}
+
+	return nil
+}
+
+func (f *Client) SubscribeToIssue(pid interface{}, iid int, username string) error {
+	f.record("SubscribeToIssue", pid, iid, username)
+
+	if f.SubscribeToIssueFunc != nil {
+		return f.SubscribeToIssueFunc(pid, iid, username)
+	}
+
+	return nil
+}
+
+func (f *Client) UnsubscribeFromIssue(pid interface{}, iid int, username string) error {
+	f.record("UnsubscribeFromIssue", pid, iid, username)
+
+	if f.UnsubscribeFromIssueFunc != nil {
+		return f.UnsubscribeFromIssueFunc(pid, iid, username)
+	}
+
+	return nil
+}
+
+func (f *Client) LinkIssues(pid interface{}, iid int, targetPID interface{}, targetIID int, linkType client.IssueLinkType) error {
+	f.record("LinkIssues", pid, iid, targetPID, targetIID, linkType)
+
+	if f.LinkIssuesFunc != nil {
+		return f.LinkIssuesFunc(pid, iid, targetPID, targetIID, linkType)
+	}
+
+	return nil
+}
+
+func (f *Client) ListIssueLinks(pid interface{}, iid int) ([]*gitlab.IssueRelation, error) {
+	f.record("ListIssueLinks", pid, iid)
+
+	if f.ListIssueLinksFunc != nil {
+		return f.ListIssueLinksFunc(pid, iid)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) ListPipelineJobs(pid interface{}, pipelineID int, includeRetried bool) ([]*gitlab.Job, error) {
+	f.record("ListPipelineJobs", pid, pipelineID, includeRetried)
+
+	if f.ListPipelineJobsFunc != nil {
+		return f.ListPipelineJobsFunc(pid, pipelineID, includeRetried)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) GetJobTrace(pid interface{}, jobID int, limit int) ([]byte, error) {
+	f.record("GetJobTrace", pid, jobID, limit)
+
+	if f.GetJobTraceFunc != nil {
+		return f.GetJobTraceFunc(pid, jobID, limit)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) ListLabels(pid interface{}) ([]*gitlab.Label, error) {
+	f.record("ListLabels", pid)
+
+	if f.ListLabelsFunc != nil {
+		return f.ListLabelsFunc(pid)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) CreateLabel(pid interface{}, opts client.LabelOptions) (*gitlab.Label, error) {
+	f.record("CreateLabel", pid, opts)
+
+	if f.CreateLabelFunc != nil {
+		return f.CreateLabelFunc(pid, opts)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) UpdateLabel(pid interface{}, name string, opts client.LabelOptions) (*gitlab.Label, error) {
+	f.record("UpdateLabel", pid, name, opts)
+
+	if f.UpdateLabelFunc != nil {
+		return f.UpdateLabelFunc(pid, name, opts)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) DeleteLabel(pid interface{}, name string) error {
+	f.record("DeleteLabel", pid, name)
+
+	if f.DeleteLabelFunc != nil {
+		return f.DeleteLabelFunc(pid, name)
+	}
+
+	return nil
+}
+
+func (f *Client) SetIssueLabels(pid interface{}, iid int, update func(current []string) []string) error {
+	f.record("SetIssueLabels", pid, iid, update)
+
+	if f.SetIssueLabelsFunc != nil {
+		return f.SetIssueLabelsFunc(pid, iid, update)
+	}
+
+	return nil
+}
+
+func (f *Client) SetMRLabels(pid interface{}, iid int, update func(current []string) []string) error {
+	f.record("SetMRLabels", pid, iid, update)
+
+	if f.SetMRLabelsFunc != nil {
+		return f.SetMRLabelsFunc(pid, iid, update)
+	}
+
+	return nil
+}
+
+func (f *Client) ListProjectMembers(pid interface{}) ([]*gitlab.ProjectMember, error) {
+	f.record("ListProjectMembers", pid)
+
+	if f.ListProjectMembersFunc != nil {
+		return f.ListProjectMembersFunc(pid)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) GetUserPermission(pid interface{}, username string) (gitlab.AccessLevelValue, error) {
+	f.record("GetUserPermission", pid, username)
+
+	if f.GetUserPermissionFunc != nil {
+		return f.GetUserPermissionFunc(pid, username)
+	}
+
+	return 0, nil
+}
+
+func (f *Client) IsProjectMember(pid interface{}, username string) (bool, error) {
+	f.record("IsProjectMember", pid, username)
+
+	if f.IsProjectMemberFunc != nil {
+		return f.IsProjectMemberFunc(pid, username)
+	}
+
+	return false, nil
+}
+
+func (f *Client) HasWriteAccess(pid interface{}, username string) (bool, error) {
+	f.record("HasWriteAccess", pid, username)
+
+	if f.HasWriteAccessFunc != nil {
+		return f.HasWriteAccessFunc(pid, username)
+	}
+
+	return false, nil
+}
+
+func (f *Client) IsMaintainer(pid interface{}, username string) (bool, error) {
+	f.record("IsMaintainer", pid, username)
+
+	if f.IsMaintainerFunc != nil {
+		return f.IsMaintainerFunc(pid, username)
+	}
+
+	return false, nil
+}
+
+func (f *Client) InvalidatePermissions(username string) {
+	f.record("InvalidatePermissions", username)
+
+	if f.InvalidatePermissionsFunc != nil {
+		f.InvalidatePermissionsFunc(username)
+	}
+}
+
+func (f *Client) GetMR(pid interface{}, iid int) (*gitlab.MergeRequest, error) {
+	f.record("GetMR", pid, iid)
+
+	if f.GetMRFunc != nil {
+		return f.GetMRFunc(pid, iid)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) GetMRDetails(pid interface{}, iid int) (*gitlab.MergeRequest, error) {
+	f.record("GetMRDetails", pid, iid)
+
+	if f.GetMRDetailsFunc != nil {
+		return f.GetMRDetailsFunc(pid, iid)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) ListMRs(pid interface{}, opts client.ListMRsOptions) ([]*gitlab.MergeRequest, error) {
+	f.record("ListMRs", pid, opts)
+
+	if f.ListMRsFunc != nil {
+		return f.ListMRsFunc(pid, opts)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) ListOpenMRsTargeting(pid interface{}, branch string) ([]*gitlab.MergeRequest, error) {
+	f.record("ListOpenMRsTargeting", pid, branch)
+
+	if f.ListOpenMRsTargetingFunc != nil {
+		return f.ListOpenMRsTargetingFunc(pid, branch)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) MergeMR(pid interface{}, iid int, opts client.MergeMROptions) error {
+	f.record("MergeMR", pid, iid, opts)
+
+	if f.MergeMRFunc != nil {
+		return f.MergeMRFunc(pid, iid, opts)
+	}
+
+	return nil
+}
+
+func (f *Client) CloseMR(pid interface{}, iid int) error {
+	f.record("CloseMR", pid, iid)
+
+	if f.CloseMRFunc != nil {
+		return f.CloseMRFunc(pid, iid)
+	}
+
+	return nil
+}
+
+func (f *Client) ReopenMR(pid interface{}, iid int) error {
+	f.record("ReopenMR", pid, iid)
+
+	if f.ReopenMRFunc != nil {
+		return f.ReopenMRFunc(pid, iid)
+	}
+
+	return nil
+}
+
+func (f *Client) UpdateMRTitle(pid interface{}, iid int, title string) error {
+	f.record("UpdateMRTitle", pid, iid, title)
+
+	if f.UpdateMRTitleFunc != nil {
+		return f.UpdateMRTitleFunc(pid, iid, title)
+	}
+
+	return nil
+}
+
+func (f *Client) UpdateMRDescription(pid interface{}, iid int, desc string) error {
+	f.record("UpdateMRDescription", pid, iid, desc)
+
+	if f.UpdateMRDescriptionFunc != nil {
+		return f.UpdateMRDescriptionFunc(pid, iid, desc)
+	}
+
+	return nil
+}
+
+func (f *Client) SetMRDraft(pid interface{}, iid int) error {
+	f.record("SetMRDraft", pid, iid)
+
+	if f.SetMRDraftFunc != nil {
+		return f.SetMRDraftFunc(pid, iid)
+	}
+
+	return nil
+}
+
+func (f *Client) SetMRReady(pid interface{}, iid int) error {
+	f.record("SetMRReady", pid, iid)
+
+	if f.SetMRReadyFunc != nil {
+		return f.SetMRReadyFunc(pid, iid)
+	}
+
+	return nil
+}
+
+func (f *Client) RebaseMR(pid interface{}, iid int) error {
+	f.record("RebaseMR", pid, iid)
+
+	if f.RebaseMRFunc != nil {
+		return f.RebaseMRFunc(pid, iid)
+	}
+
+	return nil
+}
+
+func (f *Client) WaitForRebase(pid interface{}, iid int, before *gitlab.MergeRequest, timeout time.Duration) error {
+	f.record("WaitForRebase", pid, iid, before, timeout)
+
+	if f.WaitForRebaseFunc != nil {
+		return f.WaitForRebaseFunc(pid, iid, before, timeout)
+	}
+
+	return nil
+}
+
+func (f *Client) CreateMilestone(pid interface{}, title, description string, dueDate *time.Time) (*gitlab.Milestone, error) {
+	f.record("CreateMilestone", pid, title, description, dueDate)
+
+	if f.CreateMilestoneFunc != nil {
+		return f.CreateMilestoneFunc(pid, title, description, dueDate)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) ListMilestones(pid interface{}, opts client.ListMilestonesOptions) ([]*gitlab.Milestone, error) {
+	f.record("ListMilestones", pid, opts)
+
+	if f.ListMilestonesFunc != nil {
+		return f.ListMilestonesFunc(pid, opts)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) SetIssueMilestone(pid interface{}, iid, milestoneID int) error {
+	f.record("SetIssueMilestone", pid, iid, milestoneID)
+
+	if f.SetIssueMilestoneFunc != nil {
+		return f.SetIssueMilestoneFunc(pid, iid, milestoneID)
+	}
+
+	return nil
+}
+
+func (f *Client) SetMRMilestone(pid interface{}, iid, milestoneID int) error {
+	f.record("SetMRMilestone", pid, iid, milestoneID)
+
+	if f.SetMRMilestoneFunc != nil {
+		return f.SetMRMilestoneFunc(pid, iid, milestoneID)
+	}
+
+	return nil
+}
+
+func (f *Client) ListIssueNotes(pid interface{}, iid int) ([]*gitlab.Note, error) {
+	f.record("ListIssueNotes", pid, iid)
+
+	if f.ListIssueNotesFunc != nil {
+		return f.ListIssueNotesFunc(pid, iid)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) CreateIssueComment(pid interface{}, iid int, body string) (*gitlab.Note, error) {
+	f.record("CreateIssueComment", pid, iid, body)
+
+	if f.CreateIssueCommentFunc != nil {
+		return f.CreateIssueCommentFunc(pid, iid, body)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) UpdateIssueComment(pid interface{}, iid, noteID int, body string) (*gitlab.Note, error) {
+	f.record("UpdateIssueComment", pid, iid, noteID, body)
+
+	if f.UpdateIssueCommentFunc != nil {
+		return f.UpdateIssueCommentFunc(pid, iid, noteID, body)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) DeleteIssueComment(pid interface{}, iid, noteID int) error {
+	f.record("DeleteIssueComment", pid, iid, noteID)
+
+	if f.DeleteIssueCommentFunc != nil {
+		return f.DeleteIssueCommentFunc(pid, iid, noteID)
+	}
+
+	return nil
+}
+
+func (f *Client) ListMRNotes(pid interface{}, iid int) ([]*gitlab.Note, error) {
+	f.record("ListMRNotes", pid, iid)
+
+	if f.ListMRNotesFunc != nil {
+		return f.ListMRNotesFunc(pid, iid)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) CreateMRComment(pid interface{}, iid int, body string) (*gitlab.Note, error) {
+	f.record("CreateMRComment", pid, iid, body)
+
+	if f.CreateMRCommentFunc != nil {
+		return f.CreateMRCommentFunc(pid, iid, body)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) UpdateMRComment(pid interface{}, iid, noteID int, body string) (*gitlab.Note, error) {
+	f.record("UpdateMRComment", pid, iid, noteID, body)
+
+	if f.UpdateMRCommentFunc != nil {
+		return f.UpdateMRCommentFunc(pid, iid, noteID, body)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) DeleteMRComment(pid interface{}, iid, noteID int) error {
+	f.record("DeleteMRComment", pid, iid, noteID)
+
+	if f.DeleteMRCommentFunc != nil {
+		return f.DeleteMRCommentFunc(pid, iid, noteID)
+	}
+
+	return nil
+}
+
+func (f *Client) CreatePipeline(pid interface{}, ref string, variables map[string]string) (*gitlab.Pipeline, error) {
+	f.record("CreatePipeline", pid, ref, variables)
+
+	if f.CreatePipelineFunc != nil {
+		return f.CreatePipelineFunc(pid, ref, variables)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) RetryPipeline(pid interface{}, pipelineID int) (*gitlab.Pipeline, error) {
+	f.record("RetryPipeline", pid, pipelineID)
+
+	if f.RetryPipelineFunc != nil {
+		return f.RetryPipelineFunc(pid, pipelineID)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) CancelPipeline(pid interface{}, pipelineID int) (*gitlab.Pipeline, error) {
+	f.record("CancelPipeline", pid, pipelineID)
+
+	if f.CancelPipelineFunc != nil {
+		return f.CancelPipelineFunc(pid, pipelineID)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) CreateProject(opts client.CreateProjectOptions) (*gitlab.Project, error) {
+	f.record("CreateProject", opts)
+
+	if f.CreateProjectFunc != nil {
+		return f.CreateProjectFunc(opts)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) ForkProject(pid interface{}, opts client.ForkProjectOptions) (*gitlab.Project, error) {
+	f.record("ForkProject", pid, opts)
+
+	if f.ForkProjectFunc != nil {
+		return f.ForkProjectFunc(pid, opts)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) ListProjectEvents(pid interface{}, after time.Time) ([]*gitlab.ProjectEvent, error) {
+	f.record("ListProjectEvents", pid, after)
+
+	if f.ListProjectEventsFunc != nil {
+		return f.ListProjectEventsFunc(pid, after)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) ListNewProjectEvents(pid interface{}, lastID, limit int) ([]*gitlab.ProjectEvent, error) {
+	f.record("ListNewProjectEvents", pid, lastID, limit)
+
+	if f.ListNewProjectEventsFunc != nil {
+		return f.ListNewProjectEventsFunc(pid, lastID, limit)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) RateLimit() client.RateLimitState {
+	f.record("RateLimit")
+
+	if f.RateLimitFunc != nil {
+		return f.RateLimitFunc()
+	}
+
+	return client.RateLimitState{}
+}
+
+func (f *Client) CreateRelease(pid interface{}, tag string, opts client.ReleaseOptions) (*gitlab.Release, error) {
+	f.record("CreateRelease", pid, tag, opts)
+
+	if f.CreateReleaseFunc != nil {
+		return f.CreateReleaseFunc(pid, tag, opts)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) UpdateRelease(pid interface{}, tag string, opts client.ReleaseOptions) (*gitlab.Release, error) {
+	f.record("UpdateRelease", pid, tag, opts)
+
+	if f.UpdateReleaseFunc != nil {
+		return f.UpdateReleaseFunc(pid, tag, opts)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) ListReleases(pid interface{}) ([]*gitlab.Release, error) {
+	f.record("ListReleases", pid)
+
+	if f.ListReleasesFunc != nil {
+		return f.ListReleasesFunc(pid)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) GetPathContent(pid interface{}, path, ref string) ([]byte, error) {
+	f.record("GetPathContent", pid, path, ref)
+
+	if f.GetPathContentFunc != nil {
+		return f.GetPathContentFunc(pid, path, ref)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) GetDirectoryTree(pid interface{}, path, ref string) ([]*gitlab.TreeNode, error) {
+	f.record("GetDirectoryTree", pid, path, ref)
+
+	if f.GetDirectoryTreeFunc != nil {
+		return f.GetDirectoryTreeFunc(pid, path, ref)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) ListRepositoryTree(pid interface{}, path, ref string, recursive bool) ([]*gitlab.TreeNode, error) {
+	f.record("ListRepositoryTree", pid, path, ref, recursive)
+
+	if f.ListRepositoryTreeFunc != nil {
+		return f.ListRepositoryTreeFunc(pid, path, ref, recursive)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) DownloadArchive(pid interface{}, ref, format string, w io.Writer) error {
+	f.record("DownloadArchive", pid, ref, format, w)
+
+	if f.DownloadArchiveFunc != nil {
+		return f.DownloadArchiveFunc(pid, ref, format, w)
+	}
+
+	return nil
+}
+
+func (f *Client) Compare(pid interface{}, from, to string) (*gitlab.Compare, error) {
+	f.record("Compare", pid, from, to)
+
+	if f.CompareFunc != nil {
+		return f.CompareFunc(pid, from, to)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) SearchIssues(pid interface{}, query string, limit int) ([]*gitlab.Issue, error) {
+	f.record("SearchIssues", pid, query, limit)
+
+	if f.SearchIssuesFunc != nil {
+		return f.SearchIssuesFunc(pid, query, limit)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) SearchMergeRequests(pid interface{}, query string, limit int) ([]*gitlab.MergeRequest, error) {
+	f.record("SearchMergeRequests", pid, query, limit)
+
+	if f.SearchMergeRequestsFunc != nil {
+		return f.SearchMergeRequestsFunc(pid, query, limit)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) SearchCode(pid interface{}, query, ref string, limit int) ([]*gitlab.Blob, error) {
+	f.record("SearchCode", pid, query, ref, limit)
+
+	if f.SearchCodeFunc != nil {
+		return f.SearchCodeFunc(pid, query, ref, limit)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) CreateTag(pid interface{}, tag, ref, message string) (*gitlab.Tag, error) {
+	f.record("CreateTag", pid, tag, ref, message)
+
+	if f.CreateTagFunc != nil {
+		return f.CreateTagFunc(pid, tag, ref, message)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) DeleteTag(pid interface{}, tag string) error {
+	f.record("DeleteTag", pid, tag)
+
+	if f.DeleteTagFunc != nil {
+		return f.DeleteTagFunc(pid, tag)
+	}
+
+	return nil
+}
+
+func (f *Client) AddIssueSpentTime(pid interface{}, iid int, duration, summary string) (*gitlab.TimeStats, error) {
+	f.record("AddIssueSpentTime", pid, iid, duration, summary)
+
+	if f.AddIssueSpentTimeFunc != nil {
+		return f.AddIssueSpentTimeFunc(pid, iid, duration, summary)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) SetIssueTimeEstimate(pid interface{}, iid int, duration string) (*gitlab.TimeStats, error) {
+	f.record("SetIssueTimeEstimate", pid, iid, duration)
+
+	if f.SetIssueTimeEstimateFunc != nil {
+		return f.SetIssueTimeEstimateFunc(pid, iid, duration)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) AddMRSpentTime(pid interface{}, iid int, duration, summary string) (*gitlab.TimeStats, error) {
+	f.record("AddMRSpentTime", pid, iid, duration, summary)
+
+	if f.AddMRSpentTimeFunc != nil {
+		return f.AddMRSpentTimeFunc(pid, iid, duration, summary)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) SetMRTimeEstimate(pid interface{}, iid int, duration string) (*gitlab.TimeStats, error) {
+	f.record("SetMRTimeEstimate", pid, iid, duration)
+
+	if f.SetMRTimeEstimateFunc != nil {
+		return f.SetMRTimeEstimateFunc(pid, iid, duration)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) GetUserByUsername(username string) (*gitlab.User, error) {
+	f.record("GetUserByUsername", username)
+
+	if f.GetUserByUsernameFunc != nil {
+		return f.GetUserByUsernameFunc(username)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) GetUserByID(id int) (*gitlab.User, error) {
+	f.record("GetUserByID", id)
+
+	if f.GetUserByIDFunc != nil {
+		return f.GetUserByIDFunc(id)
+	}
+
+	return nil, nil
+}
+
+func (f *Client) GetCurrentUser() (*gitlab.User, error) {
+	f.record("GetCurrentUser")
+
+	if f.GetCurrentUserFunc != nil {
+		return f.GetCurrentUserFunc()
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,73 @@
+package client
+
+import (
+	"encoding/base64"
+
+	"github.com/xanzy/go-gitlab"
+)
+
+// FileCommitOptions describe the commit made by CreateFile, UpdateFile
+// and DeleteFile.
+type FileCommitOptions struct {
+	// Branch is the branch to commit to.
+	Branch string
+
+	// StartBranch, if not empty, is the branch to create Branch from
+	// when Branch doesn't exist.
+	StartBranch string
+
+	CommitMessage string
+
+	// AuthorName and AuthorEmail default to the user owning the token.
+	AuthorName  string
+	AuthorEmail string
+
+	// LastCommitID, if not empty, is the last known commit of the file.
+	// UpdateFile and DeleteFile fail if the file was changed since then.
+	LastCommitID string
+}
+
+// CreateFile commits a new file at path with the content.
+func (cli *Client) CreateFile(pid interface{}, path string, content []byte, opts FileCommitOptions) error {
+	_, _, err := cli.c.RepositoryFiles.CreateFile(pid, path, &gitlab.CreateFileOptions{
+		Branch:        gitlab.Ptr(opts.Branch),
+		StartBranch:   optional(opts.StartBranch),
+		Encoding:      gitlab.Ptr("base64"),
+		AuthorName:    optional(opts.AuthorName),
+		AuthorEmail:   optional(opts.AuthorEmail),
+		Content:       gitlab.Ptr(base64.StdEncoding.EncodeToString(content)),
+		CommitMessage: gitlab.Ptr(opts.CommitMessage),
+	})
+
+	return err
+}
+
+// UpdateFile commits the new content of the existing file at path.
+func (cli *Client) UpdateFile(pid interface{}, path string, content []byte, opts FileCommitOptions) error {
+	_, _, err := cli.c.RepositoryFiles.UpdateFile(pid, path, &gitlab.UpdateFileOptions{
+		Branch:        gitlab.Ptr(opts.Branch),
+		StartBranch:   optional(opts.StartBranch),
+		Encoding:      gitlab.Ptr("base64"),
+		AuthorName:    optional(opts.AuthorName),
+		AuthorEmail:   optional(opts.AuthorEmail),
+		Content:       gitlab.Ptr(base64.StdEncoding.EncodeToString(content)),
+		CommitMessage: gitlab.Ptr(opts.CommitMessage),
+		LastCommitID:  optional(opts.LastCommitID),
+	})
+
+	return err
+}
+
+// DeleteFile commits the removal of the file at path.
+func (cli *Client) DeleteFile(pid interface{}, path string, opts FileCommitOptions) error {
+	_, err := cli.c.RepositoryFiles.DeleteFile(pid, path, &gitlab.DeleteFileOptions{
+		Branch:        gitlab.Ptr(opts.Branch),
+		StartBranch:   optional(opts.StartBranch),
+		AuthorName:    optional(opts.AuthorName),
+		AuthorEmail:   optional(opts.AuthorEmail),
+		CommitMessage: gitlab.Ptr(opts.CommitMessage),
+		LastCommitID:  optional(opts.LastCommitID),
+	})
+
+	return err
+}
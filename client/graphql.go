@@ -0,0 +1,84 @@
+package client
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// GraphQLError is returned by GraphQL when GitLab reports errors for the
+// query. The data which was resolved is still decoded into the result.
+type GraphQLError struct {
+	Errors []GraphQLErrorItem
+}
+
+// GraphQLErrorItem is one of the errors reported for a GraphQL query.
+type GraphQLErrorItem struct {
+	Message string `json:"message"`
+
+	// Path is the path of the field which the error occurred on.
+	Path []interface{} `json:"path"`
+
+	Locations []struct {
+		Line   int `json:"line"`
+		Column int `json:"column"`
+	} `json:"locations"`
+}
+
+func (e *GraphQLError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i := range e.Errors {
+		msgs[i] = e.Errors[i].Message
+	}
+
+	return "graphql: " + strings.Join(msgs, "; ")
+}
+
+type graphQLRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables,omitempty"`
+}
+
+type graphQLResponse struct {
+	Data   json.RawMessage    `json:"data"`
+	Errors []GraphQLErrorItem `json:"errors"`
+}
+
+// GraphQL runs the query, or the mutation, with the variables and decodes
+// the data field of the response into result, which can be nil. The
+// request is sent by the same HTTP client and the same token as the REST
+// APIs are.
+func (cli *Client) GraphQL(query string, variables map[string]interface{}, result interface{}) error {
+	req, err := cli.c.NewRequest(
+		http.MethodPost, "", &graphQLRequest{Query: query, Variables: variables}, nil,
+	)
+	if err != nil {
+		return err
+	}
+
+	req.URL = cli.graphQLURL()
+
+	var resp graphQLResponse
+	if _, err := cli.c.Do(req, &resp); err != nil {
+		return err
+	}
+
+	if result != nil && len(resp.Data) > 0 && string(resp.Data) != "null" {
+		if err := json.Unmarshal(resp.Data, result); err != nil {
+			return err
+		}
+	}
+
+	if len(resp.Errors) > 0 {
+		return &GraphQLError{Errors: resp.Errors}
+	}
+
+	return nil
+}
+
+// graphQLURL returns the endpoint of the GraphQL API, which is a sibling
+// of the REST API base URL, such as https://gitlab.com/api/graphql.
+func (cli *Client) graphQLURL() *url.URL {
+	return cli.c.BaseURL().ResolveReference(&url.URL{Path: "../graphql"})
+}
@@ -0,0 +1,55 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/xanzy/go-gitlab"
+)
+
+// ListGroupProjects returns all the projects of the group, which is the
+// numeric ID or the full path of the group. The projects of the subgroups
+// are included if includeSubgroups is true.
+func (cli *Client) ListGroupProjects(gid interface{}, includeSubgroups bool) ([]*gitlab.Project, error) {
+	v := &gitlab.ListGroupProjectsOptions{IncludeSubGroups: gitlab.Ptr(includeSubgroups)}
+
+	return CollectAll(func(opts *gitlab.ListOptions) ([]*gitlab.Project, *gitlab.Response, error) {
+		v.ListOptions = *opts
+
+		return cli.c.Groups.ListGroupProjects(gid, v)
+	})
+}
+
+// ExpandProjects returns the full paths of the projects and all the
+// projects of the groups, including the ones of the subgroups, without
+// duplicates. It goes on with the other groups if it fails to list one,
+// and returns the errors with the projects found.
+func ExpandProjects(cli Interface, projects, groups []string) ([]string, error) {
+	all := append([]string{}, projects...)
+
+	var errs []error
+	for _, g := range groups {
+		v, err := cli.ListGroupProjects(g, true)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("list projects of group %s: %w", g, err))
+
+			continue
+		}
+
+		for _, p := range v {
+			all = append(all, p.PathWithNamespace)
+		}
+	}
+
+	r := make([]string, 0, len(all))
+	seen := make(map[string]bool, len(all))
+
+	for _, p := range all {
+		if !seen[p] {
+			seen[p] = true
+			r = append(r, p)
+		}
+	}
+
+	return r, errors.Join(errs...)
+}
@@ -0,0 +1,161 @@
+package client
+
+import (
+	"github.com/xanzy/go-gitlab"
+)
+
+// HookOptions are the settings of a webhook. The events not enabled are
+// not delivered.
+type HookOptions struct {
+	URL string
+
+	// Token is the secret token sent in the X-Gitlab-Token header.
+	Token string
+
+	PushEvents               bool
+	TagPushEvents            bool
+	MergeRequestsEvents      bool
+	IssuesEvents             bool
+	ConfidentialIssuesEvents bool
+	NoteEvents               bool
+	ConfidentialNoteEvents   bool
+	PipelineEvents           bool
+	JobEvents                bool
+	ReleasesEvents           bool
+
+	// MemberEvents and SubGroupEvents are supported by the group webhooks
+	// only.
+	MemberEvents   bool
+	SubGroupEvents bool
+
+	// PushEventsBranchFilter limits the push events to the branches
+	// matching the wildcard. All the branches are included if empty.
+	PushEventsBranchFilter string
+
+	EnableSSLVerification bool
+}
+
+// ListProjectHooks returns the webhooks of the project.
+func (cli *Client) ListProjectHooks(pid interface{}) ([]*gitlab.ProjectHook, error) {
+	return CollectAll(func(opts *gitlab.ListOptions) ([]*gitlab.ProjectHook, *gitlab.Response, error) {
+		v := gitlab.ListProjectHooksOptions(*opts)
+
+		return cli.c.Projects.ListProjectHooks(pid, &v)
+	})
+}
+
+// AddProjectHook adds a webhook to the project.
+func (cli *Client) AddProjectHook(pid interface{}, opts HookOptions) (*gitlab.ProjectHook, error) {
+	v, _, err := cli.c.Projects.AddProjectHook(pid, &gitlab.AddProjectHookOptions{
+		URL:                      gitlab.Ptr(opts.URL),
+		Token:                    optional(opts.Token),
+		PushEvents:               gitlab.Ptr(opts.PushEvents),
+		TagPushEvents:            gitlab.Ptr(opts.TagPushEvents),
+		MergeRequestsEvents:      gitlab.Ptr(opts.MergeRequestsEvents),
+		IssuesEvents:             gitlab.Ptr(opts.IssuesEvents),
+		ConfidentialIssuesEvents: gitlab.Ptr(opts.ConfidentialIssuesEvents),
+		NoteEvents:               gitlab.Ptr(opts.NoteEvents),
+		ConfidentialNoteEvents:   gitlab.Ptr(opts.ConfidentialNoteEvents),
+		PipelineEvents:           gitlab.Ptr(opts.PipelineEvents),
+		JobEvents:                gitlab.Ptr(opts.JobEvents),
+		ReleasesEvents:           gitlab.Ptr(opts.ReleasesEvents),
+		PushEventsBranchFilter:   optional(opts.PushEventsBranchFilter),
+		EnableSSLVerification:    gitlab.Ptr(opts.EnableSSLVerification),
+	})
+
+	return v, err
+}
+
+// EditProjectHook replaces the settings of the webhook of the project.
+func (cli *Client) EditProjectHook(pid interface{}, hookID int, opts HookOptions) (*gitlab.ProjectHook, error) {
+	v, _, err := cli.c.Projects.EditProjectHook(pid, hookID, &gitlab.EditProjectHookOptions{
+		URL:                      gitlab.Ptr(opts.URL),
+		Token:                    optional(opts.Token),
+		PushEvents:               gitlab.Ptr(opts.PushEvents),
+		TagPushEvents:            gitlab.Ptr(opts.TagPushEvents),
+		MergeRequestsEvents:      gitlab.Ptr(opts.MergeRequestsEvents),
+		IssuesEvents:             gitlab.Ptr(opts.IssuesEvents),
+		ConfidentialIssuesEvents: gitlab.Ptr(opts.ConfidentialIssuesEvents),
+		NoteEvents:               gitlab.Ptr(opts.NoteEvents),
+		ConfidentialNoteEvents:   gitlab.Ptr(opts.ConfidentialNoteEvents),
+		PipelineEvents:           gitlab.Ptr(opts.PipelineEvents),
+		JobEvents:                gitlab.Ptr(opts.JobEvents),
+		ReleasesEvents:           gitlab.Ptr(opts.ReleasesEvents),
+		PushEventsBranchFilter:   gitlab.Ptr(opts.PushEventsBranchFilter),
+		EnableSSLVerification:    gitlab.Ptr(opts.EnableSSLVerification),
+	})
+
+	return v, err
+}
+
+// DeleteProjectHook deletes the webhook of the project.
+func (cli *Client) DeleteProjectHook(pid interface{}, hookID int) error {
+	_, err := cli.c.Projects.DeleteProjectHook(pid, hookID)
+
+	return err
+}
+
+// ListGroupHooks returns the webhooks of the group.
+func (cli *Client) ListGroupHooks(gid interface{}) ([]*gitlab.GroupHook, error) {
+	return CollectAll(func(opts *gitlab.ListOptions) ([]*gitlab.GroupHook, *gitlab.Response, error) {
+		v := gitlab.ListGroupHooksOptions(*opts)
+
+		return cli.c.Groups.ListGroupHooks(gid, &v)
+	})
+}
+
+// AddGroupHook adds a webhook to the group, which delivers the events of
+// all the projects of the group and its subgroups.
+func (cli *Client) AddGroupHook(gid interface{}, opts HookOptions) (*gitlab.GroupHook, error) {
+	v, _, err := cli.c.Groups.AddGroupHook(gid, &gitlab.AddGroupHookOptions{
+		URL:                      gitlab.Ptr(opts.URL),
+		Token:                    optional(opts.Token),
+		PushEvents:               gitlab.Ptr(opts.PushEvents),
+		TagPushEvents:            gitlab.Ptr(opts.TagPushEvents),
+		MergeRequestsEvents:      gitlab.Ptr(opts.MergeRequestsEvents),
+		IssuesEvents:             gitlab.Ptr(opts.IssuesEvents),
+		ConfidentialIssuesEvents: gitlab.Ptr(opts.ConfidentialIssuesEvents),
+		NoteEvents:               gitlab.Ptr(opts.NoteEvents),
+		ConfidentialNoteEvents:   gitlab.Ptr(opts.ConfidentialNoteEvents),
+		PipelineEvents:           gitlab.Ptr(opts.PipelineEvents),
+		JobEvents:                gitlab.Ptr(opts.JobEvents),
+		ReleasesEvents:           gitlab.Ptr(opts.ReleasesEvents),
+		MemberEvents:             gitlab.Ptr(opts.MemberEvents),
+		SubGroupEvents:           gitlab.Ptr(opts.SubGroupEvents),
+		PushEventsBranchFilter:   optional(opts.PushEventsBranchFilter),
+		EnableSSLVerification:    gitlab.Ptr(opts.EnableSSLVerification),
+	})
+
+	return v, err
+}
+
+// EditGroupHook replaces the settings of the webhook of the group.
+func (cli *Client) EditGroupHook(gid interface{}, hookID int, opts HookOptions) (*gitlab.GroupHook, error) {
+	v, _, err := cli.c.Groups.EditGroupHook(gid, hookID, &gitlab.EditGroupHookOptions{
+		URL:                      gitlab.Ptr(opts.URL),
+		Token:                    optional(opts.Token),
+		PushEvents:               gitlab.Ptr(opts.PushEvents),
+		TagPushEvents:            gitlab.Ptr(opts.TagPushEvents),
+		MergeRequestsEvents:      gitlab.Ptr(opts.MergeRequestsEvents),
+		IssuesEvents:             gitlab.Ptr(opts.IssuesEvents),
+		ConfidentialIssuesEvents: gitlab.Ptr(opts.ConfidentialIssuesEvents),
+		NoteEvents:               gitlab.Ptr(opts.NoteEvents),
+		ConfidentialNoteEvents:   gitlab.Ptr(opts.ConfidentialNoteEvents),
+		PipelineEvents:           gitlab.Ptr(opts.PipelineEvents),
+		JobEvents:                gitlab.Ptr(opts.JobEvents),
+		ReleasesEvents:           gitlab.Ptr(opts.ReleasesEvents),
+		MemberEvents:             gitlab.Ptr(opts.MemberEvents),
+		SubGroupEvents:           gitlab.Ptr(opts.SubGroupEvents),
+		PushEventsBranchFilter:   gitlab.Ptr(opts.PushEventsBranchFilter),
+		EnableSSLVerification:    gitlab.Ptr(opts.EnableSSLVerification),
+	})
+
+	return v, err
+}
+
+// DeleteGroupHook deletes the webhook of the group.
+func (cli *Client) DeleteGroupHook(gid interface{}, hookID int) error {
+	_, err := cli.c.Groups.DeleteGroupHook(gid, hookID)
+
+	return err
+}
@@ -0,0 +1,196 @@
+package client
+
+import (
+	"io"
+	"time"
+
+	"github.com/xanzy/go-gitlab"
+)
+
+// Interface is the set of operations of Client. Robots should depend on it
+// rather than on Client, so that their handlers can be tested with the fake
+// in the fake package.
+type Interface interface {
+	// Approvals
+	ApproveMR(pid interface{}, iid int) error
+	UnapproveMR(pid interface{}, iid int) error
+	GetMRApprovalState(pid interface{}, iid int) (MRApprovalState, error)
+	ListMRApprovalRules(pid interface{}, iid int) ([]*gitlab.MergeRequestApprovalRule, error)
+	CreateMRApprovalRule(pid interface{}, iid int, opts ApprovalRuleOptions) (*gitlab.MergeRequestApprovalRule, error)
+	UpdateMRApprovalRule(pid interface{}, iid, ruleID int, opts ApprovalRuleOptions) (*gitlab.MergeRequestApprovalRule, error)
+	ListProjectApprovalRules(pid interface{}) ([]*gitlab.ProjectApprovalRule, error)
+	CreateProjectApprovalRule(pid interface{}, opts ApprovalRuleOptions, protectedBranchIDs []int) (*gitlab.ProjectApprovalRule, error)
+	UpdateProjectApprovalRule(pid interface{}, ruleID int, opts ApprovalRuleOptions, protectedBranchIDs []int) (*gitlab.ProjectApprovalRule, error)
+
+	// Award emoji
+	AddAwardEmoji(pid interface{}, item Awardable, name string) (*gitlab.AwardEmoji, error)
+	RemoveAwardEmoji(pid interface{}, item Awardable, awardID int) error
+	ListAwardEmoji(pid interface{}, item Awardable) ([]*gitlab.AwardEmoji, error)
+
+	// Branches
+	GetBranch(pid interface{}, branch string) (*gitlab.Branch, error)
+	CreateBranch(pid interface{}, branch, ref string) (*gitlab.Branch, error)
+	DeleteBranch(pid interface{}, branch string) error
+	ProtectBranch(pid interface{}, branch string, opts ProtectBranchOptions) (*gitlab.ProtectedBranch, error)
+	UpdateProtectedBranch(pid interface{}, pb *gitlab.ProtectedBranch, opts ProtectBranchOptions) (*gitlab.ProtectedBranch, error)
+	UnprotectBranch(pid interface{}, branch string) error
+	ListProtectedBranches(pid interface{}) ([]*gitlab.ProtectedBranch, error)
+
+	// Circuit breaker
+	CircuitOpen() bool
+
+	// Commits
+	ListMRCommits(pid interface{}, iid int) ([]MRCommit, error)
+	SetCommitStatus(pid interface{}, sha string, state gitlab.BuildStateValue, name, targetURL, description string) error
+	GetCommitStatuses(pid interface{}, sha string) ([]*gitlab.CommitStatus, error)
+	CherryPickCommit(pid interface{}, sha, targetBranch string) (*gitlab.Commit, error)
+	RevertCommit(pid interface{}, sha, branch string) (*gitlab.Commit, error)
+
+	// Diffs
+	GetMRChangedFiles(pid interface{}, iid int) ([]ChangedFile, error)
+
+	// Discussions
+	CreateMRDiscussion(pid interface{}, iid int, body string, pos *DiffPosition) (*gitlab.Discussion, error)
+	ReplyToDiscussion(pid interface{}, iid int, discussionID, body string) (*gitlab.Note, error)
+	ResolveDiscussion(pid interface{}, iid int, discussionID string, resolved bool) error
+	LockIssueDiscussion(pid interface{}, iid int) error
+	UnlockIssueDiscussion(pid interface{}, iid int) error
+	LockMRDiscussion(pid interface{}, iid int) error
+	UnlockMRDiscussion(pid interface{}, iid int) error
+
+	// Files
+	CreateFile(pid interface{}, path string, content []byte, opts FileCommitOptions) error
+	UpdateFile(pid interface{}, path string, content []byte, opts FileCommitOptions) error
+	DeleteFile(pid interface{}, path string, opts FileCommitOptions) error
+
+	// GraphQL
+	GraphQL(query string, variables map[string]interface{}, result interface{}) error
+
+	// Groups
+	ListGroupProjects(gid interface{}, includeSubgroups bool) ([]*gitlab.Project, error)
+
+	// Hooks
+	ListProjectHooks(pid interface{}) ([]*gitlab.ProjectHook, error)
+	AddProjectHook(pid interface{}, opts HookOptions) (*gitlab.ProjectHook, error)
+	EditProjectHook(pid interface{}, hookID int, opts HookOptions) (*gitlab.ProjectHook, error)
+	DeleteProjectHook(pid interface{}, hookID int) error
+	ListGroupHooks(gid interface{}) ([]*gitlab.GroupHook, error)
+	AddGroupHook(gid interface{}, opts HookOptions) (*gitlab.GroupHook, error)
+	EditGroupHook(gid interface{}, hookID int, opts HookOptions) (*gitlab.GroupHook, error)
+	DeleteGroupHook(gid interface{}, hookID int) error
+
+	// Issues
+	GetIssue(pid interface{}, iid int) (*gitlab.Issue, error)
+	GetIssueDetails(pid interface{}, iid int) (*gitlab.Issue, error)
+	ListIssues(pid interface{}, opts ListIssuesOptions) ([]*gitlab.Issue, error)
+	ListGroupIssues(gid interface{}, opts ListIssuesOptions) ([]*gitlab.Issue, error)
+	CloseIssue(pid interface{}, iid int) error
+	ReopenIssue(pid interface{}, iid int) error
+	SetIssueDueDate(pid interface{}, iid int, due time.Time) error
+	ClearIssueDueDate(pid interface{}, iid int) error
+	SubscribeToIssue(pid interface{}, iid int, username string) error
+	UnsubscribeFromIssue(pid interface{}, iid int, username string) error
+
+	// Issue links
+	LinkIssues(pid interface{}, iid int, targetPID interface{}, targetIID int, linkType IssueLinkType) error
+	ListIssueLinks(pid interface{}, iid int) ([]*gitlab.IssueRelation, error)
+
+	// Jobs
+	ListPipelineJobs(pid interface{}, pipelineID int, includeRetried bool) ([]*gitlab.Job, error)
+	GetJobTrace(pid interface{}, jobID int, limit int) ([]byte, error)
+
+	// Labels
+	ListLabels(pid interface{}) ([]*gitlab.Label, error)
+	CreateLabel(pid interface{}, opts LabelOptions) (*gitlab.Label, error)
+	UpdateLabel(pid interface{}, name string, opts LabelOptions) (*gitlab.Label, error)
+	DeleteLabel(pid interface{}, name string) error
+	SetIssueLabels(pid interface{}, iid int, update func(current []string) []string) error
+	SetMRLabels(pid interface{}, iid int, update func(current []string) []string) error
+
+	// Members
+	ListProjectMembers(pid interface{}) ([]*gitlab.ProjectMember, error)
+	GetUserPermission(pid interface{}, username string) (gitlab.AccessLevelValue, error)
+	IsProjectMember(pid interface{}, username string) (bool, error)
+	HasWriteAccess(pid interface{}, username string) (bool, error)
+	IsMaintainer(pid interface{}, username string) (bool, error)
+	InvalidatePermissions(username string)
+
+	// Merge requests
+	GetMR(pid interface{}, iid int) (*gitlab.MergeRequest, error)
+	GetMRDetails(pid interface{}, iid int) (*gitlab.MergeRequest, error)
+	ListMRs(pid interface{}, opts ListMRsOptions) ([]*gitlab.MergeRequest, error)
+	ListOpenMRsTargeting(pid interface{}, branch string) ([]*gitlab.MergeRequest, error)
+	MergeMR(pid interface{}, iid int, opts MergeMROptions) error
+	CloseMR(pid interface{}, iid int) error
+	ReopenMR(pid interface{}, iid int) error
+	UpdateMRTitle(pid interface{}, iid int, title string) error
+	UpdateMRDescription(pid interface{}, iid int, desc string) error
+	SetMRDraft(pid interface{}, iid int) error
+	SetMRReady(pid interface{}, iid int) error
+	RebaseMR(pid interface{}, iid int) error
+	WaitForRebase(pid interface{}, iid int, before *gitlab.MergeRequest, timeout time.Duration) error
+
+	// Milestones
+	CreateMilestone(pid interface{}, title, description string, dueDate *time.Time) (*gitlab.Milestone, error)
+	ListMilestones(pid interface{}, opts ListMilestonesOptions) ([]*gitlab.Milestone, error)
+	SetIssueMilestone(pid interface{}, iid, milestoneID int) error
+	SetMRMilestone(pid interface{}, iid, milestoneID int) error
+
+	// Notes
+	ListIssueNotes(pid interface{}, iid int) ([]*gitlab.Note, error)
+	CreateIssueComment(pid interface{}, iid int, body string) (*gitlab.Note, error)
+	UpdateIssueComment(pid interface{}, iid, noteID int, body string) (*gitlab.Note, error)
+	DeleteIssueComment(pid interface{}, iid, noteID int) error
+	ListMRNotes(pid interface{}, iid int) ([]*gitlab.Note, error)
+	CreateMRComment(pid interface{}, iid int, body string) (*gitlab.Note, error)
+	UpdateMRComment(pid interface{}, iid, noteID int, body string) (*gitlab.Note, error)
+	DeleteMRComment(pid interface{}, iid, noteID int) error
+
+	// Pipelines
+	CreatePipeline(pid interface{}, ref string, variables map[string]string) (*gitlab.Pipeline, error)
+	RetryPipeline(pid interface{}, pipelineID int) (*gitlab.Pipeline, error)
+	CancelPipeline(pid interface{}, pipelineID int) (*gitlab.Pipeline, error)
+
+	// Projects
+	CreateProject(opts CreateProjectOptions) (*gitlab.Project, error)
+	ForkProject(pid interface{}, opts ForkProjectOptions) (*gitlab.Project, error)
+	ListProjectEvents(pid interface{}, after time.Time) ([]*gitlab.ProjectEvent, error)
+	ListNewProjectEvents(pid interface{}, lastID, limit int) ([]*gitlab.ProjectEvent, error)
+
+	// Rate limit
+	RateLimit() RateLimitState
+
+	// Releases
+	CreateRelease(pid interface{}, tag string, opts ReleaseOptions) (*gitlab.Release, error)
+	UpdateRelease(pid interface{}, tag string, opts ReleaseOptions) (*gitlab.Release, error)
+	ListReleases(pid interface{}) ([]*gitlab.Release, error)
+
+	// Repository
+	GetPathContent(pid interface{}, path, ref string) ([]byte, error)
+	GetDirectoryTree(pid interface{}, path, ref string) ([]*gitlab.TreeNode, error)
+	ListRepositoryTree(pid interface{}, path, ref string, recursive bool) ([]*gitlab.TreeNode, error)
+	DownloadArchive(pid interface{}, ref, format string, w io.Writer) error
+	Compare(pid interface{}, from, to string) (*gitlab.Compare, error)
+
+	// Search
+	SearchIssues(pid interface{}, query string, limit int) ([]*gitlab.Issue, error)
+	SearchMergeRequests(pid interface{}, query string, limit int) ([]*gitlab.MergeRequest, error)
+	SearchCode(pid interface{}, query, ref string, limit int) ([]*gitlab.Blob, error)
+
+	// Tags
+	CreateTag(pid interface{}, tag, ref, message string) (*gitlab.Tag, error)
+	DeleteTag(pid interface{}, tag string) error
+
+	// Time tracking
+	AddIssueSpentTime(pid interface{}, iid int, duration, summary string) (*gitlab.TimeStats, error)
+	SetIssueTimeEstimate(pid interface{}, iid int, duration string) (*gitlab.TimeStats, error)
+	AddMRSpentTime(pid interface{}, iid int, duration, summary string) (*gitlab.TimeStats, error)
+	SetMRTimeEstimate(pid interface{}, iid int, duration string) (*gitlab.TimeStats, error)
+
+	// Users
+	GetUserByUsername(username string) (*gitlab.User, error)
+	GetUserByID(id int) (*gitlab.User, error)
+	GetCurrentUser() (*gitlab.User, error)
+}
+
+var _ Interface = (*Client)(nil)
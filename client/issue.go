@@ -0,0 +1,165 @@
+package client
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/xanzy/go-gitlab"
+)
+
+// GetIssue returns the issue.
+func (cli *Client) GetIssue(pid interface{}, iid int) (*gitlab.Issue, error) {
+	v, _, err := cli.c.Issues.GetIssue(pid, iid)
+
+	return v, err
+}
+
+// GetIssueDetails returns the issue with the details of its labels, which
+// are left out by GetIssue.
+func (cli *Client) GetIssueDetails(pid interface{}, iid int) (*gitlab.Issue, error) {
+	// Only the list API returns the details of the labels.
+	v, _, err := cli.c.Issues.ListProjectIssues(pid, &gitlab.ListProjectIssuesOptions{
+		IIDs:             gitlab.Ptr([]int{iid}),
+		WithLabelDetails: gitlab.Ptr(true),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(v) == 0 {
+		return nil, gitlab.ErrNotFound
+	}
+
+	return v[0], nil
+}
+
+// ListIssuesOptions are the options of ListIssues and ListGroupIssues. The
+// zero value lists all the issues.
+type ListIssuesOptions struct {
+	// State is opened or closed.
+	State string
+
+	// Labels are the labels the issues must all have.
+	Labels []string
+
+	// Milestone is the title of the milestone. None and Any match the
+	// issues without and with a milestone.
+	Milestone string
+
+	// AuthorUsername is the username of the author.
+	AuthorUsername string
+
+	// Search matches the title or the description of the issue.
+	Search string
+}
+
+// ListIssues returns all the issues of the project which match the
+// options, the most recently created first.
+func (cli *Client) ListIssues(pid interface{}, opts ListIssuesOptions) ([]*gitlab.Issue, error) {
+	v := &gitlab.ListProjectIssuesOptions{
+		State:          optional(opts.State),
+		Labels:         labelOptions(opts.Labels),
+		Milestone:      optional(opts.Milestone),
+		AuthorUsername: optional(opts.AuthorUsername),
+		Search:         optional(opts.Search),
+	}
+
+	return CollectAll(func(page *gitlab.ListOptions) ([]*gitlab.Issue, *gitlab.Response, error) {
+		v.ListOptions = *page
+
+		return cli.c.Issues.ListProjectIssues(pid, v)
+	})
+}
+
+// ListGroupIssues returns all the issues of the projects of the group,
+// including the ones of the subgroups, which match the options.
+func (cli *Client) ListGroupIssues(gid interface{}, opts ListIssuesOptions) ([]*gitlab.Issue, error) {
+	v := &gitlab.ListGroupIssuesOptions{
+		State:          optional(opts.State),
+		Labels:         labelOptions(opts.Labels),
+		Milestone:      optional(opts.Milestone),
+		AuthorUsername: optional(opts.AuthorUsername),
+		Search:         optional(opts.Search),
+	}
+
+	return CollectAll(func(page *gitlab.ListOptions) ([]*gitlab.Issue, *gitlab.Response, error) {
+		v.ListOptions = *page
+
+		return cli.c.Issues.ListGroupIssues(gid, v)
+	})
+}
+
+// CloseIssue closes the issue.
+func (cli *Client) CloseIssue(pid interface{}, iid int) error {
+	return cli.updateIssue(pid, iid, &gitlab.UpdateIssueOptions{
+		StateEvent: gitlab.Ptr("close"),
+	})
+}
+
+// ReopenIssue reopens the closed issue.
+func (cli *Client) ReopenIssue(pid interface{}, iid int) error {
+	return cli.updateIssue(pid, iid, &gitlab.UpdateIssueOptions{
+		StateEvent: gitlab.Ptr("reopen"),
+	})
+}
+
+// SetIssueDueDate sets the due date of the issue to the date of due in its
+// location.
+func (cli *Client) SetIssueDueDate(pid interface{}, iid int, due time.Time) error {
+	return cli.updateIssue(pid, iid, &gitlab.UpdateIssueOptions{
+		DueDate: gitlab.Ptr(gitlab.ISOTime(due)),
+	})
+}
+
+// ClearIssueDueDate removes the due date of the issue.
+func (cli *Client) ClearIssueDueDate(pid interface{}, iid int) error {
+	// The zero date is sent as null, which clears the due date.
+	return cli.updateIssue(pid, iid, &gitlab.UpdateIssueOptions{
+		DueDate: new(gitlab.ISOTime),
+	})
+}
+
+// SubscribeToIssue subscribes the user with the username to the issue, or
+// the user of the client if username is empty. Subscribing another user
+// is done by sudo, which needs the token of an administrator with the sudo
+// scope. Alternatively, create a client with an impersonation token of the
+// user. Nothing is changed if the user is subscribed already.
+func (cli *Client) SubscribeToIssue(pid interface{}, iid int, username string) error {
+	_, resp, err := cli.c.Issues.SubscribeToIssue(pid, iid, sudo(username)...)
+
+	return ignoreNotModified(resp, err)
+}
+
+// UnsubscribeFromIssue is SubscribeToIssue unsubscribing the user from the
+// issue. Nothing is changed if the user is not subscribed.
+func (cli *Client) UnsubscribeFromIssue(pid interface{}, iid int, username string) error {
+	_, resp, err := cli.c.Issues.UnsubscribeFromIssue(pid, iid, sudo(username)...)
+
+	return ignoreNotModified(resp, err)
+}
+
+// sudo returns the option of the request to act as the user with the
+// username, or none if username is empty.
+func sudo(username string) []gitlab.RequestOptionFunc {
+	if username == "" {
+		return nil
+	}
+
+	return []gitlab.RequestOptionFunc{gitlab.WithSudo(username)}
+}
+
+// ignoreNotModified returns nil if GitLab answers 304 because the request
+// changes nothing.
+func ignoreNotModified(resp *gitlab.Response, err error) error {
+	if resp != nil && resp.StatusCode == http.StatusNotModified {
+		return nil
+	}
+
+	return err
+}
+
+func (cli *Client) updateIssue(pid interface{}, iid int, opts *gitlab.UpdateIssueOptions) error {
+	_, _, err := cli.c.Issues.UpdateIssue(pid, iid, opts)
+
+	return err
+}
@@ -0,0 +1,40 @@
+package client
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/xanzy/go-gitlab"
+)
+
+// IssueLinkType is the type of the relation between two issues.
+type IssueLinkType string
+
+// The types of the relation between two issues.
+const (
+	IssueLinkRelatesTo   IssueLinkType = "relates_to"
+	IssueLinkBlocks      IssueLinkType = "blocks"
+	IssueLinkIsBlockedBy IssueLinkType = "is_blocked_by"
+)
+
+// LinkIssues links the issue to the target issue, which may belong to
+// another project.
+func (cli *Client) LinkIssues(
+	pid interface{}, iid int, targetPID interface{}, targetIID int, linkType IssueLinkType,
+) error {
+	_, _, err := cli.c.IssueLinks.CreateIssueLink(pid, iid, &gitlab.CreateIssueLinkOptions{
+		TargetProjectID: gitlab.Ptr(fmt.Sprint(targetPID)),
+		TargetIssueIID:  gitlab.Ptr(strconv.Itoa(targetIID)),
+		LinkType:        gitlab.Ptr(string(linkType)),
+	})
+
+	return err
+}
+
+// ListIssueLinks returns the issues linked to the issue. The LinkType of
+// each item tells the relation.
+func (cli *Client) ListIssueLinks(pid interface{}, iid int) ([]*gitlab.IssueRelation, error) {
+	v, _, err := cli.c.IssueLinks.ListIssueRelations(pid, iid)
+
+	return v, err
+}
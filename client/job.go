@@ -0,0 +1,100 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/xanzy/go-gitlab"
+)
+
+// ListPipelineJobs returns all the jobs of the pipeline. The jobs which
+// were retried are included only if includeRetried is true.
+func (cli *Client) ListPipelineJobs(pid interface{}, pipelineID int, includeRetried bool) ([]*gitlab.Job, error) {
+	v := &gitlab.ListJobsOptions{IncludeRetried: gitlab.Ptr(includeRetried)}
+
+	return CollectAll(func(opts *gitlab.ListOptions) ([]*gitlab.Job, *gitlab.Response, error) {
+		v.ListOptions = *opts
+
+		return cli.c.Jobs.ListPipelineJobs(pid, pipelineID, v)
+	})
+}
+
+// GetJobTrace returns the last limit bytes of the log of the job, or the
+// whole log if limit is not positive. The log is streamed, so only limit
+// bytes are kept in memory however large the log is.
+func (cli *Client) GetJobTrace(pid interface{}, jobID int, limit int) ([]byte, error) {
+	project, err := projectPath(pid)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := cli.c.NewRequest(
+		http.MethodGet, fmt.Sprintf("projects/%s/jobs/%d/trace", project, jobID), nil, nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &tailWriter{limit: limit}
+	if _, err := cli.c.Do(req, w); err != nil {
+		return nil, err
+	}
+
+	return w.bytes(), nil
+}
+
+// tailWriter keeps the last limit bytes written to it in a ring buffer,
+// which never grows beyond limit.
+type tailWriter struct {
+	limit int
+	buf   []byte
+
+	// next is where the next byte goes once buf is full.
+	next int
+}
+
+func (w *tailWriter) Write(p []byte) (int, error) {
+	n := len(p)
+
+	if w.limit <= 0 {
+		w.buf = append(w.buf, p...)
+
+		return n, nil
+	}
+
+	if len(p) > w.limit {
+		p = p[len(p)-w.limit:]
+	}
+
+	if len(w.buf) < w.limit {
+		k := min(w.limit-len(w.buf), len(p))
+
+		// Grow by hand, since append may allocate beyond limit.
+		if size := len(w.buf) + k; size > cap(w.buf) {
+			buf := make([]byte, len(w.buf), min(w.limit, max(2*cap(w.buf), size)))
+			copy(buf, w.buf)
+			w.buf = buf
+		}
+
+		w.buf = append(w.buf, p[:k]...)
+		p = p[k:]
+	}
+
+	for len(p) > 0 {
+		k := copy(w.buf[w.next:], p)
+		p = p[k:]
+		w.next = (w.next + k) % w.limit
+	}
+
+	return n, nil
+}
+
+func (w *tailWriter) bytes() []byte {
+	if w.next == 0 {
+		return w.buf
+	}
+
+	r := make([]byte, 0, len(w.buf))
+
+	return append(append(r, w.buf[w.next:]...), w.buf[:w.next]...)
+}
@@ -0,0 +1,44 @@
+package client
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTailWriter(t *testing.T) {
+	tests := []struct {
+		name   string
+		limit  int
+		writes []string
+		want   string
+	}{
+		{name: "no limit", limit: 0, writes: []string{"abc", "def"}, want: "abcdef"},
+		{name: "below limit", limit: 10, writes: []string{"abc", "def"}, want: "abcdef"},
+		{name: "at limit", limit: 6, writes: []string{"abc", "def"}, want: "abcdef"},
+		{name: "wrapped", limit: 4, writes: []string{"abc", "def"}, want: "cdef"},
+		{name: "wrapped twice", limit: 4, writes: []string{"abc", "def", "gh", "ijk"}, want: "hijk"},
+		{name: "large write", limit: 3, writes: []string{"ab", "cdefgh"}, want: "fgh"},
+		{name: "byte by byte", limit: 3, writes: strings.Split("abcdefg", ""), want: "efg"},
+		{name: "empty writes", limit: 3, writes: []string{"", "ab", "", "cd"}, want: "bcd"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &tailWriter{limit: tt.limit}
+
+			for _, v := range tt.writes {
+				if n, err := w.Write([]byte(v)); n != len(v) || err != nil {
+					t.Fatalf("got %d, %v writing %q", n, err, v)
+				}
+
+				if tt.limit > 0 && cap(w.buf) > tt.limit {
+					t.Fatalf("got %d bytes kept, want at most %d", cap(w.buf), tt.limit)
+				}
+			}
+
+			if got := string(w.bytes()); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package client
+
+import (
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/xanzy/go-gitlab"
+)
+
+// LabelOptions are the settings of a label. Color is in the form of
+// #RRGGBB or one of the CSS color names.
+type LabelOptions struct {
+	Name        string
+	Color       string
+	Description string
+}
+
+// ListLabels returns the labels of the project, excluding the ones of its
+// groups.
+func (cli *Client) ListLabels(pid interface{}) ([]*gitlab.Label, error) {
+	v := &gitlab.ListLabelsOptions{IncludeAncestorGroups: gitlab.Ptr(false)}
+
+	return CollectAll(func(opts *gitlab.ListOptions) ([]*gitlab.Label, *gitlab.Response, error) {
+		v.ListOptions = *opts
+
+		return cli.c.Labels.ListLabels(pid, v)
+	})
+}
+
+// CreateLabel creates the label in the project.
+func (cli *Client) CreateLabel(pid interface{}, opts LabelOptions) (*gitlab.Label, error) {
+	v, _, err := cli.c.Labels.CreateLabel(pid, &gitlab.CreateLabelOptions{
+		Name:        gitlab.Ptr(opts.Name),
+		Color:       gitlab.Ptr(opts.Color),
+		Description: gitlab.Ptr(opts.Description),
+	})
+
+	return v, err
+}
+
+// UpdateLabel updates the label called name in the project, which is
+// renamed if the name of opts is different.
+func (cli *Client) UpdateLabel(pid interface{}, name string, opts LabelOptions) (*gitlab.Label, error) {
+	v := &gitlab.UpdateLabelOptions{
+		Color:       optional(opts.Color),
+		Description: gitlab.Ptr(opts.Description),
+	}
+
+	if opts.Name != name {
+		v.NewName = optional(opts.Name)
+	}
+
+	l, _, err := cli.c.Labels.UpdateLabel(pid, name, v)
+
+	return l, err
+}
+
+// DeleteLabel deletes the label called name from the project.
+func (cli *Client) DeleteLabel(pid interface{}, name string) error {
+	_, err := cli.c.Labels.DeleteLabel(pid, name, nil)
+
+	return err
+}
+
+// setLabelsAttempts is how many times SetIssueLabels and SetMRLabels set
+// the labels when others keep changing them at the same time.
+const setLabelsAttempts = 3
+
+// ErrLabelsConflict is returned by SetIssueLabels and SetMRLabels if the
+// labels are still changed by others after all the attempts.
+var ErrLabelsConflict = errors.New("labels keep being changed by others")
+
+// SetIssueLabels replaces the whole label set of the issue, in a single
+// request, with the one update returns from the current labels. The labels
+// are read again after they are set, and set again from the ones read if
+// others have changed them meanwhile, or if GitLab responds 409 to a
+// concurrent update, up to 3 times, after which ErrLabelsConflict is
+// returned. So update may be called several times, and must return the
+// same labels when it is given the ones it returned, which
+// ReplaceLabelsWithPrefix does. It is for the robots owning the labels of
+// a namespace, since GitLab can't update the labels conditionally, and the
+// changes others make between the read and the write are overwritten.
+func (cli *Client) SetIssueLabels(pid interface{}, iid int, update func(current []string) []string) error {
+	return setLabels(
+		func() ([]string, error) {
+			v, _, err := cli.c.Issues.GetIssue(pid, iid)
+			if err != nil {
+				return nil, err
+			}
+
+			return v.Labels, nil
+		},
+		func(labels *gitlab.LabelOptions) error {
+			return cli.updateIssue(pid, iid, &gitlab.UpdateIssueOptions{Labels: labels})
+		},
+		update,
+	)
+}
+
+// SetMRLabels is SetIssueLabels for the merge request.
+func (cli *Client) SetMRLabels(pid interface{}, iid int, update func(current []string) []string) error {
+	return setLabels(
+		func() ([]string, error) {
+			v, _, err := cli.c.MergeRequests.GetMergeRequest(pid, iid, nil)
+			if err != nil {
+				return nil, err
+			}
+
+			return v.Labels, nil
+		},
+		func(labels *gitlab.LabelOptions) error {
+			return cli.updateMR(pid, iid, &gitlab.UpdateMergeRequestOptions{Labels: labels})
+		},
+		update,
+	)
+}
+
+// setLabels sets the labels returned by update from the ones get returns
+// by set, until get returns the labels update keeps.
+func setLabels(
+	get func() ([]string, error), set func(*gitlab.LabelOptions) error,
+	update func(current []string) []string,
+) error {
+	for attempt := 0; ; attempt++ {
+		current, err := get()
+		if err != nil {
+			return err
+		}
+
+		labels := update(slices.Clone(current))
+		if sameLabels(current, labels) {
+			return nil
+		}
+
+		if attempt == setLabelsAttempts {
+			return ErrLabelsConflict
+		}
+
+		// A nil LabelOptions is sent as null, which doesn't clear them.
+		v := gitlab.LabelOptions(append([]string{}, labels...))
+		if err := set(&v); err != nil && !IsConflict(err) {
+			return err
+		}
+	}
+}
+
+// sameLabels reports whether a and b are the same set of labels.
+func sameLabels(a, b []string) bool {
+	for _, v := range a {
+		if !slices.Contains(b, v) {
+			return false
+		}
+	}
+
+	for _, v := range b {
+		if !slices.Contains(a, v) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ReplaceLabelsWithPrefix returns the update of SetIssueLabels and
+// SetMRLabels which replaces the labels starting with prefix by labels,
+// and keeps the others. It is for the robots owning the labels of a
+// namespace such as "priority::".
+func ReplaceLabelsWithPrefix(prefix string, labels ...string) func(current []string) []string {
+	return func(current []string) []string {
+		r := slices.DeleteFunc(current, func(v string) bool {
+			return strings.HasPrefix(v, prefix)
+		})
+
+		for _, v := range labels {
+			if !slices.Contains(r, v) {
+				r = append(r, v)
+			}
+		}
+
+		return r
+	}
+}
@@ -0,0 +1,104 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestReplaceLabelsWithPrefix(t *testing.T) {
+	update := ReplaceLabelsWithPrefix("priority::", "priority::high")
+
+	got := update([]string{"bug", "priority::low", "priority::high"})
+	if want := []string{"bug", "priority::high"}; !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestSetIssueLabels(t *testing.T) {
+	tests := []struct {
+		name string
+		// reads are the labels each GET returns, the last one repeated.
+		reads  []string
+		status int
+		update func([]string) []string
+		bodies []string
+		err    error
+	}{
+		{
+			name:   "replaced",
+			reads:  []string{`["bug","priority::low"]`, `["bug","priority::high"]`},
+			update: ReplaceLabelsWithPrefix("priority::", "priority::high"),
+			bodies: []string{`{"labels":"bug,priority::high"}`},
+		},
+		{
+			name:   "unchanged",
+			reads:  []string{`["bug","priority::high"]`},
+			update: ReplaceLabelsWithPrefix("priority::", "priority::high"),
+		},
+		{
+			name:   "cleared",
+			reads:  []string{`["bug"]`, `[]`},
+			update: func([]string) []string { return nil },
+			bodies: []string{`{"labels":""}`},
+		},
+		{
+			name:   "overwritten by others",
+			reads:  []string{`["priority::low"]`, `["priority::low","bug"]`, `["priority::high","bug"]`},
+			update: ReplaceLabelsWithPrefix("priority::", "priority::high"),
+			bodies: []string{`{"labels":"priority::high"}`, `{"labels":"bug,priority::high"}`},
+		},
+		{
+			name:   "conflict",
+			reads:  []string{`["priority::low"]`},
+			status: http.StatusConflict,
+			update: ReplaceLabelsWithPrefix("priority::", "priority::high"),
+			bodies: slices.Repeat([]string{`{"labels":"priority::high"}`}, setLabelsAttempts),
+			err:    ErrLabelsConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var bodies []string
+			reads := tt.reads
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodGet {
+					fmt.Fprintf(w, `{"id":9,"iid":1,"labels":%s}`, reads[0])
+					if len(reads) > 1 {
+						reads = reads[1:]
+					}
+
+					return
+				}
+
+				b, _ := io.ReadAll(r.Body)
+				bodies = append(bodies, string(b))
+
+				if tt.status != 0 {
+					w.WriteHeader(tt.status)
+				}
+				fmt.Fprint(w, `{"id":9,"iid":1}`)
+			}))
+			defer srv.Close()
+
+			cli, err := NewClient(func() []byte { return []byte("token") }, srv.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := cli.SetIssueLabels("a/b", 1, tt.update); !errors.Is(err, tt.err) {
+				t.Errorf("got error %v, want %v", err, tt.err)
+			}
+
+			if !slices.Equal(bodies, tt.bodies) {
+				t.Errorf("got %q, want %q", bodies, tt.bodies)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/xanzy/go-gitlab"
+)
+
+// ListProjectMembers returns all the members of the project, including the
+// ones inherited from its ancestor groups.
+func (cli *Client) ListProjectMembers(pid interface{}) ([]*gitlab.ProjectMember, error) {
+	v := &gitlab.ListProjectMembersOptions{}
+
+	return CollectAll(func(opts *gitlab.ListOptions) ([]*gitlab.ProjectMember, *gitlab.Response, error) {
+		v.ListOptions = *opts
+
+		return cli.c.ProjectMembers.ListAllProjectMembers(pid, v)
+	})
+}
+
+// GetUserPermission returns the effective access level of the user on the
+// project, taking the inherited membership into account. It is
+// gitlab.NoPermissions if the user is not a member. It is served from the
+// permission cache if enabled. See WithPermissionCache.
+func (cli *Client) GetUserPermission(pid interface{}, username string) (gitlab.AccessLevelValue, error) {
+	if cli.perms == nil {
+		return cli.getUserPermission(pid, username)
+	}
+
+	k := newPermissionKey(pid, username)
+	if v, ok := cli.perms.get(k); ok {
+		return v, nil
+	}
+
+	v, err := cli.getUserPermission(pid, username)
+	if err == nil {
+		cli.perms.set(k, v)
+	}
+
+	return v, err
+}
+
+func (cli *Client) getUserPermission(pid interface{}, username string) (gitlab.AccessLevelValue, error) {
+	id, err := cli.userID(username)
+	if err != nil {
+		return gitlab.NoPermissions, err
+	}
+
+	v, resp, err := cli.c.ProjectMembers.GetInheritedProjectMember(pid, id)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return gitlab.NoPermissions, nil
+		}
+
+		return gitlab.NoPermissions, err
+	}
+
+	return v.AccessLevel, nil
+}
+
+// IsProjectMember reports whether the user is a member of the project,
+// directly or through its ancestor groups.
+func (cli *Client) IsProjectMember(pid interface{}, username string) (bool, error) {
+	return cli.hasAccessLevel(pid, username, gitlab.GuestPermissions)
+}
+
+// HasWriteAccess reports whether the user can push to the unprotected
+// branches of the project, that is the user is a developer at least.
+func (cli *Client) HasWriteAccess(pid interface{}, username string) (bool, error) {
+	return cli.hasAccessLevel(pid, username, gitlab.DeveloperPermissions)
+}
+
+// IsMaintainer reports whether the user is a maintainer or an owner of the
+// project.
+func (cli *Client) IsMaintainer(pid interface{}, username string) (bool, error) {
+	return cli.hasAccessLevel(pid, username, gitlab.MaintainerPermissions)
+}
+
+func (cli *Client) hasAccessLevel(pid interface{}, username string, level gitlab.AccessLevelValue) (bool, error) {
+	v, err := cli.GetUserPermission(pid, username)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return v >= level, nil
+}
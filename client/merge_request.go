@@ -0,0 +1,331 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/xanzy/go-gitlab"
+)
+
+var (
+	// ErrMRNotMergeable is returned by MergeMR when GitLab refuses to merge
+	// for a reason other than a conflict or a pending pipeline, for example
+	// because the merge request is a draft, is closed or lacks approvals.
+	ErrMRNotMergeable = errors.New("merge request is not mergeable")
+
+	// ErrMRPipelinePending is returned by MergeMR when the merge request
+	// can't be merged until its pipeline succeeds.
+	ErrMRPipelinePending = errors.New("pipeline of merge request has not succeeded")
+
+	// ErrMRConflict is returned by MergeMR when the merge request has
+	// conflicts with its target branch.
+	ErrMRConflict = errors.New("merge request has conflicts")
+)
+
+// MergeMROptions are the options of MergeMR.
+type MergeMROptions struct {
+	Squash bool
+
+	// MergeWhenPipelineSucceeds sets the merge request to be merged once
+	// its pipeline succeeds instead of merging it immediately.
+	MergeWhenPipelineSucceeds bool
+
+	// DeleteSourceBranch removes the source branch after merging.
+	DeleteSourceBranch bool
+
+	// MergeCommitMessage and SquashCommitMessage are left to GitLab's
+	// defaults when empty.
+	MergeCommitMessage  string
+	SquashCommitMessage string
+
+	// SHA, if not empty, must match the head of the source branch,
+	// otherwise the merge request is not merged.
+	SHA string
+}
+
+func (opts *MergeMROptions) toAcceptOptions() *gitlab.AcceptMergeRequestOptions {
+	r := &gitlab.AcceptMergeRequestOptions{
+		Squash:                    gitlab.Ptr(opts.Squash),
+		ShouldRemoveSourceBranch:  gitlab.Ptr(opts.DeleteSourceBranch),
+		MergeWhenPipelineSucceeds: gitlab.Ptr(opts.MergeWhenPipelineSucceeds),
+	}
+
+	if opts.MergeCommitMessage != "" {
+		r.MergeCommitMessage = gitlab.Ptr(opts.MergeCommitMessage)
+	}
+
+	if opts.SquashCommitMessage != "" {
+		r.SquashCommitMessage = gitlab.Ptr(opts.SquashCommitMessage)
+	}
+
+	if opts.SHA != "" {
+		r.SHA = gitlab.Ptr(opts.SHA)
+	}
+
+	return r
+}
+
+// MergeMR merges the merge request. When GitLab refuses to merge it, the
+// returned error wraps one of ErrMRConflict, ErrMRPipelinePending or
+// ErrMRNotMergeable, which can be checked by errors.Is.
+func (cli *Client) MergeMR(pid interface{}, iid int, opts MergeMROptions) error {
+	_, resp, err := cli.c.MergeRequests.AcceptMergeRequest(pid, iid, opts.toAcceptOptions())
+	if err == nil {
+		return nil
+	}
+
+	if resp == nil {
+		return err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusMethodNotAllowed, http.StatusNotAcceptable,
+		http.StatusConflict, http.StatusUnprocessableEntity:
+
+		return cli.classifyMergeError(pid, iid, err)
+	}
+
+	return err
+}
+
+// classifyMergeError looks up the merge request to find out why it could
+// not be merged, since GitLab answers most of the cases with the same status.
+func (cli *Client) classifyMergeError(pid interface{}, iid int, err error) error {
+	mr, _, err1 := cli.c.MergeRequests.GetMergeRequest(pid, iid, nil)
+	if err1 != nil {
+		return fmt.Errorf("%w: %w", ErrMRNotMergeable, err)
+	}
+
+	switch {
+	case mr.HasConflicts || mr.DetailedMergeStatus == "conflict":
+		return fmt.Errorf("%w: %w", ErrMRConflict, err)
+
+	case mr.DetailedMergeStatus == "ci_still_running" ||
+		mr.DetailedMergeStatus == "ci_must_pass":
+
+		return fmt.Errorf("%w: %w", ErrMRPipelinePending, err)
+
+	default:
+		return fmt.Errorf("%w: %w", ErrMRNotMergeable, err)
+	}
+}
+
+// GetMR returns the merge request.
+func (cli *Client) GetMR(pid interface{}, iid int) (*gitlab.MergeRequest, error) {
+	v, _, err := cli.c.MergeRequests.GetMergeRequest(pid, iid, nil)
+
+	return v, err
+}
+
+// GetMRDetails returns the merge request with the details left out by
+// GetMR, which are the count of the commits of the target branch the source
+// branch is behind and the details of the labels. It is for the robots
+// which must act on the current state rather than the one of the event.
+// The merge status and the head pipeline are included as by GetMR.
+func (cli *Client) GetMRDetails(pid interface{}, iid int) (*gitlab.MergeRequest, error) {
+	mr, _, err := cli.c.MergeRequests.GetMergeRequest(pid, iid, &gitlab.GetMergeRequestsOptions{
+		IncludeDivergedCommitsCount: gitlab.Ptr(true),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the list API returns the details of the labels.
+	v, _, err := cli.c.MergeRequests.ListProjectMergeRequests(pid, &gitlab.ListProjectMergeRequestsOptions{
+		IIDs:              gitlab.Ptr([]int{iid}),
+		WithLabelsDetails: gitlab.Ptr(true),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(v) > 0 {
+		mr.LabelDetails = v[0].LabelDetails
+	}
+
+	return mr, nil
+}
+
+// ListMRsOptions are the options of ListMRs. The zero value lists all the
+// merge requests.
+type ListMRsOptions struct {
+	// State is opened, closed, locked or merged.
+	State string
+
+	// Labels are the labels the merge requests must all have.
+	Labels []string
+
+	// TargetBranch matches the target branch exactly.
+	TargetBranch string
+
+	// AuthorUsername is the username of the author.
+	AuthorUsername string
+
+	// UpdatedAfter lists the merge requests updated at or after it only.
+	UpdatedAfter time.Time
+}
+
+// ListMRs returns all the merge requests of the project which match the
+// options, the most recently created first.
+func (cli *Client) ListMRs(pid interface{}, opts ListMRsOptions) ([]*gitlab.MergeRequest, error) {
+	v := &gitlab.ListProjectMergeRequestsOptions{
+		State:          optional(opts.State),
+		Labels:         labelOptions(opts.Labels),
+		TargetBranch:   optional(opts.TargetBranch),
+		AuthorUsername: optional(opts.AuthorUsername),
+		UpdatedAfter:   optional(opts.UpdatedAfter),
+	}
+
+	return CollectAll(func(page *gitlab.ListOptions) ([]*gitlab.MergeRequest, *gitlab.Response, error) {
+		v.ListOptions = *page
+
+		return cli.c.MergeRequests.ListProjectMergeRequests(pid, v)
+	})
+}
+
+// ListOpenMRsTargeting returns all the opened merge requests of the
+// project whose target branch is branch, for example to tell them to
+// rebase after the branch moved.
+func (cli *Client) ListOpenMRsTargeting(pid interface{}, branch string) ([]*gitlab.MergeRequest, error) {
+	return cli.ListMRs(pid, ListMRsOptions{State: "opened", TargetBranch: branch})
+}
+
+// CloseMR closes the merge request.
+func (cli *Client) CloseMR(pid interface{}, iid int) error {
+	return cli.updateMR(pid, iid, &gitlab.UpdateMergeRequestOptions{
+		StateEvent: gitlab.Ptr("close"),
+	})
+}
+
+// ReopenMR reopens the closed merge request.
+func (cli *Client) ReopenMR(pid interface{}, iid int) error {
+	return cli.updateMR(pid, iid, &gitlab.UpdateMergeRequestOptions{
+		StateEvent: gitlab.Ptr("reopen"),
+	})
+}
+
+// UpdateMRTitle changes the title of the merge request.
+func (cli *Client) UpdateMRTitle(pid interface{}, iid int, title string) error {
+	return cli.updateMR(pid, iid, &gitlab.UpdateMergeRequestOptions{
+		Title: gitlab.Ptr(title),
+	})
+}
+
+// UpdateMRDescription replaces the whole description of the merge request.
+func (cli *Client) UpdateMRDescription(pid interface{}, iid int, desc string) error {
+	return cli.updateMR(pid, iid, &gitlab.UpdateMergeRequestOptions{
+		Description: gitlab.Ptr(desc),
+	})
+}
+
+func (cli *Client) updateMR(pid interface{}, iid int, opts *gitlab.UpdateMergeRequestOptions) error {
+	_, _, err := cli.c.MergeRequests.UpdateMergeRequest(pid, iid, opts)
+
+	return err
+}
+
+// draftPrefix matches the title prefixes which GitLab recognizes as marking
+// a merge request as draft.
+var draftPrefix = regexp.MustCompile(`(?i)^\s*(\[draft\]|\(draft\)|draft:|draft\s-|\[wip\]|wip:)\s*`)
+
+// SetMRDraft marks the merge request as draft by prefixing its title with
+// "Draft: ". Nothing is changed if it is a draft already.
+func (cli *Client) SetMRDraft(pid interface{}, iid int) error {
+	mr, _, err := cli.c.MergeRequests.GetMergeRequest(pid, iid, nil)
+	if err != nil {
+		return err
+	}
+
+	if mr.Draft || draftPrefix.MatchString(mr.Title) {
+		return nil
+	}
+
+	return cli.UpdateMRTitle(pid, iid, "Draft: "+mr.Title)
+}
+
+// SetMRReady marks the merge request as ready by removing the draft
+// prefixes from its title. Nothing is changed if it is ready already.
+func (cli *Client) SetMRReady(pid interface{}, iid int) error {
+	mr, _, err := cli.c.MergeRequests.GetMergeRequest(pid, iid, nil)
+	if err != nil {
+		return err
+	}
+
+	title := mr.Title
+	for draftPrefix.MatchString(title) {
+		title = draftPrefix.ReplaceAllString(title, "")
+	}
+
+	if title == mr.Title {
+		return nil
+	}
+
+	return cli.UpdateMRTitle(pid, iid, title)
+}
+
+// ErrRebaseFailed is returned by WaitForRebase when GitLab fails to rebase
+// the merge request, usually because of conflicts.
+var ErrRebaseFailed = errors.New("rebase of merge request failed")
+
+const rebasePollInterval = 2 * time.Second
+
+// RebaseMR starts to rebase the source branch of the merge request onto
+// its target branch. The rebase runs asynchronously, see WaitForRebase.
+func (cli *Client) RebaseMR(pid interface{}, iid int) error {
+	_, err := cli.c.MergeRequests.RebaseMergeRequest(pid, iid, nil)
+
+	return err
+}
+
+// WaitForRebase polls the merge request until its rebase finishes or
+// timeout. The error wraps ErrRebaseFailed if the rebase failed. Pass the
+// merge request read before RebaseMR as before, since GitLab keeps the
+// merge error of the former attempts, which is then told apart by the
+// head of the merge request not moving. If before is nil, any merge error
+// is taken as the failure of the rebase.
+func (cli *Client) WaitForRebase(
+	pid interface{}, iid int, before *gitlab.MergeRequest, timeout time.Duration,
+) error {
+	opts := &gitlab.GetMergeRequestsOptions{IncludeRebaseInProgress: gitlab.Ptr(true)}
+	deadline := time.Now().Add(timeout)
+
+	for {
+		mr, _, err := cli.c.MergeRequests.GetMergeRequest(pid, iid, opts)
+		if err != nil {
+			return err
+		}
+
+		if !mr.RebaseInProgress {
+			if rebaseFailed(before, mr) {
+				return fmt.Errorf("%w: %s", ErrRebaseFailed, mr.MergeError)
+			}
+
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("rebase of merge request %d is still in progress after %s", iid, timeout)
+		}
+
+		time.Sleep(rebasePollInterval)
+	}
+}
+
+// rebaseFailed reports whether the rebase of the merge request failed,
+// which is when it has a merge error other than the one before the rebase,
+// or the same one without its head moving.
+func rebaseFailed(before, after *gitlab.MergeRequest) bool {
+	switch {
+	case after.MergeError == "":
+		return false
+
+	case before == nil:
+		return true
+
+	default:
+		return after.MergeError != before.MergeError || after.SHA == before.SHA
+	}
+}
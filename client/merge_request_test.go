@@ -0,0 +1,35 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/xanzy/go-gitlab"
+)
+
+func TestRebaseFailed(t *testing.T) {
+	mr := func(sha, mergeError string) *gitlab.MergeRequest {
+		return &gitlab.MergeRequest{SHA: sha, MergeError: mergeError}
+	}
+
+	tests := []struct {
+		name   string
+		before *gitlab.MergeRequest
+		after  *gitlab.MergeRequest
+		want   bool
+	}{
+		{name: "rebased", before: mr("a", ""), after: mr("b", "")},
+		{name: "failed", before: mr("a", ""), after: mr("a", "conflicts"), want: true},
+		{name: "stale error", before: mr("a", "conflicts"), after: mr("b", "conflicts")},
+		{name: "failed again", before: mr("a", "conflicts"), after: mr("a", "conflicts"), want: true},
+		{name: "failed otherwise", before: mr("a", "conflicts"), after: mr("b", "timeout"), want: true},
+		{name: "without before", after: mr("b", "conflicts"), want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rebaseFailed(tt.before, tt.after); got != tt.want {
+				t.Errorf("got %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
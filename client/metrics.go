@@ -0,0 +1,155 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// WithMetrics makes the client report the requests it sends to reg:
+//
+//   - gitlab_client_requests_total, the requests by host, operation and
+//     status code.
+//   - gitlab_client_request_duration_seconds, the latency of the requests
+//     by host and operation.
+//   - gitlab_client_rate_limit_remaining, the remaining requests of the
+//     rate limit by host.
+//
+// The operation is the method and the path of the API, with the IDs
+// replaced by :id, such as "GET /projects/:id/merge_requests/:id/notes".
+// Every sent request is counted, including the retries. Several clients
+// can report to the same reg.
+func WithMetrics(reg prometheus.Registerer) Option {
+	return func(o *options) {
+		o.metrics = reg
+	}
+}
+
+type clientMetrics struct {
+	requests  *prometheus.CounterVec
+	duration  *prometheus.HistogramVec
+	remaining *prometheus.GaugeVec
+}
+
+func newClientMetrics(reg prometheus.Registerer) (*clientMetrics, error) {
+	m := &clientMetrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "gitlab_client_requests_total",
+			Help: "Requests sent to the GitLab API.",
+		}, []string{"host", "operation", "code"}),
+
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "gitlab_client_request_duration_seconds",
+			Help:    "Latency of the requests sent to the GitLab API.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"host", "operation"}),
+
+		remaining: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "gitlab_client_rate_limit_remaining",
+			Help: "Remaining requests of the GitLab API rate limit.",
+		}, []string{"host"}),
+	}
+
+	var err error
+	if m.requests, err = register(reg, m.requests); err != nil {
+		return nil, err
+	}
+
+	if m.duration, err = register(reg, m.duration); err != nil {
+		return nil, err
+	}
+
+	if m.remaining, err = register(reg, m.remaining); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// register registers c, or returns the same collector registered before.
+func register[T prometheus.Collector](reg prometheus.Registerer, c T) (T, error) {
+	err := reg.Register(c)
+	if err == nil {
+		return c, nil
+	}
+
+	var are prometheus.AlreadyRegisteredError
+	if errors.As(err, &are) {
+		if v, ok := are.ExistingCollector.(T); ok {
+			return v, nil
+		}
+	}
+
+	return c, err
+}
+
+// metricsTransport reports each request sent by base.
+type metricsTransport struct {
+	base    http.RoundTripper
+	metrics *clientMetrics
+}
+
+func (t *metricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+
+	resp, err := t.base.RoundTrip(req)
+
+	host, op := req.URL.Host, operation(req)
+	t.metrics.duration.WithLabelValues(host, op).Observe(time.Since(start).Seconds())
+
+	code := "error"
+	if err == nil {
+		code = strconv.Itoa(resp.StatusCode)
+
+		if v, err := strconv.Atoi(resp.Header.Get("RateLimit-Remaining")); err == nil {
+			t.metrics.remaining.WithLabelValues(host).Set(float64(v))
+		}
+	}
+
+	t.metrics.requests.WithLabelValues(host, op, code).Inc()
+
+	return resp, err
+}
+
+// fixedSegments are the segments of the API paths which are neither a
+// collection nor an ID, mapped to whether an ID follows them.
+var fixedSegments = map[string]bool{
+	"repository": false,
+	"assets":     false,
+	"all":        true,
+}
+
+// operation returns the method and the path of the API requested, with the
+// IDs replaced by :id. The paths of the REST APIs alternate a collection and an
+// ID of it, such as /projects/:id/issues/:id/notes.
+func operation(req *http.Request) string {
+	path := req.URL.EscapedPath()
+
+	i := strings.Index(path, "/api/v4/")
+	if i < 0 {
+		return req.Method + " " + path
+	}
+
+	segments := strings.Split(strings.Trim(path[i+len("/api/v4"):], "/"), "/")
+
+	isID := false
+	for i, s := range segments {
+		if follows, ok := fixedSegments[s]; ok {
+			isID = follows
+
+			continue
+		}
+
+		if isID {
+			segments[i] = ":id"
+		}
+
+		isID = !isID
+	}
+
+	return req.Method + " /" + strings.Join(segments, "/")
+}
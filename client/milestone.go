@@ -0,0 +1,69 @@
+package client
+
+import (
+	"time"
+
+	"github.com/xanzy/go-gitlab"
+)
+
+// CreateMilestone creates a milestone in the project. It has no due date
+// if dueDate is nil.
+func (cli *Client) CreateMilestone(pid interface{}, title, description string, dueDate *time.Time) (*gitlab.Milestone, error) {
+	opts := &gitlab.CreateMilestoneOptions{
+		Title:       gitlab.Ptr(title),
+		Description: optional(description),
+	}
+
+	if dueDate != nil {
+		opts.DueDate = gitlab.Ptr(gitlab.ISOTime(*dueDate))
+	}
+
+	v, _, err := cli.c.Milestones.CreateMilestone(pid, opts)
+
+	return v, err
+}
+
+// ListMilestonesOptions are the options of ListMilestones. The zero value
+// lists all the milestones.
+type ListMilestonesOptions struct {
+	// State is active or closed.
+	State string
+
+	// Title matches the title of the milestone exactly.
+	Title string
+
+	// Search matches the title or the description of the milestone.
+	Search string
+}
+
+// ListMilestones returns all the milestones of the project which match
+// the options.
+func (cli *Client) ListMilestones(pid interface{}, opts ListMilestonesOptions) ([]*gitlab.Milestone, error) {
+	v := &gitlab.ListMilestonesOptions{
+		State:  optional(opts.State),
+		Title:  optional(opts.Title),
+		Search: optional(opts.Search),
+	}
+
+	return CollectAll(func(page *gitlab.ListOptions) ([]*gitlab.Milestone, *gitlab.Response, error) {
+		v.ListOptions = *page
+
+		return cli.c.Milestones.ListMilestones(pid, v)
+	})
+}
+
+// SetIssueMilestone sets the milestone of the issue. It removes the issue
+// from its milestone if milestoneID is 0.
+func (cli *Client) SetIssueMilestone(pid interface{}, iid, milestoneID int) error {
+	return cli.updateIssue(pid, iid, &gitlab.UpdateIssueOptions{
+		MilestoneID: gitlab.Ptr(milestoneID),
+	})
+}
+
+// SetMRMilestone sets the milestone of the merge request. It removes the
+// merge request from its milestone if milestoneID is 0.
+func (cli *Client) SetMRMilestone(pid interface{}, iid, milestoneID int) error {
+	return cli.updateMR(pid, iid, &gitlab.UpdateMergeRequestOptions{
+		MilestoneID: gitlab.Ptr(milestoneID),
+	})
+}
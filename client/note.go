@@ -0,0 +1,149 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/xanzy/go-gitlab"
+)
+
+// ListIssueNotes returns the notes of the issue in the order they are
+// created, including the system notes.
+func (cli *Client) ListIssueNotes(pid interface{}, iid int) ([]*gitlab.Note, error) {
+	v := &gitlab.ListIssueNotesOptions{
+		OrderBy: gitlab.Ptr("created_at"),
+		Sort:    gitlab.Ptr("asc"),
+	}
+
+	return CollectAll(func(opts *gitlab.ListOptions) ([]*gitlab.Note, *gitlab.Response, error) {
+		v.ListOptions = *opts
+
+		return cli.c.Notes.ListIssueNotes(pid, iid, v)
+	})
+}
+
+// CreateIssueComment adds a comment to the issue.
+func (cli *Client) CreateIssueComment(pid interface{}, iid int, body string) (*gitlab.Note, error) {
+	v, _, err := cli.c.Notes.CreateIssueNote(pid, iid, &gitlab.CreateIssueNoteOptions{Body: gitlab.Ptr(body)})
+
+	return v, err
+}
+
+// UpdateIssueComment replaces the body of the comment of the issue.
+func (cli *Client) UpdateIssueComment(pid interface{}, iid, noteID int, body string) (*gitlab.Note, error) {
+	v, _, err := cli.c.Notes.UpdateIssueNote(pid, iid, noteID, &gitlab.UpdateIssueNoteOptions{Body: gitlab.Ptr(body)})
+
+	return v, err
+}
+
+// DeleteIssueComment deletes the comment of the issue.
+func (cli *Client) DeleteIssueComment(pid interface{}, iid, noteID int) error {
+	_, err := cli.c.Notes.DeleteIssueNote(pid, iid, noteID)
+
+	return err
+}
+
+// ListMRNotes returns the notes of the merge request in the order they are
+// created, including the system notes.
+func (cli *Client) ListMRNotes(pid interface{}, iid int) ([]*gitlab.Note, error) {
+	v := &gitlab.ListMergeRequestNotesOptions{
+		OrderBy: gitlab.Ptr("created_at"),
+		Sort:    gitlab.Ptr("asc"),
+	}
+
+	return CollectAll(func(opts *gitlab.ListOptions) ([]*gitlab.Note, *gitlab.Response, error) {
+		v.ListOptions = *opts
+
+		return cli.c.Notes.ListMergeRequestNotes(pid, iid, v)
+	})
+}
+
+// CreateMRComment adds a comment to the merge request.
+func (cli *Client) CreateMRComment(pid interface{}, iid int, body string) (*gitlab.Note, error) {
+	v, _, err := cli.c.Notes.CreateMergeRequestNote(
+		pid, iid, &gitlab.CreateMergeRequestNoteOptions{Body: gitlab.Ptr(body)},
+	)
+
+	return v, err
+}
+
+// UpdateMRComment replaces the body of the comment of the merge request.
+func (cli *Client) UpdateMRComment(pid interface{}, iid, noteID int, body string) (*gitlab.Note, error) {
+	v, _, err := cli.c.Notes.UpdateMergeRequestNote(
+		pid, iid, noteID, &gitlab.UpdateMergeRequestNoteOptions{Body: gitlab.Ptr(body)},
+	)
+
+	return v, err
+}
+
+// DeleteMRComment deletes the comment of the merge request.
+func (cli *Client) DeleteMRComment(pid interface{}, iid, noteID int) error {
+	_, err := cli.c.Notes.DeleteMergeRequestNote(pid, iid, noteID)
+
+	return err
+}
+
+// commentMarker returns the hidden marker identifying the comments of a
+// kind, which is an HTML comment GitLab does not render.
+func commentMarker(marker string) string {
+	return fmt.Sprintf("<!-- robot-comment: %s -->", marker)
+}
+
+// UpsertComment keeps a single comment of the bot identified by marker on
+// the issue or the merge request, such as the one of the CI results. It
+// updates the former comment of the bot carrying the marker in place, or
+// adds one if there is none, instead of adding a comment every time. The
+// marker is hidden in the body. kind is NoteableIssue or
+// NoteableMergeRequest. The bot is resolved by ResolveBot if its username
+// is empty, since the comments of the others may carry the marker too.
+func UpsertComment(cli Interface, pid interface{}, kind NoteableType, iid int, bot Bot, marker, body string) (*gitlab.Note, error) {
+	var (
+		list   func(pid interface{}, iid int) ([]*gitlab.Note, error)
+		create func(pid interface{}, iid int, body string) (*gitlab.Note, error)
+		update func(pid interface{}, iid, noteID int, body string) (*gitlab.Note, error)
+	)
+
+	switch kind {
+	case NoteableIssue:
+		list, create, update = cli.ListIssueNotes, cli.CreateIssueComment, cli.UpdateIssueComment
+
+	case NoteableMergeRequest:
+		list, create, update = cli.ListMRNotes, cli.CreateMRComment, cli.UpdateMRComment
+
+	default:
+		return nil, fmt.Errorf("can't upsert the comment of noteable type %q", kind)
+	}
+
+	if bot.Username == "" {
+		var err error
+		if bot, err = ResolveBot(cli); err != nil {
+			return nil, err
+		}
+	}
+
+	m := commentMarker(marker)
+	body = strings.TrimRight(body, "\n") + "\n\n" + m
+
+	notes, err := list(pid, iid)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, n := range notes {
+		if n.System || !strings.Contains(n.Body, m) {
+			continue
+		}
+
+		if n.Author.Username != bot.Username {
+			continue
+		}
+
+		if n.Body == body {
+			return n, nil
+		}
+
+		return update(pid, iid, n.ID, body)
+	}
+
+	return create(pid, iid, body)
+}
@@ -0,0 +1,93 @@
+package client_test
+
+import (
+	"testing"
+
+	"github.com/xanzy/go-gitlab"
+
+	"github.com/opensourceways/robot-gitlab-lib/client"
+	"github.com/opensourceways/robot-gitlab-lib/client/fake"
+)
+
+func TestUpsertComment(t *testing.T) {
+	const marker = "<!-- robot-comment: ci -->"
+
+	note := func(id int, author, body string) *gitlab.Note {
+		n := &gitlab.Note{ID: id, Body: body}
+		n.Author.Username = author
+
+		return n
+	}
+
+	tests := []struct {
+		name       string
+		bot        client.Bot
+		notes      []*gitlab.Note
+		wantUpdate int
+		wantCreate bool
+	}{
+		{
+			name:       "none",
+			bot:        client.Bot{Username: "robot"},
+			notes:      []*gitlab.Note{note(1, "alice", "hello")},
+			wantCreate: true,
+		},
+		{
+			name:       "updated",
+			bot:        client.Bot{Username: "robot"},
+			notes:      []*gitlab.Note{note(1, "alice", "hello"), note(2, "robot", "failed\n\n"+marker)},
+			wantUpdate: 2,
+		},
+		{
+			name:  "unchanged",
+			bot:   client.Bot{Username: "robot"},
+			notes: []*gitlab.Note{note(2, "robot", "passed\n\n"+marker)},
+		},
+		{
+			name:       "marker quoted by others",
+			bot:        client.Bot{Username: "robot"},
+			notes:      []*gitlab.Note{note(1, "alice", "> failed\n> "+marker)},
+			wantCreate: true,
+		},
+		{
+			name:       "bot resolved",
+			notes:      []*gitlab.Note{note(1, "alice", "> failed\n> "+marker), note(2, "robot", "failed\n\n"+marker)},
+			wantUpdate: 2,
+		},
+		{
+			name:       "bot resolved with marker quoted by others",
+			notes:      []*gitlab.Note{note(1, "alice", "> failed\n> "+marker)},
+			wantCreate: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := &fake.Client{
+				GetCurrentUserFunc: func() (*gitlab.User, error) {
+					return &gitlab.User{ID: 1, Username: "robot"}, nil
+				},
+				ListIssueNotesFunc: func(interface{}, int) ([]*gitlab.Note, error) {
+					return tt.notes, nil
+				},
+			}
+
+			if _, err := client.UpsertComment(cli, "opensourceways/robot-test", client.NoteableIssue, 1, tt.bot, "ci", "passed"); err != nil {
+				t.Fatal(err)
+			}
+
+			updated := 0
+			if calls := cli.CallsOf("UpdateIssueComment"); len(calls) > 0 {
+				updated = calls[0].Args[2].(int)
+			}
+
+			if updated != tt.wantUpdate {
+				t.Errorf("got note %d updated, want %d", updated, tt.wantUpdate)
+			}
+
+			if created := len(cli.CallsOf("CreateIssueComment")) > 0; created != tt.wantCreate {
+				t.Errorf("got created %v, want %v", created, tt.wantCreate)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/xanzy/go-gitlab"
+	"golang.org/x/oauth2"
+)
+
+// OAuth2Config is the OAuth2 application the client authenticates as.
+type OAuth2Config struct {
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+
+	// RefreshToken is exchanged for the access tokens, and is required
+	// since GitLab doesn't support the client credentials grant. Get it
+	// by authorizing the application once as the user of the robot.
+	RefreshToken string
+
+	// OnRotate is called with the new refresh token each time GitLab
+	// rotates it, which it does on every refresh and revokes the previous
+	// one. Save it to pass as RefreshToken next time, otherwise the
+	// client can't authenticate after a restart.
+	OnRotate func(refreshToken string)
+}
+
+// NewOAuth2Client creates a client for the GitLab instance at host which
+// authenticates with OAuth2 access tokens instead of a personal access
+// token. The access token is refreshed shortly before it expires, so the
+// client can run for a long time. WithTokenPool has no effect on it.
+func NewOAuth2Client(cfg OAuth2Config, host string, opts ...Option) (*Client, error) {
+	if cfg.RefreshToken == "" {
+		return nil, errors.New("missing the oauth2 refresh token")
+	}
+
+	o := newOptions(opts)
+
+	t := o.transport.newTransport()
+
+	base := &oauth2.Transport{
+		Source: cfg.tokenSource(oauth2TokenURL(host), &http.Client{
+			Transport: t,
+			Timeout:   o.transport.Timeout,
+		}),
+		Base: newCacheTransport(t, o.cache),
+	}
+
+	return newClient(base, o, func(hc *http.Client) (*gitlab.Client, error) {
+		// The token is set by the oauth2 transport.
+		return gitlab.NewOAuthClient(
+			"", gitlab.WithBaseURL(host), gitlab.WithHTTPClient(hc), gitlab.WithoutRetries(),
+		)
+	})
+}
+
+// tokenSource returns the source of the access tokens, which requests them
+// by hc.
+func (cfg *OAuth2Config) tokenSource(tokenURL string, hc *http.Client) oauth2.TokenSource {
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, hc)
+
+	c := &oauth2.Config{
+		ClientID:     cfg.ClientID,
+		ClientSecret: cfg.ClientSecret,
+		Endpoint:     oauth2.Endpoint{TokenURL: tokenURL},
+		Scopes:       cfg.Scopes,
+	}
+
+	return &rotatingTokenSource{
+		base:     c.TokenSource(ctx, &oauth2.Token{RefreshToken: cfg.RefreshToken}),
+		refresh:  cfg.RefreshToken,
+		onRotate: cfg.OnRotate,
+	}
+}
+
+// rotatingTokenSource reports the refresh tokens rotated by the refreshes
+// of the access tokens.
+type rotatingTokenSource struct {
+	base     oauth2.TokenSource
+	onRotate func(string)
+
+	mu      sync.Mutex
+	refresh string
+}
+
+func (s *rotatingTokenSource) Token() (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, err := s.base.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	if t.RefreshToken != "" && t.RefreshToken != s.refresh {
+		s.refresh = t.RefreshToken
+
+		if s.onRotate != nil {
+			s.onRotate(t.RefreshToken)
+		}
+	}
+
+	return t, nil
+}
+
+// oauth2TokenURL returns the token endpoint of the instance at host, which
+// can be given with or without the API path, the same as NewClient accepts.
+func oauth2TokenURL(host string) string {
+	host = strings.TrimSuffix(strings.TrimSuffix(host, "/"), "/api/v4")
+
+	return host + "/oauth/token"
+}
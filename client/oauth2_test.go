@@ -0,0 +1,79 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestOAuth2RotatesRefreshToken(t *testing.T) {
+	var (
+		mu        sync.Mutex
+		refreshes []string
+		rotated   []string
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/oauth/token":
+			mu.Lock()
+			refreshes = append(refreshes, r.FormValue("refresh_token"))
+			n := len(refreshes)
+			mu.Unlock()
+
+			w.Header().Set("Content-Type", "application/json")
+
+			// The access tokens expire within the expiry delta of the
+			// oauth2 package, so each request refreshes it.
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"access_token":  fmt.Sprintf("access-%d", n),
+				"refresh_token": fmt.Sprintf("refresh-%d", n),
+				"token_type":    "Bearer",
+				"expires_in":    1,
+			})
+
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"id": 1, "username": "robot"}`))
+		}
+	}))
+	defer srv.Close()
+
+	cli, err := NewOAuth2Client(OAuth2Config{
+		ClientID:     "id",
+		ClientSecret: "secret",
+		RefreshToken: "refresh-0",
+		OnRotate: func(v string) {
+			mu.Lock()
+			rotated = append(rotated, v)
+			mu.Unlock()
+		},
+	}, srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := cli.GetCurrentUser(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if want := []string{"refresh-0", "refresh-1"}; !slices.Equal(refreshes, want) {
+		t.Errorf("got refreshed by %v, want %v", refreshes, want)
+	}
+
+	if want := []string{"refresh-1", "refresh-2"}; !slices.Equal(rotated, want) {
+		t.Errorf("got rotated %v, want %v", rotated, want)
+	}
+}
+
+func TestOAuth2RequiresRefreshToken(t *testing.T) {
+	if _, err := NewOAuth2Client(OAuth2Config{ClientID: "id", ClientSecret: "secret"}, "https://gitlab.example.com"); err == nil {
+		t.Error("got no error, want the error of the missing refresh token")
+	}
+}
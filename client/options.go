@@ -0,0 +1,42 @@
+package client
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+// Option configures the client created by NewClient.
+type Option func(*options)
+
+type options struct {
+	transport      TransportOptions
+	retry          RetryPolicy
+	circuitBreaker *CircuitBreakerOptions
+	rateLimit      RateLimitOptions
+	cache          *CacheOptions
+	permissionTTL  time.Duration
+	metrics        prometheus.Registerer
+	dryRun         *logrus.Entry
+	audit          AuditSink
+	poolTokens     []string
+	rotation       TokenRotation
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, fn := range opts {
+		fn(o)
+	}
+
+	return o
+}
+
+// WithRateLimit configures how the client deals with the rate limit of
+// GitLab.
+func WithRateLimit(opts RateLimitOptions) Option {
+	return func(o *options) {
+		o.rateLimit = opts
+	}
+}
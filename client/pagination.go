@@ -0,0 +1,53 @@
+package client
+
+import (
+	"github.com/xanzy/go-gitlab"
+)
+
+// perPage is the page size used to list, which is the maximum GitLab allows.
+const perPage = 100
+
+// PageFunc fetches the page of a list API described by opts. It usually
+// copies opts into the options of the go-gitlab method it calls, such as:
+//
+//	func(opts *gitlab.ListOptions) ([]*gitlab.Label, *gitlab.Response, error) {
+//		v.ListOptions = *opts
+//		return c.Labels.ListLabels(pid, v)
+//	}
+type PageFunc[T any] func(opts *gitlab.ListOptions) ([]T, *gitlab.Response, error)
+
+// ForEachPage fetches the pages one by one from the first and passes the
+// items of each page to fn, until the last page is reached or fn returns
+// false.
+func ForEachPage[T any](fetch PageFunc[T], fn func(items []T) bool) error {
+	opts := &gitlab.ListOptions{Page: 1, PerPage: perPage}
+
+	for {
+		v, resp, err := fetch(opts)
+		if err != nil {
+			return err
+		}
+
+		if !fn(v) || resp.NextPage == 0 {
+			return nil
+		}
+
+		opts.Page = resp.NextPage
+	}
+}
+
+// CollectAll returns the items of all the pages.
+func CollectAll[T any](fetch PageFunc[T]) ([]T, error) {
+	var r []T
+
+	err := ForEachPage(fetch, func(items []T) bool {
+		r = append(r, items...)
+
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
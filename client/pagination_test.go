@@ -0,0 +1,96 @@
+package client
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/xanzy/go-gitlab"
+)
+
+// pages returns the PageFunc serving the pages and recording the pages
+// requested.
+func pages(requested *[]int, items ...[]int) PageFunc[int] {
+	return func(opts *gitlab.ListOptions) ([]int, *gitlab.Response, error) {
+		*requested = append(*requested, opts.Page)
+
+		if opts.PerPage != perPage {
+			return nil, nil, errors.New("unexpected page size")
+		}
+
+		resp := &gitlab.Response{}
+		if opts.Page < len(items) {
+			resp.NextPage = opts.Page + 1
+		}
+
+		return items[opts.Page-1], resp, nil
+	}
+}
+
+func TestCollectAll(t *testing.T) {
+	tests := []struct {
+		name  string
+		pages [][]int
+		want  []int
+	}{
+		{name: "empty", pages: [][]int{nil}, want: nil},
+		{name: "one page", pages: [][]int{{1, 2}}, want: []int{1, 2}},
+		{name: "three pages", pages: [][]int{{1, 2}, {3, 4}, {5}}, want: []int{1, 2, 3, 4, 5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requested []int
+
+			got, err := CollectAll(pages(&requested, tt.pages...))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+
+			if len(requested) != len(tt.pages) {
+				t.Errorf("got pages %v requested, want %d pages", requested, len(tt.pages))
+			}
+		})
+	}
+}
+
+func TestCollectAllError(t *testing.T) {
+	errFetch := errors.New("fetch")
+
+	_, err := CollectAll(func(opts *gitlab.ListOptions) ([]int, *gitlab.Response, error) {
+		if opts.Page == 2 {
+			return nil, nil, errFetch
+		}
+
+		return []int{1}, &gitlab.Response{NextPage: opts.Page + 1}, nil
+	})
+	if !errors.Is(err, errFetch) {
+		t.Errorf("got %v, want %v", err, errFetch)
+	}
+}
+
+func TestForEachPageStops(t *testing.T) {
+	var requested []int
+
+	var got []int
+	err := ForEachPage(pages(&requested, []int{1, 2}, []int{3, 4}, []int{5}), func(items []int) bool {
+		got = append(got, items...)
+
+		return !slices.Contains(items, 3)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []int{1, 2, 3, 4}; !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	if want := []int{1, 2}; !slices.Equal(requested, want) {
+		t.Errorf("got pages %v requested, want %v", requested, want)
+	}
+}
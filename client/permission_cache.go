@@ -0,0 +1,116 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/xanzy/go-gitlab"
+)
+
+// WithPermissionCache makes the client keep the access levels of the users
+// returned by GetUserPermission, and the checks built on it, for ttl. The
+// permission checks of the commands in the comments then cost no request
+// most of the time. Call InvalidatePermissions when the membership of a
+// user changes, such as framework.NewPermissionInvalidator does on the
+// member events of the group webhooks, so that it takes effect before ttl
+// expires.
+func WithPermissionCache(ttl time.Duration) Option {
+	return func(o *options) {
+		o.permissionTTL = ttl
+	}
+}
+
+type permissionKey struct {
+	project  string
+	username string
+}
+
+type permissionEntry struct {
+	level   gitlab.AccessLevelValue
+	expires time.Time
+}
+
+// permissionCache keeps the access levels of the users on the projects.
+type permissionCache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[permissionKey]permissionEntry
+}
+
+func newPermissionCache(ttl time.Duration) *permissionCache {
+	return &permissionCache{
+		ttl:     ttl,
+		entries: map[permissionKey]permissionEntry{},
+	}
+}
+
+func newPermissionKey(pid interface{}, username string) permissionKey {
+	return permissionKey{project: fmt.Sprint(pid), username: strings.ToLower(username)}
+}
+
+func (c *permissionCache) get(k permissionKey) (gitlab.AccessLevelValue, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	v, ok := c.entries[k]
+	if !ok {
+		return gitlab.NoPermissions, false
+	}
+
+	if time.Now().After(v.expires) {
+		delete(c.entries, k)
+
+		return gitlab.NoPermissions, false
+	}
+
+	return v.level, true
+}
+
+func (c *permissionCache) set(k permissionKey, level gitlab.AccessLevelValue) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+
+	// Drop the expired entries now and then, so that the ones of the
+	// users not seen again do not pile up.
+	if len(c.entries)%1024 == 1023 {
+		for k, v := range c.entries {
+			if now.After(v.expires) {
+				delete(c.entries, k)
+			}
+		}
+	}
+
+	c.entries[k] = permissionEntry{level: level, expires: now.Add(c.ttl)}
+}
+
+func (c *permissionCache) invalidate(username string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if username == "" {
+		clear(c.entries)
+
+		return
+	}
+
+	username = strings.ToLower(username)
+	for k := range c.entries {
+		if k.username == username {
+			delete(c.entries, k)
+		}
+	}
+}
+
+// InvalidatePermissions drops the cached access levels of the user on all
+// the projects, or the ones of all the users if username is empty. It
+// does nothing if the permission cache is not enabled.
+func (cli *Client) InvalidatePermissions(username string) {
+	if cli.perms != nil {
+		cli.perms.invalidate(username)
+	}
+}
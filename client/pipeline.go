@@ -0,0 +1,48 @@
+package client
+
+import (
+	"sort"
+
+	"github.com/xanzy/go-gitlab"
+)
+
+// CreatePipeline runs a new pipeline on ref with the extra variables.
+func (cli *Client) CreatePipeline(pid interface{}, ref string, variables map[string]string) (*gitlab.Pipeline, error) {
+	opts := &gitlab.CreatePipelineOptions{Ref: gitlab.Ptr(ref)}
+
+	if len(variables) > 0 {
+		keys := make([]string, 0, len(variables))
+		for k := range variables {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		vars := make([]*gitlab.PipelineVariableOptions, len(keys))
+		for i, k := range keys {
+			vars[i] = &gitlab.PipelineVariableOptions{
+				Key:   gitlab.Ptr(k),
+				Value: gitlab.Ptr(variables[k]),
+			}
+		}
+
+		opts.Variables = &vars
+	}
+
+	v, _, err := cli.c.Pipelines.CreatePipeline(pid, opts)
+
+	return v, err
+}
+
+// RetryPipeline retries the failed or canceled jobs of the pipeline.
+func (cli *Client) RetryPipeline(pid interface{}, pipelineID int) (*gitlab.Pipeline, error) {
+	v, _, err := cli.c.Pipelines.RetryPipelineBuild(pid, pipelineID)
+
+	return v, err
+}
+
+// CancelPipeline cancels the running jobs of the pipeline.
+func (cli *Client) CancelPipeline(pid interface{}, pipelineID int) (*gitlab.Pipeline, error) {
+	v, _, err := cli.c.Pipelines.CancelPipelineBuild(pid, pipelineID)
+
+	return v, err
+}
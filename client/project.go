@@ -0,0 +1,196 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/xanzy/go-gitlab"
+)
+
+// ErrImportFailed is returned when the import of a created or forked
+// project fails.
+var ErrImportFailed = errors.New("project import failed")
+
+const importPollInterval = 2 * time.Second
+
+// CreateProjectOptions are the options of CreateProject.
+type CreateProjectOptions struct {
+	Name string
+
+	// Path defaults to the one GitLab generates from Name.
+	Path string
+
+	// Namespace is the full path of the group to create the project in.
+	// The project is created in the namespace of the user owning the token
+	// if it is empty.
+	Namespace string
+
+	Description          string
+	Visibility           gitlab.VisibilityValue
+	DefaultBranch        string
+	InitializeWithReadme bool
+
+	// ImportURL is the URL of a repository to import.
+	ImportURL string
+
+	// WaitForImport, if positive, is how long to wait for the import of
+	// the repository to finish.
+	WaitForImport time.Duration
+}
+
+// CreateProject creates a project.
+func (cli *Client) CreateProject(opts CreateProjectOptions) (*gitlab.Project, error) {
+	v := &gitlab.CreateProjectOptions{
+		Name:                 gitlab.Ptr(opts.Name),
+		Path:                 optional(opts.Path),
+		Description:          optional(opts.Description),
+		Visibility:           optional(opts.Visibility),
+		DefaultBranch:        optional(opts.DefaultBranch),
+		InitializeWithReadme: gitlab.Ptr(opts.InitializeWithReadme),
+		ImportURL:            optional(opts.ImportURL),
+	}
+
+	if opts.Namespace != "" {
+		ns, _, err := cli.c.Namespaces.GetNamespace(opts.Namespace)
+		if err != nil {
+			return nil, err
+		}
+
+		v.NamespaceID = gitlab.Ptr(ns.ID)
+	}
+
+	p, _, err := cli.c.Projects.CreateProject(v)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.ImportURL == "" || opts.WaitForImport <= 0 {
+		return p, nil
+	}
+
+	return cli.waitForImport(p, opts.WaitForImport)
+}
+
+// ForkProjectOptions are the options of ForkProject.
+type ForkProjectOptions struct {
+	// Namespace is the full path of the namespace to fork to. The project
+	// is forked to the namespace of the user owning the token if it is
+	// empty.
+	Namespace string
+
+	// Name and Path default to the ones of the forked project.
+	Name string
+	Path string
+
+	// WaitForImport, if positive, is how long to wait for the repository
+	// to be copied to the fork.
+	WaitForImport time.Duration
+}
+
+// ForkProject forks the project.
+func (cli *Client) ForkProject(pid interface{}, opts ForkProjectOptions) (*gitlab.Project, error) {
+	p, _, err := cli.c.Projects.ForkProject(pid, &gitlab.ForkProjectOptions{
+		NamespacePath: optional(opts.Namespace),
+		Name:          optional(opts.Name),
+		Path:          optional(opts.Path),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.WaitForImport <= 0 {
+		return p, nil
+	}
+
+	return cli.waitForImport(p, opts.WaitForImport)
+}
+
+// waitForImport polls the project until its import finishes or timeout.
+func (cli *Client) waitForImport(p *gitlab.Project, timeout time.Duration) (*gitlab.Project, error) {
+	deadline := time.Now().Add(timeout)
+
+	for {
+		switch p.ImportStatus {
+		case "", "none", "finished":
+			return p, nil
+
+		case "failed":
+			return p, fmt.Errorf("%w: %s: %s", ErrImportFailed, p.PathWithNamespace, p.ImportError)
+		}
+
+		if time.Now().After(deadline) {
+			return p, fmt.Errorf(
+				"import of %s is still %s after %s", p.PathWithNamespace, p.ImportStatus, timeout,
+			)
+		}
+
+		time.Sleep(importPollInterval)
+
+		v, _, err := cli.c.Projects.GetProject(p.ID, nil)
+		if err != nil {
+			return p, err
+		}
+
+		p = v
+	}
+}
+
+// ListProjectEvents returns the events of the project, from the oldest to
+// the newest. Since GitLab filters the events by dates, the ones returned
+// are those created after the day of after rather than after the exact
+// time. All the events are returned if after is zero.
+func (cli *Client) ListProjectEvents(pid interface{}, after time.Time) ([]*gitlab.ProjectEvent, error) {
+	v := &gitlab.ListProjectVisibleEventsOptions{Sort: gitlab.Ptr("asc")}
+	if !after.IsZero() {
+		v.After = gitlab.Ptr(gitlab.ISOTime(after))
+	}
+
+	return CollectAll(func(opts *gitlab.ListOptions) ([]*gitlab.ProjectEvent, *gitlab.Response, error) {
+		v.ListOptions = *opts
+
+		return cli.c.Events.ListProjectVisibleEvents(pid, v)
+	})
+}
+
+// ListNewProjectEvents returns the events of the project newer than the
+// event of lastID, from the oldest to the newest. Only the newest limit
+// of them are returned if limit is positive. The events are listed from the
+// newest, so that only the pages of the new ones are requested.
+func (cli *Client) ListNewProjectEvents(pid interface{}, lastID, limit int) ([]*gitlab.ProjectEvent, error) {
+	v := &gitlab.ListProjectVisibleEventsOptions{Sort: gitlab.Ptr("desc")}
+
+	var r []*gitlab.ProjectEvent
+
+	err := ForEachPage(func(opts *gitlab.ListOptions) ([]*gitlab.ProjectEvent, *gitlab.Response, error) {
+		v.ListOptions = *opts
+
+		return cli.c.Events.ListProjectVisibleEvents(pid, v)
+	}, func(items []*gitlab.ProjectEvent) bool {
+		for _, e := range items {
+			if e.ID <= lastID {
+				return false
+			}
+
+			// The events created while paging shift the pages, which
+			// returns the ones seen on the former page again.
+			if len(r) > 0 && e.ID >= r[len(r)-1].ID {
+				continue
+			}
+
+			if r = append(r, e); len(r) == limit {
+				return false
+			}
+		}
+
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.Reverse(r)
+
+	return r, nil
+}
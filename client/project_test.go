@@ -0,0 +1,78 @@
+package client
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func TestListNewProjectEvents(t *testing.T) {
+	tests := []struct {
+		name      string
+		pages     [][]int
+		lastID    int
+		limit     int
+		want      []int
+		requested int
+	}{
+		{name: "new on the first page", pages: [][]int{{9, 8, 7}, {6, 5}}, lastID: 7, want: []int{8, 9}, requested: 1},
+		{name: "new on two pages", pages: [][]int{{9, 8, 7}, {6, 5}}, lastID: 5, want: []int{6, 7, 8, 9}, requested: 2},
+		{name: "none new", pages: [][]int{{9, 8}}, lastID: 9, requested: 1},
+		{name: "newest", pages: [][]int{{9, 8, 7}, {6, 5}}, limit: 1, want: []int{9}, requested: 1},
+		{name: "all", pages: [][]int{{9, 8}, {7}}, want: []int{7, 8, 9}, requested: 2},
+		{name: "shifted pages", pages: [][]int{{10, 9, 8}, {8, 7}}, lastID: 6, want: []int{7, 8, 9, 10}, requested: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requested := 0
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requested++
+
+				if r.URL.Query().Get("sort") != "desc" {
+					t.Errorf("got sort %q, want desc", r.URL.Query().Get("sort"))
+				}
+
+				page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+				if page < len(tt.pages) {
+					w.Header().Set("X-Next-Page", strconv.Itoa(page+1))
+				}
+
+				var events []map[string]int
+				for _, id := range tt.pages[page-1] {
+					events = append(events, map[string]int{"id": id})
+				}
+
+				json.NewEncoder(w).Encode(events)
+			}))
+			defer srv.Close()
+
+			cli, err := NewClient(func() []byte { return []byte("token") }, srv.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			events, err := cli.ListNewProjectEvents("a/b", tt.lastID, tt.limit)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []int
+			for _, e := range events {
+				got = append(got, e.ID)
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+
+			if requested != tt.requested {
+				t.Errorf("got %d pages requested, want %d", requested, tt.requested)
+			}
+		})
+	}
+}
@@ -0,0 +1,226 @@
+package client
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	defaultRateLimitRetries = 3
+	defaultRateLimitMaxWait = time.Minute
+)
+
+// RateLimitOptions configure how the client deals with the rate limit. The
+// zero value retries the requests rejected by 429 only.
+type RateLimitOptions struct {
+	// ThrottleBelow, if positive, makes the requests wait for the rate
+	// limit to reset once the remaining requests drop to it, instead of
+	// running out of quota and being rejected.
+	ThrottleBelow int
+
+	// MaxRetries is how many times a request rejected by 429 is retried
+	// after the time GitLab asks for. It is 3 if zero, and the requests
+	// are not retried if it is negative.
+	MaxRetries int
+
+	// MaxWait caps how long to wait each time. It is 1 minute if zero.
+	MaxWait time.Duration
+
+	// OnThrottle, if not nil, is called each time before waiting.
+	OnThrottle func(ThrottleEvent)
+}
+
+// ThrottleEvent describes a wait caused by the rate limit.
+type ThrottleEvent struct {
+	Method string
+	URL    string
+
+	// Retry is true when waiting to retry a request rejected by 429, and
+	// false when waiting proactively because of ThrottleBelow.
+	Retry bool
+
+	Wait      time.Duration
+	Remaining int
+}
+
+// RateLimitState is the rate limit reported by the last response.
+type RateLimitState struct {
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+// RateLimit returns the rate limit reported by the last response. It is
+// the zero value if GitLab doesn't report it.
+func (cli *Client) RateLimit() RateLimitState {
+	return cli.rateLimiter.state()
+}
+
+// rateLimitTransport tracks the rate limit headers of the responses,
+// throttles the requests and retries the ones rejected by 429.
+type rateLimitTransport struct {
+	base http.RoundTripper
+	opts RateLimitOptions
+
+	mu      sync.Mutex
+	current RateLimitState
+}
+
+func newRateLimitTransport(base http.RoundTripper, opts RateLimitOptions) *rateLimitTransport {
+	if opts.MaxRetries == 0 {
+		opts.MaxRetries = defaultRateLimitRetries
+	}
+
+	if opts.MaxWait <= 0 {
+		opts.MaxWait = defaultRateLimitMaxWait
+	}
+
+	return &rateLimitTransport{base: base, opts: opts}
+}
+
+func (t *rateLimitTransport) state() RateLimitState {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.current
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.throttle(req); err != nil {
+		return nil, err
+	}
+
+	for retries := 0; ; retries++ {
+		resp, err := t.base.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+
+		t.record(resp)
+
+		if resp.StatusCode != http.StatusTooManyRequests ||
+			retries >= t.opts.MaxRetries || req.GetBody == nil && req.Body != nil {
+
+			return resp, nil
+		}
+
+		wait := t.retryAfter(resp)
+		resp.Body.Close()
+
+		t.notify(req, true, wait)
+
+		if err := sleep(req, wait); err != nil {
+			return nil, err
+		}
+
+		if req, err = rewind(req); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// throttle waits for the rate limit to reset if the remaining requests
+// drop to ThrottleBelow.
+func (t *rateLimitTransport) throttle(req *http.Request) error {
+	if t.opts.ThrottleBelow <= 0 {
+		return nil
+	}
+
+	s := t.state()
+	if s.Reset.IsZero() || s.Remaining > t.opts.ThrottleBelow {
+		return nil
+	}
+
+	wait := time.Until(s.Reset)
+	if wait <= 0 {
+		return nil
+	}
+
+	wait = min(wait, t.opts.MaxWait)
+
+	t.notify(req, false, wait)
+
+	return sleep(req, wait)
+}
+
+func (t *rateLimitTransport) notify(req *http.Request, retry bool, wait time.Duration) {
+	if t.opts.OnThrottle == nil {
+		return
+	}
+
+	t.opts.OnThrottle(ThrottleEvent{
+		Method:    req.Method,
+		URL:       req.URL.String(),
+		Retry:     retry,
+		Wait:      wait,
+		Remaining: t.state().Remaining,
+	})
+}
+
+func (t *rateLimitTransport) record(resp *http.Response) {
+	remaining, err := strconv.Atoi(resp.Header.Get("RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+
+	s := RateLimitState{Remaining: remaining}
+	s.Limit, _ = strconv.Atoi(resp.Header.Get("RateLimit-Limit"))
+
+	if v, err := strconv.ParseInt(resp.Header.Get("RateLimit-Reset"), 10, 64); err == nil {
+		s.Reset = time.Unix(v, 0)
+	}
+
+	t.mu.Lock()
+	t.current = s
+	t.mu.Unlock()
+}
+
+// retryAfter returns how long to wait before retrying the request rejected
+// by 429, according to the Retry-After header, or RateLimit-Reset if the
+// former is missing.
+func (t *rateLimitTransport) retryAfter(resp *http.Response) time.Duration {
+	wait := time.Second
+
+	if v := resp.Header.Get("Retry-After"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			wait = time.Duration(n) * time.Second
+		} else if d, err := http.ParseTime(v); err == nil {
+			wait = time.Until(d)
+		}
+	} else if s := t.state(); !s.Reset.IsZero() {
+		wait = time.Until(s.Reset)
+	}
+
+	return min(max(wait, 0), t.opts.MaxWait)
+}
+
+// sleep waits for d unless the request is canceled.
+func sleep(req *http.Request, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-req.Context().Done():
+		return req.Context().Err()
+	}
+}
+
+// rewind returns a copy of the request which can be sent again.
+func rewind(req *http.Request) (*http.Request, error) {
+	r := req.Clone(req.Context())
+
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+
+		r.Body = body
+	}
+
+	return r, nil
+}
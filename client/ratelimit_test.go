@@ -0,0 +1,172 @@
+package client
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// roundTripFunc is the transport of the tests answering the requests by
+// the function.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func response(code int) *http.Response {
+	return &http.Response{
+		StatusCode: code,
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader("")),
+	}
+}
+
+func TestRateLimitTransport(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		maxRetries int
+		codes      []int
+		attempts   int
+		want       int
+		throttles  int
+	}{
+		{name: "success", method: http.MethodGet, codes: []int{200}, attempts: 1, want: 200},
+		{name: "retried", method: http.MethodGet, codes: []int{429, 429, 200}, attempts: 3, want: 200, throttles: 2},
+		{name: "post retried", method: http.MethodPost, codes: []int{429, 201}, attempts: 2, want: 201, throttles: 1},
+		{name: "retries exhausted", method: http.MethodGet, codes: []int{429, 429, 429, 429, 200}, attempts: 4, want: 429, throttles: 3},
+		{name: "one retry", method: http.MethodGet, maxRetries: 1, codes: []int{429, 429, 200}, attempts: 2, want: 429, throttles: 1},
+		{name: "no retries", method: http.MethodGet, maxRetries: -1, codes: []int{429, 200}, attempts: 1, want: 429},
+		{name: "500 not retried", method: http.MethodGet, codes: []int{500, 200}, attempts: 1, want: 500},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+
+			base := roundTripFunc(func(*http.Request) (*http.Response, error) {
+				resp := response(tt.codes[attempts])
+				resp.Header.Set("Retry-After", "0")
+				resp.Header.Set("RateLimit-Limit", "600")
+				resp.Header.Set("RateLimit-Remaining", strconv.Itoa(100-attempts))
+
+				attempts++
+
+				return resp, nil
+			})
+
+			throttles := 0
+			rt := newRateLimitTransport(base, RateLimitOptions{
+				MaxRetries: tt.maxRetries,
+				OnThrottle: func(e ThrottleEvent) {
+					if !e.Retry {
+						t.Errorf("got the throttle of %+v, want a retry", e)
+					}
+
+					throttles++
+				},
+			})
+
+			req, err := http.NewRequest(tt.method, "https://gitlab.example.com/api/v4/projects", strings.NewReader("{}"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			resp, err := rt.RoundTrip(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.want {
+				t.Errorf("got %d, want %d", resp.StatusCode, tt.want)
+			}
+
+			if attempts != tt.attempts {
+				t.Errorf("got %d attempts, want %d", attempts, tt.attempts)
+			}
+
+			if throttles != tt.throttles {
+				t.Errorf("got %d throttles, want %d", throttles, tt.throttles)
+			}
+
+			if s := rt.state(); s.Limit != 600 || s.Remaining != 101-attempts {
+				t.Errorf("got state %+v after %d attempts", s, attempts)
+			}
+		})
+	}
+}
+
+func TestRateLimitRetryAfter(t *testing.T) {
+	tests := []struct {
+		name   string
+		header http.Header
+		reset  time.Time
+		want   time.Duration
+	}{
+		{name: "default", header: http.Header{}, want: time.Second},
+		{name: "seconds", header: http.Header{"Retry-After": {"5"}}, want: 5 * time.Second},
+		{name: "capped", header: http.Header{"Retry-After": {"3600"}}, want: time.Minute},
+		{name: "negative", header: http.Header{"Retry-After": {"-5"}}, want: 0},
+		{name: "past date", header: http.Header{"Retry-After": {"Mon, 02 Jan 2006 15:04:05 GMT"}}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt := newRateLimitTransport(nil, RateLimitOptions{})
+
+			resp := response(http.StatusTooManyRequests)
+			resp.Header = tt.header
+
+			if got := rt.retryAfter(resp); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRateLimitThrottle(t *testing.T) {
+	tests := []struct {
+		name      string
+		below     int
+		remaining int
+		throttled bool
+	}{
+		{name: "disabled", below: 0, remaining: 0, throttled: false},
+		{name: "above", below: 10, remaining: 11, throttled: false},
+		{name: "at", below: 10, remaining: 10, throttled: true},
+		{name: "below", below: 10, remaining: 3, throttled: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			throttled := false
+
+			rt := newRateLimitTransport(nil, RateLimitOptions{
+				ThrottleBelow: tt.below,
+				MaxWait:       time.Millisecond,
+				OnThrottle: func(e ThrottleEvent) {
+					throttled = !e.Retry && e.Remaining == tt.remaining
+				},
+			})
+			rt.current = RateLimitState{Remaining: tt.remaining, Reset: time.Now().Add(time.Hour)}
+
+			req, err := http.NewRequest(http.MethodGet, "https://gitlab.example.com/api/v4/projects", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := rt.throttle(req); err != nil {
+				t.Fatal(err)
+			}
+
+			if throttled != tt.throttled {
+				t.Errorf("got throttled %v, want %v", throttled, tt.throttled)
+			}
+		})
+	}
+}
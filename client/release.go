@@ -0,0 +1,121 @@
+package client
+
+import (
+	"github.com/xanzy/go-gitlab"
+)
+
+// ReleaseLink is an asset link of a release.
+type ReleaseLink struct {
+	Name     string
+	URL      string
+	LinkType gitlab.LinkTypeValue
+}
+
+// ReleaseOptions are the options of CreateRelease and UpdateRelease.
+type ReleaseOptions struct {
+	Name        string
+	Description string
+	Milestones  []string
+	Links       []ReleaseLink
+
+	// Ref is the branch or commit to create the tag from if the tag
+	// doesn't exist. It is used by CreateRelease only.
+	Ref string
+}
+
+// CreateRelease publishes a release for the tag.
+func (cli *Client) CreateRelease(pid interface{}, tag string, opts ReleaseOptions) (*gitlab.Release, error) {
+	v := &gitlab.CreateReleaseOptions{
+		Name:        optional(opts.Name),
+		TagName:     gitlab.Ptr(tag),
+		Description: optional(opts.Description),
+		Ref:         optional(opts.Ref),
+	}
+
+	if len(opts.Milestones) > 0 {
+		v.Milestones = &opts.Milestones
+	}
+
+	if len(opts.Links) > 0 {
+		links := make([]*gitlab.ReleaseAssetLinkOptions, len(opts.Links))
+		for i := range opts.Links {
+			item := &opts.Links[i]
+
+			links[i] = &gitlab.ReleaseAssetLinkOptions{
+				Name:     gitlab.Ptr(item.Name),
+				URL:      gitlab.Ptr(item.URL),
+				LinkType: optional(item.LinkType),
+			}
+		}
+
+		v.Assets = &gitlab.ReleaseAssetsOptions{Links: links}
+	}
+
+	r, _, err := cli.c.Releases.CreateRelease(pid, v)
+
+	return r, err
+}
+
+// UpdateRelease replaces the name, description and milestones of the
+// release of the tag. The links are matched by name: the ones which exist
+// are updated and the others are added. Links not in opts are kept.
+func (cli *Client) UpdateRelease(pid interface{}, tag string, opts ReleaseOptions) (*gitlab.Release, error) {
+	v := &gitlab.UpdateReleaseOptions{
+		Name:        gitlab.Ptr(opts.Name),
+		Description: gitlab.Ptr(opts.Description),
+	}
+
+	if len(opts.Milestones) > 0 {
+		v.Milestones = &opts.Milestones
+	}
+
+	r, _, err := cli.c.Releases.UpdateRelease(pid, tag, v)
+	if err != nil || len(opts.Links) == 0 {
+		return r, err
+	}
+
+	existing := make(map[string]int, len(r.Assets.Links))
+	for _, item := range r.Assets.Links {
+		existing[item.Name] = item.ID
+	}
+
+	for i := range opts.Links {
+		if err := cli.upsertReleaseLink(pid, tag, &opts.Links[i], existing); err != nil {
+			return nil, err
+		}
+	}
+
+	r, _, err = cli.c.Releases.GetRelease(pid, tag)
+
+	return r, err
+}
+
+func (cli *Client) upsertReleaseLink(pid interface{}, tag string, link *ReleaseLink, existing map[string]int) error {
+	if id, ok := existing[link.Name]; ok {
+		_, _, err := cli.c.ReleaseLinks.UpdateReleaseLink(pid, tag, id, &gitlab.UpdateReleaseLinkOptions{
+			URL:      gitlab.Ptr(link.URL),
+			LinkType: optional(link.LinkType),
+		})
+
+		return err
+	}
+
+	_, _, err := cli.c.ReleaseLinks.CreateReleaseLink(pid, tag, &gitlab.CreateReleaseLinkOptions{
+		Name:     gitlab.Ptr(link.Name),
+		URL:      gitlab.Ptr(link.URL),
+		LinkType: optional(link.LinkType),
+	})
+
+	return err
+}
+
+// ListReleases returns all the releases of the project, the latest first.
+func (cli *Client) ListReleases(pid interface{}) ([]*gitlab.Release, error) {
+	v := &gitlab.ListReleasesOptions{}
+
+	return CollectAll(func(opts *gitlab.ListOptions) ([]*gitlab.Release, *gitlab.Response, error) {
+		v.ListOptions = *opts
+
+		return cli.c.Releases.ListReleases(pid, v)
+	})
+}
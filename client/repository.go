@@ -0,0 +1,79 @@
+package client
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+
+	"github.com/xanzy/go-gitlab"
+)
+
+// GetPathContent returns the content of the file at path on ref, which
+// can be a branch, a tag or a commit SHA.
+func (cli *Client) GetPathContent(pid interface{}, path, ref string) ([]byte, error) {
+	f, _, err := cli.c.RepositoryFiles.GetFile(
+		pid, path, &gitlab.GetFileOptions{Ref: gitlab.Ptr(ref)},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	switch f.Encoding {
+	case "base64":
+		return base64.StdEncoding.DecodeString(f.Content)
+
+	case "", "text":
+		return []byte(f.Content), nil
+
+	default:
+		return nil, fmt.Errorf("unknown encoding %s of file %s", f.Encoding, path)
+	}
+}
+
+// GetDirectoryTree returns the files and directories directly under the
+// directory at path on ref. An empty path means the root directory.
+func (cli *Client) GetDirectoryTree(pid interface{}, path, ref string) ([]*gitlab.TreeNode, error) {
+	return cli.ListRepositoryTree(pid, path, ref, false)
+}
+
+// ListRepositoryTree returns the files and directories under the directory
+// at path on ref, including the ones in the subdirectories if recursive is
+// true. An empty path means the root directory.
+func (cli *Client) ListRepositoryTree(pid interface{}, path, ref string, recursive bool) ([]*gitlab.TreeNode, error) {
+	v := &gitlab.ListTreeOptions{
+		Path:      optional(path),
+		Ref:       gitlab.Ptr(ref),
+		Recursive: gitlab.Ptr(recursive),
+	}
+
+	return CollectAll(func(opts *gitlab.ListOptions) ([]*gitlab.TreeNode, *gitlab.Response, error) {
+		v.ListOptions = *opts
+
+		return cli.c.Repositories.ListTree(pid, v)
+	})
+}
+
+// DownloadArchive writes the archive of the repository at ref to w. The
+// format is one of tar.gz, tar.bz2, tbz, tbz2, tb2, bz2, tar and zip, and
+// defaults to tar.gz if empty.
+func (cli *Client) DownloadArchive(pid interface{}, ref, format string, w io.Writer) error {
+	_, err := cli.c.Repositories.StreamArchive(pid, w, &gitlab.ArchiveOptions{
+		Format: optional(format),
+		SHA:    gitlab.Ptr(ref),
+	})
+
+	return err
+}
+
+// Compare returns the commits and the diffs between from and to, which can
+// be branches, tags or commit SHAs. The comparison is made from the merge
+// base of them, the same as git diff from...to does. The diffs may be
+// incomplete when the CompareTimeout of the result is true.
+func (cli *Client) Compare(pid interface{}, from, to string) (*gitlab.Compare, error) {
+	v, _, err := cli.c.Repositories.Compare(pid, &gitlab.CompareOptions{
+		From: gitlab.Ptr(from),
+		To:   gitlab.Ptr(to),
+	})
+
+	return v, err
+}
@@ -0,0 +1,148 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"slices"
+	"time"
+)
+
+const (
+	defaultRetryAttempts   = 3
+	defaultRetryMinBackoff = 200 * time.Millisecond
+	defaultRetryMaxBackoff = 5 * time.Second
+)
+
+var defaultRetryStatusCodes = []int{
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// RetryPolicy decides which failed requests are sent again and when. The
+// zero value of each field takes the default. The requests rejected by 429
+// are retried according to RateLimitOptions instead.
+type RetryPolicy struct {
+	// MaxAttempts is how many times a request is sent at most. It is 3 if
+	// zero, and 1 disables the retries.
+	MaxAttempts int
+
+	// MinBackoff is the wait before the first retry, which doubles at each
+	// retry up to MaxBackoff. They are 200ms and 5s if zero. Some jitter is
+	// applied to the waits.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	// StatusCodes are the status codes of the responses to retry. They
+	// are 502, 503 and 504 if empty.
+	StatusCodes []int
+
+	// NoRetryOnNetworkError disables retrying the requests which fail
+	// without a response, such as on a reset connection.
+	NoRetryOnNetworkError bool
+
+	// Retryable, if not nil, decides whether to retry instead of
+	// StatusCodes and NoRetryOnNetworkError. Either resp or err is nil.
+	// Without it, only the GET, HEAD, PUT and DELETE requests are retried,
+	// because the others may have taken effect even if they failed.
+	Retryable func(req *http.Request, resp *http.Response, err error) bool
+}
+
+// WithRetry configures how the client retries the failed requests. The
+// client retries by the default policy without it.
+func WithRetry(p RetryPolicy) Option {
+	return func(o *options) {
+		o.retry = p
+	}
+}
+
+// retryTransport retries the failed requests by the policy.
+type retryTransport struct {
+	base   http.RoundTripper
+	policy RetryPolicy
+}
+
+func newRetryTransport(base http.RoundTripper, p RetryPolicy) *retryTransport {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = defaultRetryAttempts
+	}
+
+	if p.MinBackoff <= 0 {
+		p.MinBackoff = defaultRetryMinBackoff
+	}
+
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = defaultRetryMaxBackoff
+	}
+
+	if len(p.StatusCodes) == 0 {
+		p.StatusCodes = defaultRetryStatusCodes
+	}
+
+	return &retryTransport{base: base, policy: p}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+
+		if attempt >= t.policy.MaxAttempts || !t.retryable(req, resp, err) ||
+			req.GetBody == nil && req.Body != nil && req.Body != http.NoBody {
+
+			return resp, err
+		}
+
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		if err := sleep(req, t.backoff(attempt)); err != nil {
+			return nil, err
+		}
+
+		if req, err = rewind(req); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (t *retryTransport) retryable(req *http.Request, resp *http.Response, err error) bool {
+	if t.policy.Retryable != nil {
+		return t.policy.Retryable(req, resp, err)
+	}
+
+	// The other requests may have taken effect even if they failed, such
+	// as a comment posted before the proxy timed out.
+	if !idempotent(req.Method) {
+		return false
+	}
+
+	if err != nil {
+		return !t.policy.NoRetryOnNetworkError && !errors.Is(err, context.Canceled) &&
+			!errors.Is(err, context.DeadlineExceeded)
+	}
+
+	return slices.Contains(t.policy.StatusCodes, resp.StatusCode)
+}
+
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// backoff returns the wait before the retry following the attempt, which
+// is between the half and the whole of the exponential backoff.
+func (t *retryTransport) backoff(attempt int) time.Duration {
+	d := t.policy.MaxBackoff
+	if attempt < 32 {
+		d = min(t.policy.MinBackoff<<(attempt-1), t.policy.MaxBackoff)
+	}
+
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
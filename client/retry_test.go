@@ -0,0 +1,90 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRetryTransport(t *testing.T) {
+	errReset := errors.New("connection reset")
+
+	tests := []struct {
+		name     string
+		method   string
+		policy   RetryPolicy
+		code     int
+		err      error
+		attempts int
+	}{
+		{name: "get on 502", method: http.MethodGet, code: 502, attempts: 3},
+		{name: "put on 504", method: http.MethodPut, code: 504, attempts: 3},
+		{name: "get on 500", method: http.MethodGet, code: 500, attempts: 1},
+		{name: "get on success", method: http.MethodGet, code: 200, attempts: 1},
+		{name: "post on 502", method: http.MethodPost, code: 502, attempts: 1},
+		{name: "patch on 503", method: http.MethodPatch, code: 503, attempts: 1},
+		{name: "get on network error", method: http.MethodGet, err: errReset, attempts: 3},
+		{name: "post on network error", method: http.MethodPost, err: errReset, attempts: 1},
+		{
+			name:     "get on network error disabled",
+			method:   http.MethodGet,
+			policy:   RetryPolicy{NoRetryOnNetworkError: true},
+			err:      errReset,
+			attempts: 1,
+		},
+		{
+			name:     "get on 500 configured",
+			method:   http.MethodGet,
+			policy:   RetryPolicy{StatusCodes: []int{500}},
+			code:     500,
+			attempts: 3,
+		},
+		{
+			name:   "post opted in",
+			method: http.MethodPost,
+			policy: RetryPolicy{
+				MaxAttempts: 2,
+				Retryable: func(req *http.Request, resp *http.Response, err error) bool {
+					return resp != nil && resp.StatusCode == 502
+				},
+			},
+			code:     502,
+			attempts: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+
+			base := roundTripFunc(func(*http.Request) (*http.Response, error) {
+				attempts++
+
+				if tt.err != nil {
+					return nil, tt.err
+				}
+
+				return response(tt.code), nil
+			})
+
+			p := tt.policy
+			p.MinBackoff = time.Millisecond
+			p.MaxBackoff = time.Millisecond
+
+			req, err := http.NewRequest(tt.method, "https://gitlab.example.com/api/v4/projects", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			resp, err := newRetryTransport(base, p).RoundTrip(req)
+			if err == nil {
+				resp.Body.Close()
+			}
+
+			if attempts != tt.attempts {
+				t.Errorf("got %d attempts, want %d", attempts, tt.attempts)
+			}
+		})
+	}
+}
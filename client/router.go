@@ -0,0 +1,98 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ErrNoInstance is returned by Router when no instance serves the project.
+var ErrNoInstance = errors.New("no gitlab instance serves the project")
+
+// Router selects the client of the GitLab instance which a project belongs
+// to, so that one robot can serve several instances. It is not safe to add
+// instances while looking up.
+type Router struct {
+	clients map[string]*Client
+
+	// hosts and groups map to the instance names.
+	hosts  map[string]string
+	groups map[string]string
+
+	defaultName string
+}
+
+// NewRouter creates an empty router.
+func NewRouter() *Router {
+	return &Router{
+		clients: map[string]*Client{},
+		hosts:   map[string]string{},
+		groups:  map[string]string{},
+	}
+}
+
+// AddInstance adds the instance served by cli under name. The projects on
+// the host of cli are routed to it, and so are the projects under groups,
+// which are full group paths such as "org/team", whatever the host is.
+func (r *Router) AddInstance(name string, cli *Client, groups ...string) {
+	r.clients[name] = cli
+	r.hosts[strings.ToLower(cli.c.BaseURL().Host)] = name
+
+	for _, g := range groups {
+		r.groups[strings.Trim(g, "/")] = name
+	}
+}
+
+// SetDefault makes the instance named name serve the projects which match
+// no other instance.
+func (r *Router) SetDefault(name string) {
+	r.defaultName = name
+}
+
+// Instance returns the client of the instance named name.
+func (r *Router) Instance(name string) (*Client, bool) {
+	cli, ok := r.clients[name]
+
+	return cli, ok
+}
+
+// ForURL returns the client for the project at webURL, such as the web_url
+// of the project in a webhook event. The host of the URL is matched first,
+// then the groups of the project path.
+func (r *Router) ForURL(webURL string) (*Client, error) {
+	u, err := url.Parse(webURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if name, ok := r.hosts[strings.ToLower(u.Host)]; ok {
+		return r.clients[name], nil
+	}
+
+	return r.ForProject(u.Path)
+}
+
+// ForProject returns the client for the project at the full path, matching
+// the deepest configured group which contains it.
+func (r *Router) ForProject(path string) (*Client, error) {
+	path = strings.Trim(path, "/")
+
+	for p := path; p != ""; {
+		i := strings.LastIndex(p, "/")
+		if i < 0 {
+			break
+		}
+
+		p = p[:i]
+		if name, ok := r.groups[p]; ok {
+			return r.clients[name], nil
+		}
+	}
+
+	if cli, ok := r.clients[r.defaultName]; ok {
+		return cli, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrNoInstance, path)
+}
@@ -0,0 +1,64 @@
+package client
+
+import (
+	"github.com/xanzy/go-gitlab"
+)
+
+// SearchIssues returns at most limit issues of the project which match the
+// query, or all of them if limit is not positive.
+func (cli *Client) SearchIssues(pid interface{}, query string, limit int) ([]*gitlab.Issue, error) {
+	return search(limit, "", func(opts *gitlab.SearchOptions) ([]*gitlab.Issue, *gitlab.Response, error) {
+		return cli.c.Search.IssuesByProject(pid, query, opts)
+	})
+}
+
+// SearchMergeRequests returns at most limit merge requests of the project
+// which match the query, or all of them if limit is not positive.
+func (cli *Client) SearchMergeRequests(pid interface{}, query string, limit int) ([]*gitlab.MergeRequest, error) {
+	return search(limit, "", func(opts *gitlab.SearchOptions) ([]*gitlab.MergeRequest, *gitlab.Response, error) {
+		return cli.c.Search.MergeRequestsByProject(pid, query, opts)
+	})
+}
+
+// SearchCode returns at most limit snippets of the code on ref which match
+// the query, or all of them if limit is not positive. The default branch is
+// searched if ref is empty.
+func (cli *Client) SearchCode(pid interface{}, query, ref string, limit int) ([]*gitlab.Blob, error) {
+	return search(limit, ref, func(opts *gitlab.SearchOptions) ([]*gitlab.Blob, *gitlab.Response, error) {
+		return cli.c.Search.BlobsByProject(pid, query, opts)
+	})
+}
+
+func search[T any](
+	limit int, ref string,
+	do func(*gitlab.SearchOptions) ([]T, *gitlab.Response, error),
+) ([]T, error) {
+	var r []T
+
+	v := &gitlab.SearchOptions{Ref: optional(ref)}
+
+	err := ForEachPage(
+		func(opts *gitlab.ListOptions) ([]T, *gitlab.Response, error) {
+			v.ListOptions = *opts
+			if limit > 0 && limit < v.PerPage {
+				v.PerPage = limit
+			}
+
+			return do(v)
+		},
+		func(items []T) bool {
+			r = append(r, items...)
+
+			return limit <= 0 || len(r) < limit
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if limit > 0 && len(r) > limit {
+		r = r[:limit]
+	}
+
+	return r, nil
+}
@@ -0,0 +1,95 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/xanzy/go-gitlab"
+)
+
+// ErrTagProtected is returned by CreateTag and DeleteTag when the tag
+// matches a protected tag rule which the user owning the token is not
+// allowed to bypass.
+var ErrTagProtected = errors.New("tag is protected")
+
+// CreateTag creates the tag on ref. An annotated tag is created if message
+// is not empty.
+func (cli *Client) CreateTag(pid interface{}, tag, ref, message string) (*gitlab.Tag, error) {
+	v, resp, err := cli.c.Tags.CreateTag(pid, &gitlab.CreateTagOptions{
+		TagName: gitlab.Ptr(tag),
+		Ref:     gitlab.Ptr(ref),
+		Message: optional(message),
+	})
+	if err != nil {
+		return nil, cli.classifyTagError(pid, tag, resp, err)
+	}
+
+	return v, nil
+}
+
+// DeleteTag deletes the tag.
+func (cli *Client) DeleteTag(pid interface{}, tag string) error {
+	resp, err := cli.c.Tags.DeleteTag(pid, tag)
+	if err != nil {
+		return cli.classifyTagError(pid, tag, resp, err)
+	}
+
+	return nil
+}
+
+// classifyTagError wraps err with ErrTagProtected if the tag is protected.
+func (cli *Client) classifyTagError(pid interface{}, tag string, resp *gitlab.Response, err error) error {
+	if resp == nil {
+		return err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusForbidden, http.StatusMethodNotAllowed, http.StatusBadRequest:
+	default:
+		return err
+	}
+
+	if protected, err1 := cli.isTagProtected(pid, tag); err1 == nil && protected {
+		return fmt.Errorf("%w: %w", ErrTagProtected, err)
+	}
+
+	return err
+}
+
+func (cli *Client) isTagProtected(pid interface{}, tag string) (bool, error) {
+	protected := false
+
+	err := ForEachPage(
+		func(opts *gitlab.ListOptions) ([]*gitlab.ProtectedTag, *gitlab.Response, error) {
+			return cli.c.ProtectedTags.ListProtectedTags(pid, (*gitlab.ListProtectedTagsOptions)(opts))
+		},
+		func(items []*gitlab.ProtectedTag) bool {
+			for _, item := range items {
+				if matchWildcard(item.Name, tag) {
+					protected = true
+
+					return false
+				}
+			}
+
+			return true
+		},
+	)
+
+	return protected, err
+}
+
+// matchWildcard reports whether name matches the pattern of a protected
+// tag or branch, in which * matches any sequence of characters.
+func matchWildcard(pattern, name string) bool {
+	if !strings.Contains(pattern, "*") {
+		return pattern == name
+	}
+
+	expr := strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*")
+
+	return regexp.MustCompile("^" + expr + "$").MatchString(name)
+}
@@ -0,0 +1,47 @@
+package client
+
+import (
+	"github.com/xanzy/go-gitlab"
+)
+
+// The durations of time tracking are in the form of GitLab, such as "1h30m"
+// or "1w2d", where a day is 8 hours and a week is 5 days by default.
+
+// AddIssueSpentTime adds the duration to the time spent on the issue. The
+// duration is subtracted if it starts with "-". The summary is optional.
+func (cli *Client) AddIssueSpentTime(pid interface{}, iid int, duration, summary string) (*gitlab.TimeStats, error) {
+	v, _, err := cli.c.Issues.AddSpentTime(pid, iid, &gitlab.AddSpentTimeOptions{
+		Duration: gitlab.Ptr(duration),
+		Summary:  optional(summary),
+	})
+
+	return v, err
+}
+
+// SetIssueTimeEstimate sets the estimated time of the issue.
+func (cli *Client) SetIssueTimeEstimate(pid interface{}, iid int, duration string) (*gitlab.TimeStats, error) {
+	v, _, err := cli.c.Issues.SetTimeEstimate(pid, iid, &gitlab.SetTimeEstimateOptions{
+		Duration: gitlab.Ptr(duration),
+	})
+
+	return v, err
+}
+
+// AddMRSpentTime is AddIssueSpentTime for the merge request.
+func (cli *Client) AddMRSpentTime(pid interface{}, iid int, duration, summary string) (*gitlab.TimeStats, error) {
+	v, _, err := cli.c.MergeRequests.AddSpentTime(pid, iid, &gitlab.AddSpentTimeOptions{
+		Duration: gitlab.Ptr(duration),
+		Summary:  optional(summary),
+	})
+
+	return v, err
+}
+
+// SetMRTimeEstimate is SetIssueTimeEstimate for the merge request.
+func (cli *Client) SetMRTimeEstimate(pid interface{}, iid int, duration string) (*gitlab.TimeStats, error) {
+	v, _, err := cli.c.MergeRequests.SetTimeEstimate(pid, iid, &gitlab.SetTimeEstimateOptions{
+		Duration: gitlab.Ptr(duration),
+	})
+
+	return v, err
+}
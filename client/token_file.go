@@ -0,0 +1,103 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TokenFile is a token, or any secret, kept in a file such as a mounted
+// Kubernetes secret, which can be rotated without restarting the robot.
+// Pass its Token method to NewClient, and run Watch to pick up the new
+// token when the file changes.
+type TokenFile struct {
+	path  string
+	token atomic.Pointer[[]byte]
+}
+
+// LoadTokenFile reads the token in the file, ignoring the surrounding
+// spaces.
+func LoadTokenFile(path string) (*TokenFile, error) {
+	f := &TokenFile{path: path}
+	if _, err := f.Reload(); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// Token returns the current token. It is safe for concurrent use.
+func (f *TokenFile) Token() []byte {
+	return *f.token.Load()
+}
+
+// Reload reads the file again and returns whether the token has changed.
+// The token is kept if the file is missing or empty, which happens for a
+// moment while the secret is being rotated.
+func (f *TokenFile) Reload() (bool, error) {
+	b, err := os.ReadFile(f.path)
+	if err != nil {
+		return false, fmt.Errorf("read the token file %s: %w", f.path, err)
+	}
+
+	b = bytes.TrimSpace(b)
+	if len(b) == 0 {
+		return false, fmt.Errorf("token file %s is empty", f.path)
+	}
+
+	if old := f.token.Load(); old != nil && bytes.Equal(*old, b) {
+		return false, nil
+	}
+
+	f.token.Store(&b)
+
+	return true, nil
+}
+
+// Watch reloads the file every interval until ctx is done. The file is
+// polled rather than watched by inotify, since the Kubernetes secrets are
+// updated by replacing the symbolic links of their directories.
+func (f *TokenFile) Watch(ctx context.Context, interval time.Duration) {
+	log := logrus.WithField("token-file", f.path)
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-t.C:
+		}
+
+		changed, err := f.Reload()
+		switch {
+		case err != nil:
+			log.WithError(err).Warn("reload the token, keep the current one")
+
+		case changed:
+			log.Info("reloaded the token")
+		}
+	}
+}
+
+// tokenTransport sets the token of each request to the one getToken
+// returns at the time, so that the rotated token takes effect at once.
+type tokenTransport struct {
+	base     http.RoundTripper
+	getToken func() []byte
+}
+
+func (t *tokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	r := req.Clone(req.Context())
+	r.Header.Set("PRIVATE-TOKEN", string(t.getToken()))
+
+	return t.base.RoundTrip(r)
+}
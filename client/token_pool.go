@@ -0,0 +1,118 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// TokenRotation is how the client picks a token from the pool for each
+// request.
+type TokenRotation int
+
+const (
+	// RoundRobin uses the tokens in turn.
+	RoundRobin TokenRotation = iota
+
+	// LeastRecentlyThrottled uses the token which was rate limited the
+	// longest time ago, preferring the ones never rate limited.
+	LeastRecentlyThrottled
+)
+
+// WithTokenPool makes the client spread the requests over several access
+// tokens, so that it is not capped by the rate limit of a single one. The
+// token passed to NewClient is the first one of the pool.
+func WithTokenPool(tokens []string, rotation TokenRotation) Option {
+	return func(o *options) {
+		o.poolTokens = tokens
+		o.rotation = rotation
+	}
+}
+
+type pooledToken struct {
+	value       string
+	lastUsed    time.Time
+	throttledAt time.Time
+}
+
+// tokenPoolTransport sets the token of each request picked from the pool,
+// and marks the token as throttled when it runs out of quota.
+type tokenPoolTransport struct {
+	base     http.RoundTripper
+	rotation TokenRotation
+
+	mu     sync.Mutex
+	tokens []*pooledToken
+	next   int
+}
+
+// newTokenPoolTransport returns the transport of the tokens without the
+// empty and the duplicate ones, or an error if there are none left.
+func newTokenPoolTransport(
+	base http.RoundTripper, tokens []string, rotation TokenRotation,
+) (*tokenPoolTransport, error) {
+	t := &tokenPoolTransport{base: base, rotation: rotation}
+
+	seen := map[string]bool{}
+	for _, v := range tokens {
+		if v != "" && !seen[v] {
+			seen[v] = true
+			t.tokens = append(t.tokens, &pooledToken{value: v})
+		}
+	}
+
+	if len(t.tokens) == 0 {
+		return nil, errors.New("no token in the pool")
+	}
+
+	return t, nil
+}
+
+func (t *tokenPoolTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	tok := t.pick()
+
+	r := req.Clone(req.Context())
+	r.Header.Set("PRIVATE-TOKEN", tok.value)
+
+	resp, err := t.base.RoundTrip(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests ||
+		resp.Header.Get("RateLimit-Remaining") == "0" {
+
+		t.mu.Lock()
+		tok.throttledAt = time.Now()
+		t.mu.Unlock()
+	}
+
+	return resp, nil
+}
+
+func (t *tokenPoolTransport) pick() *pooledToken {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var tok *pooledToken
+
+	switch t.rotation {
+	case LeastRecentlyThrottled:
+		for _, item := range t.tokens {
+			if tok == nil || item.throttledAt.Before(tok.throttledAt) ||
+				item.throttledAt.Equal(tok.throttledAt) && item.lastUsed.Before(tok.lastUsed) {
+
+				tok = item
+			}
+		}
+
+	default:
+		tok = t.tokens[t.next%len(t.tokens)]
+		t.next++
+	}
+
+	tok.lastUsed = time.Now()
+
+	return tok
+}
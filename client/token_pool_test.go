@@ -0,0 +1,59 @@
+package client
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func TestNewClientTokenPool(t *testing.T) {
+	tests := []struct {
+		name    string
+		token   string
+		pool    []string
+		rotated []string
+		wantErr bool
+	}{
+		{name: "empty tokens", pool: []string{"", ""}, wantErr: true},
+		{name: "deduplicated", token: "a", pool: []string{"b", "a", ""}, rotated: []string{"a", "b", "a"}},
+		{name: "empty first token", pool: []string{"b"}, rotated: []string{"b", "b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewClient(func() []byte { return []byte(tt.token) }, "https://gitlab.com",
+				WithTokenPool(tt.pool, LeastRecentlyThrottled))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %t", err, tt.wantErr)
+			}
+
+			if err != nil {
+				return
+			}
+
+			var got []string
+
+			base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				got = append(got, req.Header.Get("PRIVATE-TOKEN"))
+
+				return response(http.StatusOK), nil
+			})
+
+			pool, err := newTokenPoolTransport(base, append([]string{tt.token}, tt.pool...), RoundRobin)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for range tt.rotated {
+				req, _ := http.NewRequest(http.MethodGet, "https://gitlab.com/api/v4/user", nil)
+				if _, err := pool.RoundTrip(req); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if !slices.Equal(got, tt.rotated) {
+				t.Errorf("got %v, want %v", got, tt.rotated)
+			}
+		})
+	}
+}
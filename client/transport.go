@@ -0,0 +1,70 @@
+package client
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// TransportOptions configure the HTTP connections to GitLab. The zero value
+// of each field keeps the default of http.DefaultTransport.
+type TransportOptions struct {
+	// Timeout limits the time of each request, including reading the
+	// response body. There is no limit if zero.
+	Timeout time.Duration
+
+	// Proxy is the URL of the proxy. The proxy is read from the
+	// environment variables such as HTTPS_PROXY if nil.
+	Proxy *url.URL
+
+	// TLSConfig, if not nil, is used for the TLS connections, such as to
+	// trust a private CA by its RootCAs.
+	TLSConfig *tls.Config
+
+	DisableKeepAlives   bool
+	MaxIdleConns        int
+	MaxIdleConnsPerHost int
+	MaxConnsPerHost     int
+	IdleConnTimeout     time.Duration
+}
+
+// WithTransport configures the HTTP connections of the client.
+func WithTransport(opts TransportOptions) Option {
+	return func(o *options) {
+		o.transport = opts
+	}
+}
+
+// newTransport returns the transport which sends the requests to GitLab.
+func (opts *TransportOptions) newTransport() *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+
+	if opts.Proxy != nil {
+		t.Proxy = http.ProxyURL(opts.Proxy)
+	}
+
+	if opts.TLSConfig != nil {
+		t.TLSClientConfig = opts.TLSConfig
+	}
+
+	t.DisableKeepAlives = opts.DisableKeepAlives
+
+	if opts.MaxIdleConns > 0 {
+		t.MaxIdleConns = opts.MaxIdleConns
+	}
+
+	if opts.MaxIdleConnsPerHost > 0 {
+		t.MaxIdleConnsPerHost = opts.MaxIdleConnsPerHost
+	}
+
+	if opts.MaxConnsPerHost > 0 {
+		t.MaxConnsPerHost = opts.MaxConnsPerHost
+	}
+
+	if opts.IdleConnTimeout > 0 {
+		t.IdleConnTimeout = opts.IdleConnTimeout
+	}
+
+	return t
+}
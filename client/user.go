@@ -0,0 +1,52 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/xanzy/go-gitlab"
+)
+
+// ErrUserNotFound is returned when there is no user with the username.
+var ErrUserNotFound = errors.New("user not found")
+
+// GetUserByUsername returns the user with the username. The error wraps
+// ErrUserNotFound if there is no such user.
+func (cli *Client) GetUserByUsername(username string) (*gitlab.User, error) {
+	v, _, err := cli.c.Users.ListUsers(&gitlab.ListUsersOptions{
+		Username: gitlab.Ptr(username),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(v) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrUserNotFound, username)
+	}
+
+	return v[0], nil
+}
+
+// GetUserByID returns the user with the ID.
+func (cli *Client) GetUserByID(id int) (*gitlab.User, error) {
+	v, _, err := cli.c.Users.GetUser(id, gitlab.GetUsersOptions{})
+
+	return v, err
+}
+
+// GetCurrentUser returns the user owning the token.
+func (cli *Client) GetCurrentUser() (*gitlab.User, error) {
+	v, _, err := cli.c.Users.CurrentUser()
+
+	return v, err
+}
+
+// userID resolves the username to the ID of the user.
+func (cli *Client) userID(username string) (int, error) {
+	v, err := cli.GetUserByUsername(username)
+	if err != nil {
+		return 0, err
+	}
+
+	return v.ID, nil
+}
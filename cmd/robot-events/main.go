@@ -0,0 +1,232 @@
+// Command robot-events lists and replays the events persisted by a robot
+// through the admin API on its debug port.
+//
+//	robot-events [-addr URL] [-token-file FILE] list [filters]
+//	robot-events [-addr URL] show ID
+//	robot-events [-addr URL] replay ID
+//	robot-events [-addr URL] replay [filters] [-all]
+//
+// The filters are -since and -until in RFC 3339, -endpoint, -type,
+// -project and -limit. Replaying the events requires a filter other than
+// -limit, or -all to replay all the events stored. At most 100 events are
+// listed unless -limit is given.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/opensourceways/robot-gitlab-lib/framework"
+)
+
+func main() {
+	addr := flag.String("addr", "http://localhost:6060", "Base URL of the debug port of the robot.")
+	tokenFile := flag.String("token-file", "", "Path to the file containing the admin token of the robot.")
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	c := &adminClient{base: strings.TrimSuffix(*addr, "/")}
+
+	if *tokenFile != "" {
+		b, err := os.ReadFile(*tokenFile)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+
+		c.token = strings.TrimSpace(string(b))
+	}
+
+	var err error
+	switch cmd, args := flag.Arg(0), flag.Args()[1:]; cmd {
+	case "list":
+		err = c.list(args)
+	case "show":
+		err = c.show(args)
+	case "replay":
+		err = c.replay(args)
+	default:
+		usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintf(flag.CommandLine.Output(), `usage: robot-events [-addr URL] [-token-file FILE] list [filters]
+       robot-events [-addr URL] show ID
+       robot-events [-addr URL] replay ID | [filters] [-all]
+
+filters: -since TIME -until TIME -endpoint PATH -type TYPE -project PATH -limit N
+`)
+	flag.PrintDefaults()
+}
+
+// parseFilters parses the filters into the query of the admin API. The
+// replay requires a filter other than -limit, or -all.
+func parseFilters(name string, args []string) (url.Values, error) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+
+	var all *bool
+	if name == "replay" {
+		all = fs.Bool("all", false, "")
+	}
+
+	names := []string{"since", "until", "endpoint", "type", "project", "limit"}
+	values := make([]*string, len(names))
+	for i, n := range names {
+		values[i] = fs.String(n, "", "")
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected arguments %v", fs.Args())
+	}
+
+	q := url.Values{}
+	for i, n := range names {
+		if *values[i] != "" {
+			q.Set(n, *values[i])
+		}
+	}
+
+	if all != nil {
+		switch {
+		case *all:
+			q.Set("all", "true")
+		case len(q) == 0 || len(q) == 1 && q.Has("limit"):
+			return nil, fmt.Errorf("replay requires the ID, a filter other than -limit, or -all")
+		}
+	}
+
+	return q, nil
+}
+
+type adminClient struct {
+	base  string
+	token string
+}
+
+func (c *adminClient) list(args []string) error {
+	q, err := parseFilters("list", args)
+	if err != nil {
+		return err
+	}
+
+	var v []framework.StoredEvent
+	if err := c.do(http.MethodGet, "/events?"+q.Encode(), &v); err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tRECEIVED\tENDPOINT\tTYPE\tPROJECT\tUUID")
+
+	for i := range v {
+		e := &v[i]
+		fmt.Fprintf(
+			w, "%d\t%s\t%s\t%s\t%s\t%s\n",
+			e.ID, e.Received.Format(time.RFC3339), e.Endpoint, e.EventType, e.Project, e.UUID,
+		)
+	}
+
+	return w.Flush()
+}
+
+func (c *adminClient) show(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("show requires the ID of the event")
+	}
+
+	var v framework.StoredEvent
+	if err := c.do(http.MethodGet, "/events/"+args[0], &v); err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(&v)
+}
+
+func (c *adminClient) replay(args []string) error {
+	var results []framework.ReplayResult
+
+	if len(args) == 1 && !strings.HasPrefix(args[0], "-") {
+		var r framework.ReplayResult
+		if err := c.do(http.MethodPost, "/events/"+args[0]+"/replay", &r); err != nil {
+			return err
+		}
+
+		results = append(results, r)
+	} else {
+		q, err := parseFilters("replay", args)
+		if err != nil {
+			return err
+		}
+
+		if err := c.do(http.MethodPost, "/events/replay?"+q.Encode(), &results); err != nil {
+			return err
+		}
+	}
+
+	failed := 0
+	for _, r := range results {
+		if r.Error == "" {
+			fmt.Printf("%d\tok\n", r.ID)
+		} else {
+			fmt.Printf("%d\t%s\n", r.ID, r.Error)
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d events failed", failed, len(results))
+	}
+
+	return nil
+}
+
+func (c *adminClient) do(method, path string, result interface{}) error {
+	req, err := http.NewRequest(method, c.base+path, nil)
+	if err != nil {
+		return err
+	}
+
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+
+		return fmt.Errorf("%s %s: %s: %s", method, path, resp.Status, strings.TrimSpace(string(b)))
+	}
+
+	return json.NewDecoder(resp.Body).Decode(result)
+}
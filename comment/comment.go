@@ -0,0 +1,183 @@
+// Package comment renders the bodies of the comments of the robots by
+// text/template, with the helpers of the Markdown GitLab renders, so that
+// the robots keep the wording of their comments in the templates instead
+// of formatting them in the code. Catalogs keep the templates of several
+// languages for the communities of different languages.
+package comment
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+	"text/template"
+)
+
+// Funcs are the helpers available to the templates:
+//
+//	mention "alice"                 @alice
+//	mentions .Reviewers             @alice @bob
+//	code "go" .Snippet              a fenced code block of the language
+//	inlineCode .Branch              `main`
+//	details "Logs" .Logs            a collapsible section
+//	quote .Note                     the text quoted line by line
+//	join ", " .Labels               the strings joined by the separator
+func Funcs() template.FuncMap {
+	return template.FuncMap{
+		"mention":    Mention,
+		"mentions":   Mentions,
+		"code":       CodeBlock,
+		"inlineCode": InlineCode,
+		"details":    Details,
+		"quote":      Quote,
+		"join":       func(sep string, v []string) string { return strings.Join(v, sep) },
+	}
+}
+
+// Mention returns the mention of the user, which notifies the user.
+func Mention(username string) string {
+	return "@" + strings.TrimPrefix(username, "@")
+}
+
+// Mentions returns the mentions of the users separated by spaces.
+func Mentions(usernames []string) string {
+	v := make([]string, len(usernames))
+	for i, u := range usernames {
+		v[i] = Mention(u)
+	}
+
+	return strings.Join(v, " ")
+}
+
+// CodeBlock returns the text in a fenced code block of the language, which
+// can be empty. The fence is longer than any run of backticks in the text,
+// so that the text cannot close it.
+func CodeBlock(lang, text string) string {
+	fence := strings.Repeat("`", max(3, longestRun(text, '`')+1))
+
+	return fmt.Sprintf("%s%s\n%s\n%s", fence, lang, strings.TrimSuffix(text, "\n"), fence)
+}
+
+// InlineCode returns the text as inline code.
+func InlineCode(text string) string {
+	fence := strings.Repeat("`", longestRun(text, '`')+1)
+	if strings.HasPrefix(text, "`") || strings.HasSuffix(text, "`") {
+		text = " " + text + " "
+	}
+
+	return fence + text + fence
+}
+
+// Details returns the body in a section collapsed under the summary.
+func Details(summary, body string) string {
+	return fmt.Sprintf(
+		"<details>\n<summary>%s</summary>\n\n%s\n\n</details>",
+		template.HTMLEscapeString(summary), strings.TrimSuffix(body, "\n"),
+	)
+}
+
+// Quote returns the text quoted line by line.
+func Quote(text string) string {
+	lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimRight("> "+l, " ")
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func longestRun(s string, c byte) int {
+	n, longest := 0, 0
+
+	for i := 0; i < len(s); i++ {
+		if s[i] != c {
+			n = 0
+
+			continue
+		}
+
+		n++
+		longest = max(longest, n)
+	}
+
+	return longest
+}
+
+// Templates are the named templates of the comments. The zero value is not
+// usable, call New.
+type Templates struct {
+	t *template.Template
+}
+
+// New returns the empty templates.
+func New() *Templates {
+	return &Templates{
+		t: template.New("").Funcs(Funcs()).Option("missingkey=error"),
+	}
+}
+
+// Parse adds the template of the name, replacing the existing one, so
+// that the defaults of a robot can be overridden by its configuration.
+func (ts *Templates) Parse(name, text string) error {
+	if _, err := ts.t.New(name).Parse(text); err != nil {
+		return fmt.Errorf("parse the template %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// ParseMap adds the templates keyed by their names, which are usually a
+// section of the configuration of a robot.
+func (ts *Templates) ParseMap(m map[string]string) error {
+	for name, text := range m {
+		if err := ts.Parse(name, text); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ParseFS adds the templates of the files of fsys matching the pattern,
+// named by the base names of the files without the extension.
+func (ts *Templates) ParseFS(fsys fs.FS, pattern string) error {
+	files, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range files {
+		b, err := fs.ReadFile(fsys, f)
+		if err != nil {
+			return err
+		}
+
+		base := path.Base(f)
+		if err := ts.Parse(strings.TrimSuffix(base, path.Ext(base)), string(b)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Has returns whether the template of the name exists.
+func (ts *Templates) Has(name string) bool {
+	return ts.t.Lookup(name) != nil
+}
+
+// Render executes the template of the name with data and returns the
+// comment body.
+func (ts *Templates) Render(name string, data interface{}) (string, error) {
+	t := ts.t.Lookup(name)
+	if t == nil {
+		return "", fmt.Errorf("no template %s", name)
+	}
+
+	var b strings.Builder
+	if err := t.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("render the template %s: %w", name, err)
+	}
+
+	return strings.TrimSpace(b.String()), nil
+}
@@ -0,0 +1,126 @@
+package comment
+
+import (
+	"fmt"
+	"io/fs"
+	"strings"
+)
+
+// Languages configures the languages of the comments of the projects.
+type Languages struct {
+	// Default is the language of the projects not configured, which is
+	// also the one falling back to for the messages missing in the
+	// catalog of a language.
+	Default string `json:"default"`
+
+	// Projects map the paths of the projects, or of the groups for all
+	// the projects under them, to the languages. The longest path
+	// matching a project wins.
+	Projects map[string]string `json:"projects,omitempty"`
+}
+
+// language returns the language of the project.
+func (l *Languages) language(project string) string {
+	lang, longest := l.Default, -1
+
+	for p, v := range l.Projects {
+		p = strings.Trim(p, "/")
+		if project != p && !strings.HasPrefix(project, p+"/") {
+			continue
+		}
+
+		if len(p) > longest {
+			lang, longest = v, len(p)
+		}
+	}
+
+	return lang
+}
+
+// Catalogs are the message catalogs of the languages, each of which is the
+// templates of the messages in the language, such as zh and en. They
+// render the messages in the language of the project.
+type Catalogs struct {
+	langs     Languages
+	templates map[string]*Templates
+}
+
+// NewCatalogs returns the empty catalogs of the languages.
+func NewCatalogs(langs Languages) (*Catalogs, error) {
+	if langs.Default == "" {
+		return nil, fmt.Errorf("missing the default language")
+	}
+
+	return &Catalogs{
+		langs:     langs,
+		templates: map[string]*Templates{},
+	}, nil
+}
+
+// Catalog returns the templates of the messages in the language, which the
+// messages are added to.
+func (c *Catalogs) Catalog(lang string) *Templates {
+	ts, ok := c.templates[lang]
+	if !ok {
+		ts = New()
+		c.templates[lang] = ts
+	}
+
+	return ts
+}
+
+// ParseMap adds the messages in the language keyed by their names.
+func (c *Catalogs) ParseMap(lang string, m map[string]string) error {
+	if err := c.Catalog(lang).ParseMap(m); err != nil {
+		return fmt.Errorf("catalog %s: %w", lang, err)
+	}
+
+	return nil
+}
+
+// ParseFS adds the catalog of each language from the directory named after
+// it in fsys, such as en/lgtm.tmpl and zh/lgtm.tmpl. See Templates.ParseFS.
+func (c *Catalogs) ParseFS(fsys fs.FS, ext string) error {
+	dirs, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return err
+	}
+
+	for _, d := range dirs {
+		if !d.IsDir() {
+			continue
+		}
+
+		if err := c.Catalog(d.Name()).ParseFS(fsys, d.Name()+"/*"+ext); err != nil {
+			return fmt.Errorf("catalog %s: %w", d.Name(), err)
+		}
+	}
+
+	return nil
+}
+
+// Language returns the language of the comments of the project, which is
+// the path of it.
+func (c *Catalogs) Language(project string) string {
+	return c.langs.language(project)
+}
+
+// Render renders the message of the name in the language of the project,
+// or in the default language if the catalog of the language lacks it.
+func (c *Catalogs) Render(project, name string, data interface{}) (string, error) {
+	return c.RenderIn(c.Language(project), name, data)
+}
+
+// RenderIn renders the message of the name in the language, or in the
+// default language if the catalog of the language lacks it.
+func (c *Catalogs) RenderIn(lang, name string, data interface{}) (string, error) {
+	if ts, ok := c.templates[lang]; ok && ts.Has(name) {
+		return ts.Render(name, data)
+	}
+
+	if ts, ok := c.templates[c.langs.Default]; ok && ts.Has(name) {
+		return ts.Render(name, data)
+	}
+
+	return "", fmt.Errorf("no message %s in language %s", name, lang)
+}
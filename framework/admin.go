@@ -0,0 +1,197 @@
+package framework
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/xanzy/go-gitlab"
+)
+
+// EndpointStatus describes what an endpoint listens to and how busy it is.
+type EndpointStatus struct {
+	Path   string        `json:"path"`
+	Robots []RobotStatus `json:"robots"`
+
+	// EventTypes are the values of the X-Gitlab-Event header handled by
+	// any of the robots.
+	EventTypes []string `json:"event_types"`
+
+	// Shard is the sharding of the events across the replicas, which
+	// filters the events handled by the replica.
+	Shard *ShardStatus `json:"shard,omitempty"`
+
+	Recording bool `json:"recording"`
+
+	// InFlight is the number of the events being handled, and Queued is
+	// the number of the ones of them waiting for the former events of
+	// the same projects when sharded.
+	InFlight int64 `json:"in_flight"`
+	Queued   int   `json:"queued"`
+
+	// LastEvents are the times of the last events of the projects.
+	LastEvents map[string]time.Time `json:"last_events"`
+}
+
+// RobotStatus describes the handlers and the tasks a robot registered.
+type RobotStatus struct {
+	Robot    string       `json:"robot"`
+	Handlers []string     `json:"handlers"`
+	Disabled []string     `json:"disabled,omitempty"`
+	Tasks    []TaskStatus `json:"tasks,omitempty"`
+}
+
+// TaskStatus describes a periodic task.
+type TaskStatus struct {
+	Name string    `json:"name"`
+	Next time.Time `json:"next"`
+}
+
+// ShardStatus describes the sharding of the events.
+type ShardStatus struct {
+	Self  string   `json:"self"`
+	Peers []string `json:"peers"`
+}
+
+// Status returns the status of the handler. The path is left empty.
+func (wh *Handler) Status() EndpointStatus {
+	d := wh.d
+
+	v := EndpointStatus{
+		Robots:    make([]RobotStatus, len(d.hs)),
+		Recording: wh.recorder != nil,
+		InFlight:  wh.inFlight.Load(),
+	}
+
+	types := map[string]bool{}
+	now := time.Now()
+
+	for i := range d.hs {
+		h := &d.hs[i]
+		r := RobotStatus{Robot: d.names[i], Handlers: []string{}, Disabled: h.toggles.list()}
+
+		for _, k := range handlerKinds {
+			if k.set(h) {
+				r.Handlers = append(r.Handlers, k.name)
+				types[string(k.eventType)] = true
+			}
+		}
+
+		for _, t := range h.tasks {
+			r.Tasks = append(r.Tasks, TaskStatus{Name: t.name, Next: t.schedule.Next(now)})
+		}
+
+		v.Robots[i] = r
+	}
+
+	if types[string(gitlab.EventTypeIssue)] {
+		types[string(gitlab.EventConfidentialIssue)] = true
+	}
+
+	if types[string(gitlab.EventTypeNote)] {
+		types[string(gitlab.EventConfidentialNote)] = true
+	}
+
+	v.EventTypes = slices.Sorted(maps.Keys(types))
+
+	if s := wh.shard; s != nil {
+		v.Shard = &ShardStatus{Self: s.self, Peers: s.peers}
+
+		s.mu.Lock()
+		for _, q := range s.queues {
+			v.Queued += len(q)
+		}
+		s.mu.Unlock()
+	}
+
+	d.mu.Lock()
+	v.LastEvents = maps.Clone(d.lastEvents)
+	d.mu.Unlock()
+
+	return v
+}
+
+// NewAdminHandler returns the admin API of the handlers of the webhook
+// paths. It serves:
+//
+//	GET  /admin/status                          the status of the endpoints
+//	POST /admin/handlers/{robot}/{kind}/enable  switch a handler on
+//	POST /admin/handlers/{robot}/{kind}/disable switch a handler off
+//
+// A handler is switched on all the endpoints having the robot. Wrap it by
+// RequireToken to authenticate the callers.
+func NewAdminHandler(handlers map[string]*Handler) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /admin/status", func(w http.ResponseWriter, r *http.Request) {
+		v := make([]EndpointStatus, 0, len(handlers))
+		for _, path := range slices.Sorted(maps.Keys(handlers)) {
+			s := handlers[path].Status()
+			s.Path = path
+			v = append(v, s)
+		}
+
+		writeJSON(w, v)
+	})
+
+	toggle := func(enabled bool) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			robot, kind := r.PathValue("robot"), r.PathValue("kind")
+
+			found := false
+
+			for _, wh := range handlers {
+				ok, err := wh.SetHandlerEnabled(robot, kind, enabled)
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+
+					return
+				}
+
+				found = found || ok
+			}
+
+			if !found {
+				http.Error(w, fmt.Sprintf("no robot %q", robot), http.StatusNotFound)
+
+				return
+			}
+
+			logrus.WithFields(logrus.Fields{
+				"robot":   robot,
+				"kind":    kind,
+				"enabled": enabled,
+			}).Info("switched the handler")
+
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}
+
+	mux.HandleFunc("POST /admin/handlers/{robot}/{kind}/enable", toggle(true))
+	mux.HandleFunc("POST /admin/handlers/{robot}/{kind}/disable", toggle(false))
+
+	return mux
+}
+
+// RequireToken returns the handler responding 401 to the requests without
+// the bearer token returned by token in the Authorization header.
+func RequireToken(token func() []byte, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		want := token()
+
+		v, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || len(want) == 0 || subtle.ConstantTimeCompare([]byte(v), want) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "401 Unauthorized", http.StatusUnauthorized)
+
+			return
+		}
+
+		h.ServeHTTP(w, r)
+	})
+}
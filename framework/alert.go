@@ -0,0 +1,217 @@
+package framework
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// AlertFormat is the format of the body of the alerts, which is the one of
+// the incoming webhooks of the chat service.
+type AlertFormat string
+
+const (
+	// AlertFormatJSON posts the fields of Alert.
+	AlertFormatJSON     AlertFormat = "json"
+	AlertFormatSlack    AlertFormat = "slack"
+	AlertFormatWeCom    AlertFormat = "wecom"
+	AlertFormatDingTalk AlertFormat = "dingtalk"
+)
+
+// AlertOptions configure alerting the owners of the robots when a handler
+// keeps failing.
+type AlertOptions struct {
+	// URL is the incoming webhook the alerts are posted to.
+	URL    string
+	Format AlertFormat
+
+	// Threshold is the number of the failures of a handler within Window
+	// which raises an alert. A handler is alerted once a Window at most.
+	Threshold int
+	Window    time.Duration
+
+	// Client defaults to the one with a timeout of 10 seconds.
+	Client *http.Client
+}
+
+// Alert is an alert of a handler failing Failures times within Window. The
+// rest is of the last failure.
+type Alert struct {
+	Robot     string        `json:"robot"`
+	Kind      string        `json:"kind"`
+	Handler   string        `json:"handler,omitempty"`
+	Failures  int           `json:"failures"`
+	Window    time.Duration `json:"window_ns"`
+	Project   string        `json:"project,omitempty"`
+	EventType string        `json:"event_type,omitempty"`
+	EventUUID string        `json:"event_uuid,omitempty"`
+	Error     string        `json:"error"`
+	Time      time.Time     `json:"time"`
+}
+
+func (a *Alert) text() string {
+	robot := a.Robot
+	if a.Handler != "" {
+		robot += " (" + a.Handler + ")"
+	}
+
+	s := fmt.Sprintf(
+		"Robot %s failed to handle %d %s events in %s.\nLast failure: %s",
+		robot, a.Failures, a.Kind, a.Window, a.Error,
+	)
+
+	if a.Project != "" {
+		s += "\nProject: " + a.Project
+	}
+
+	if a.EventUUID != "" {
+		s += "\nEvent: " + a.EventUUID
+	}
+
+	return s
+}
+
+func (a *Alert) body(format AlertFormat) ([]byte, error) {
+	type text struct {
+		Content string `json:"content"`
+	}
+
+	switch format {
+	case AlertFormatSlack:
+		return json.Marshal(map[string]string{"text": a.text()})
+
+	case AlertFormatWeCom, AlertFormatDingTalk:
+		return json.Marshal(map[string]interface{}{
+			"msgtype": "text",
+			"text":    text{Content: a.text()},
+		})
+	}
+
+	return json.Marshal(a)
+}
+
+// alerter counts the failures of the handlers and posts the alerts.
+type alerter struct {
+	opts AlertOptions
+
+	mu       sync.Mutex
+	failures map[string][]time.Time
+	alerted  map[string]time.Time
+}
+
+// AlertOnFailures posts an alert to the webhook of opts when a handler of
+// a robot fails the threshold times within the window, with the context of
+// the last failure. It must be called before the handler serves.
+func (wh *Handler) AlertOnFailures(opts AlertOptions) error {
+	if opts.URL == "" || opts.Threshold <= 0 || opts.Window <= 0 {
+		return errors.New("missing URL, threshold or window of the alerts")
+	}
+
+	switch opts.Format {
+	case "":
+		opts.Format = AlertFormatJSON
+
+	case AlertFormatJSON, AlertFormatSlack, AlertFormatWeCom, AlertFormatDingTalk:
+
+	default:
+		return fmt.Errorf("unknown alert format %q", opts.Format)
+	}
+
+	if opts.Client == nil {
+		opts.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	wh.d.alerter = &alerter{
+		opts:     opts,
+		failures: map[string][]time.Time{},
+		alerted:  map[string]time.Time{},
+	}
+
+	return nil
+}
+
+// failed counts the failure of the handler of the robot, and posts an
+// alert in the background if it reaches the threshold.
+func (al *alerter) failed(robot, kind, handler, project string, err error, log *logrus.Entry) {
+	now := time.Now()
+	key := robot + "/" + kind + "/" + handler
+
+	al.mu.Lock()
+
+	ts := al.failures[key]
+	for len(ts) > 0 && now.Sub(ts[0]) > al.opts.Window {
+		ts = ts[1:]
+	}
+
+	ts = append(ts, now)
+	al.failures[key] = ts
+
+	n := len(ts)
+	alert := n >= al.opts.Threshold && now.Sub(al.alerted[key]) > al.opts.Window
+	if alert {
+		al.alerted[key] = now
+	}
+
+	al.mu.Unlock()
+
+	if !alert {
+		return
+	}
+
+	a := &Alert{
+		Robot:    robot,
+		Kind:     kind,
+		Handler:  handler,
+		Failures: n,
+		Window:   al.opts.Window,
+		Project:  project,
+		Error:    err.Error(),
+		Time:     now,
+	}
+
+	a.EventType, _ = log.Data["event-type"].(string)
+	a.EventUUID, _ = log.Data["event-uuid"].(string)
+
+	go func() {
+		if err := al.post(a); err != nil {
+			log.WithError(err).Error("post the alert")
+		}
+	}()
+}
+
+func (al *alerter) post(a *Alert) error {
+	body, err := a.body(al.opts.Format)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), al.opts.Client.Timeout+time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, al.opts.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := al.opts.Client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("post the alert: %s", resp.Status)
+	}
+
+	return nil
+}
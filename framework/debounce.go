@@ -0,0 +1,179 @@
+package framework
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/xanzy/go-gitlab"
+)
+
+// pushDebouncer coalesces the push events of the same branch delivered
+// within a window into one, so that a storm of force pushes runs the
+// expensive handlers once.
+type pushDebouncer struct {
+	window time.Duration
+
+	mu      sync.Mutex
+	pending map[string]*pendingPush
+}
+
+// pendingPush is the push events of a branch waiting for the window to
+// close.
+type pendingPush struct {
+	events []*gitlab.PushEvent
+
+	// delivery and log are of the latest event.
+	delivery *Delivery
+	log      *logrus.Entry
+}
+
+// DebouncePush holds the push events for the window, and dispatches the
+// ones of the same branch delivered meanwhile as one. The merged event is
+// the latest one, except that it is before the first one and has the
+// commits of all of them. Its Delivery is the one received last. Wait
+// waits for the events being held, which delays the shutdown by the window
+// at most. It must be called before the handler serves.
+func (wh *Handler) DebouncePush(window time.Duration) {
+	wh.debounce = &pushDebouncer{
+		window:  window,
+		pending: map[string]*pendingPush{},
+	}
+}
+
+// hold holds the push event, and returns false if it can't be decoded,
+// which is then dispatched at once to report the error.
+func (wh *Handler) hold(ev *event, delivery *Delivery, log *logrus.Entry) bool {
+	v, err := ev.decode(decodeEvent[gitlab.PushEvent])
+	if err != nil {
+		return false
+	}
+
+	e := v.(*gitlab.PushEvent)
+
+	key := e.Project.PathWithNamespace + "\x00" + e.Ref
+
+	db := wh.debounce
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if p, ok := db.pending[key]; ok {
+		p.events = append(p.events, e)
+		p.delivery, p.log = delivery, log
+
+		return true
+	}
+
+	db.pending[key] = &pendingPush{events: []*gitlab.PushEvent{e}, delivery: delivery, log: log}
+
+	wh.wg.Add(1)
+	time.AfterFunc(db.window, func() {
+		if wh.shard != nil {
+			wh.shard.serialize(e.Project.PathWithNamespace, func() { wh.flush(key) })
+		} else {
+			wh.flush(key)
+		}
+	})
+
+	return true
+}
+
+// flush dispatches the push events held of the key.
+func (wh *Handler) flush(key string) {
+	defer wh.wg.Done()
+
+	db := wh.debounce
+
+	db.mu.Lock()
+	p := db.pending[key]
+	delete(db.pending, key)
+	db.mu.Unlock()
+
+	log := p.log.WithField("coalesced", len(p.events))
+
+	e := mergePushes(orderPushes(p.events))
+
+	payload, err := json.Marshal(e)
+	if err != nil {
+		log.WithError(err).Error("encode the coalesced push event")
+
+		return
+	}
+
+	ctx, err := wh.handlerContext(context.Background(), p.delivery, e.Project.PathWithNamespace)
+	if err != nil {
+		log.WithError(err).Error("route the coalesced push event")
+
+		return
+	}
+
+	if err := wh.d.Dispatch(ctx, string(gitlab.EventTypePush), payload, log); err != nil {
+		log.WithError(err).Error("handle the event")
+	}
+}
+
+// orderPushes orders the push events of a branch by chaining the before
+// of each one to the after of the previous one, since they may be handled
+// out of the order they were delivered. They are kept as is if they don't
+// make a chain.
+func orderPushes(events []*gitlab.PushEvent) []*gitlab.PushEvent {
+	byBefore := make(map[string]*gitlab.PushEvent, len(events))
+	afters := make(map[string]bool, len(events))
+
+	for _, e := range events {
+		byBefore[e.Before] = e
+		afters[e.After] = true
+	}
+
+	for _, e := range events {
+		if afters[e.Before] {
+			continue
+		}
+
+		var ordered []*gitlab.PushEvent
+		for v := e; v != nil && len(ordered) < len(events); v = byBefore[v.After] {
+			ordered = append(ordered, v)
+		}
+
+		if len(ordered) == len(events) {
+			return ordered
+		}
+	}
+
+	return events
+}
+
+// mergePushes merges the ordered push events into the last one, which is
+// before the first one and has the commits of all of them.
+func mergePushes(events []*gitlab.PushEvent) *gitlab.PushEvent {
+	last := events[len(events)-1]
+	if len(events) == 1 {
+		return last
+	}
+
+	seen := map[string]bool{}
+
+	// The commits are of an anonymous type, which is taken from the event.
+	commits := last.Commits[:0:0]
+	total := 0
+
+	for _, e := range events {
+		for _, c := range e.Commits {
+			if !seen[c.ID] {
+				seen[c.ID] = true
+				commits = append(commits, c)
+			}
+		}
+
+		total += e.TotalCommitsCount
+	}
+
+	last.Before = events[0].Before
+	last.Commits = commits
+	last.TotalCommitsCount = total
+
+	return last
+}
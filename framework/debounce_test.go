@@ -0,0 +1,141 @@
+package framework
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/xanzy/go-gitlab"
+)
+
+// pushEvent returns the push event from before to after with the commits.
+func pushEvent(t *testing.T, before, after string, commits ...string) *gitlab.PushEvent {
+	t.Helper()
+
+	v := map[string]interface{}{
+		"before":              before,
+		"after":               after,
+		"total_commits_count": len(commits),
+	}
+
+	var cs []map[string]string
+	for _, c := range commits {
+		cs = append(cs, map[string]string{"id": c})
+	}
+	v["commits"] = cs
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := new(gitlab.PushEvent)
+	if err := json.Unmarshal(b, e); err != nil {
+		t.Fatal(err)
+	}
+
+	return e
+}
+
+func afters(events []*gitlab.PushEvent) []string {
+	r := make([]string, len(events))
+	for i, e := range events {
+		r[i] = e.After
+	}
+
+	return r
+}
+
+func TestOrderPushes(t *testing.T) {
+	tests := []struct {
+		name   string
+		events [][2]string
+		want   []string
+	}{
+		{name: "one", events: [][2]string{{"a", "b"}}, want: []string{"b"}},
+		{name: "in order", events: [][2]string{{"a", "b"}, {"b", "c"}, {"c", "d"}}, want: []string{"b", "c", "d"}},
+		{name: "reversed", events: [][2]string{{"c", "d"}, {"b", "c"}, {"a", "b"}}, want: []string{"b", "c", "d"}},
+		{name: "shuffled", events: [][2]string{{"b", "c"}, {"c", "d"}, {"a", "b"}}, want: []string{"b", "c", "d"}},
+		// A force push breaks the chain, so the delivery order is kept.
+		{name: "not chained", events: [][2]string{{"x", "d"}, {"a", "b"}, {"b", "c"}}, want: []string{"d", "b", "c"}},
+		{name: "cycle", events: [][2]string{{"b", "a"}, {"a", "b"}}, want: []string{"a", "b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := make([]*gitlab.PushEvent, len(tt.events))
+			for i, v := range tt.events {
+				events[i] = pushEvent(t, v[0], v[1])
+			}
+
+			if got := afters(orderPushes(events)); !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergePushes(t *testing.T) {
+	tests := []struct {
+		name        string
+		events      []*gitlab.PushEvent
+		wantBefore  string
+		wantAfter   string
+		wantCommits []string
+		wantTotal   int
+	}{
+		{
+			name:        "one",
+			events:      []*gitlab.PushEvent{pushEvent(t, "a", "b", "b")},
+			wantBefore:  "a",
+			wantAfter:   "b",
+			wantCommits: []string{"b"},
+			wantTotal:   1,
+		},
+		{
+			name: "chained",
+			events: []*gitlab.PushEvent{
+				pushEvent(t, "a", "c", "b", "c"),
+				pushEvent(t, "c", "d", "d"),
+			},
+			wantBefore:  "a",
+			wantAfter:   "d",
+			wantCommits: []string{"b", "c", "d"},
+			wantTotal:   3,
+		},
+		{
+			name: "duplicate commits",
+			events: []*gitlab.PushEvent{
+				pushEvent(t, "a", "c", "b", "c"),
+				pushEvent(t, "c", "d", "c", "d"),
+			},
+			wantBefore:  "a",
+			wantAfter:   "d",
+			wantCommits: []string{"b", "c", "d"},
+			wantTotal:   4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := mergePushes(tt.events)
+
+			var commits []string
+			for _, c := range e.Commits {
+				commits = append(commits, c.ID)
+			}
+
+			if e.Before != tt.wantBefore || e.After != tt.wantAfter {
+				t.Errorf("got %s..%s, want %s..%s", e.Before, e.After, tt.wantBefore, tt.wantAfter)
+			}
+
+			if !slices.Equal(commits, tt.wantCommits) {
+				t.Errorf("got commits %v, want %v", commits, tt.wantCommits)
+			}
+
+			if e.TotalCommitsCount != tt.wantTotal {
+				t.Errorf("got %d commits in total, want %d", e.TotalCommitsCount, tt.wantTotal)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package framework
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// debugMux returns the handlers of the debug endpoints, which are the ones
+// of net/http/pprof under /debug/pprof/, the runtime statistics at
+// /debug/runtime and the metrics of the default registry at /metrics, and
+// the admin APIs under /events and /admin/ if admin is not nil.
+func debugMux(admin http.Handler) *http.ServeMux {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.HandleFunc("/debug/runtime", serveRuntimeStats)
+	mux.Handle("/metrics", promhttp.Handler())
+
+	if admin != nil {
+		mux.Handle("/events", admin)
+		mux.Handle("/events/", admin)
+		mux.Handle("/admin/", admin)
+	}
+
+	return mux
+}
+
+type runtimeStats struct {
+	Goroutines   int           `json:"goroutines"`
+	HeapAlloc    uint64        `json:"heap_alloc_bytes"`
+	HeapObjects  uint64        `json:"heap_objects"`
+	Sys          uint64        `json:"sys_bytes"`
+	NumGC        uint32        `json:"num_gc"`
+	PauseTotal   time.Duration `json:"gc_pause_total_ns"`
+	GOMAXPROCS   int           `json:"gomaxprocs"`
+	GoVersion    string        `json:"go_version"`
+	LastGCUnixMs int64         `json:"last_gc_unix_ms"`
+}
+
+func serveRuntimeStats(w http.ResponseWriter, _ *http.Request) {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+
+	w.Header().Set("Content-Type", "application/json")
+
+	_ = json.NewEncoder(w).Encode(runtimeStats{
+		Goroutines:   runtime.NumGoroutine(),
+		HeapAlloc:    m.HeapAlloc,
+		HeapObjects:  m.HeapObjects,
+		Sys:          m.Sys,
+		NumGC:        m.NumGC,
+		PauseTotal:   time.Duration(m.PauseTotalNs),
+		GOMAXPROCS:   runtime.GOMAXPROCS(0),
+		GoVersion:    runtime.Version(),
+		LastGCUnixMs: int64(m.LastGC / uint64(time.Millisecond)),
+	})
+}
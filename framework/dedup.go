@@ -0,0 +1,194 @@
+package framework
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+// headerIdempotencyKey is the key GitLab keeps for the retries of a
+// delivery, which is the UUID of the event on the older versions.
+const headerIdempotencyKey = "Idempotency-Key"
+
+// DedupStore remembers the deliveries accepted, so that the ones delivered
+// again, such as the retries of the deliveries GitLab timed out on, are
+// dropped. NewDedupStore returns the ones kept in memory, in a bbolt file
+// and in Redis, the last of which is shared by the replicas.
+type DedupStore interface {
+	// Seen records the key for the window, and returns whether it has
+	// been recorded within the window already. It must be atomic across
+	// the handlers sharing the store.
+	Seen(ctx context.Context, key string, window time.Duration) (bool, error)
+
+	Close() error
+}
+
+// NewDedupStore returns the store of the spec, which is one of
+//
+//   - memory, the store of the process.
+//   - bolt:/path/to/file, the store in the bbolt file, surviving restarts.
+//   - redis://[:password@]host:port[/db], or rediss:// for TLS, the store
+//     in Redis shared by the replicas.
+func NewDedupStore(spec string) (DedupStore, error) {
+	switch {
+	case spec == "memory":
+		return NewMemoryDedupStore(), nil
+
+	case strings.HasPrefix(spec, "bolt:"):
+		return NewBoltDedupStore(strings.TrimPrefix(spec, "bolt:"))
+
+	case strings.HasPrefix(spec, "redis://"), strings.HasPrefix(spec, "rediss://"):
+		return NewRedisDedupStore(spec)
+	}
+
+	return nil, fmt.Errorf("unknown dedup store %q", spec)
+}
+
+// deduper drops the deliveries accepted by a handler before.
+type deduper struct {
+	store  DedupStore
+	window time.Duration
+
+	duplicates prometheus.Counter
+	errors     prometheus.Counter
+}
+
+// Dedup drops the deliveries whose idempotency keys, or UUIDs, are seen in
+// store within the window, responding them as received. The deliveries
+// dropped, and the errors of store, which let the deliveries through, are
+// reported to reg if it is not nil:
+//
+//   - gitlab_webhook_duplicates_suppressed_total
+//   - gitlab_webhook_dedup_errors_total
+//
+// It must be called before the handler serves.
+func (wh *Handler) Dedup(store DedupStore, window time.Duration, reg prometheus.Registerer) error {
+	if window <= 0 {
+		return errors.New("invalid dedup window")
+	}
+
+	dd := &deduper{
+		store:  store,
+		window: window,
+
+		duplicates: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "gitlab_webhook_duplicates_suppressed_total",
+			Help: "Deliveries of the webhooks dropped as duplicates.",
+		}),
+
+		errors: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "gitlab_webhook_dedup_errors_total",
+			Help: "Errors of the dedup store, letting the deliveries through.",
+		}),
+	}
+
+	if reg != nil {
+		var err error
+		if dd.duplicates, err = register(reg, dd.duplicates); err != nil {
+			return err
+		}
+
+		if dd.errors, err = register(reg, dd.errors); err != nil {
+			return err
+		}
+	}
+
+	wh.dedup = dd
+
+	return nil
+}
+
+// duplicate returns whether the delivery has been accepted before. The
+// deliveries without a key, and the ones the store fails on, are taken as
+// new.
+func (dd *deduper) duplicate(r *http.Request, log *logrus.Entry) bool {
+	key := r.Header.Get(headerIdempotencyKey)
+	if key == "" {
+		key = r.Header.Get(headerEventUUID)
+	}
+
+	if key == "" {
+		return false
+	}
+
+	seen, err := dd.store.Seen(r.Context(), key, dd.window)
+	if err != nil {
+		dd.errors.Inc()
+		log.WithError(err).Warn("check the duplicate delivery")
+
+		return false
+	}
+
+	if seen {
+		dd.duplicates.Inc()
+	}
+
+	return seen
+}
+
+// register registers c, or returns the same collector registered before.
+func register[T prometheus.Collector](reg prometheus.Registerer, c T) (T, error) {
+	err := reg.Register(c)
+	if err == nil {
+		return c, nil
+	}
+
+	var are prometheus.AlreadyRegisteredError
+	if errors.As(err, &are) {
+		if v, ok := are.ExistingCollector.(T); ok {
+			return v, nil
+		}
+	}
+
+	return c, err
+}
+
+// memoryDedupStore is the DedupStore of the process. The expired keys are
+// swept once a minute at most.
+type memoryDedupStore struct {
+	mu      sync.Mutex
+	expires map[string]time.Time
+	swept   time.Time
+}
+
+// NewMemoryDedupStore returns the DedupStore kept in memory, which is lost
+// on restart and not shared by the replicas.
+func NewMemoryDedupStore() DedupStore {
+	return &memoryDedupStore{expires: map[string]time.Time{}}
+}
+
+func (s *memoryDedupStore) Seen(_ context.Context, key string, window time.Duration) (bool, error) {
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.swept) > time.Minute {
+		for k, t := range s.expires {
+			if now.After(t) {
+				delete(s.expires, k)
+			}
+		}
+
+		s.swept = now
+	}
+
+	if t, ok := s.expires[key]; ok && now.Before(t) {
+		return true, nil
+	}
+
+	s.expires[key] = now.Add(window)
+
+	return false, nil
+}
+
+func (s *memoryDedupStore) Close() error {
+	return nil
+}
@@ -0,0 +1,96 @@
+package framework
+
+import (
+	"context"
+	"encoding/binary"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var boltDeliveriesBucket = []byte("deliveries")
+
+// boltDedupStore is the DedupStore of a bbolt database, keeping the
+// expiry of each key in Unix nanoseconds.
+type boltDedupStore struct {
+	db    *bolt.DB
+	swept time.Time
+}
+
+// NewBoltDedupStore opens, or creates, the dedup store in the file at
+// path, which survives the restarts. The file is locked until the store
+// is closed, so it can't be the one of the event store.
+func NewBoltDedupStore(path string) (DedupStore, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(boltDeliveriesBucket)
+
+		return err
+	})
+	if err != nil {
+		db.Close()
+
+		return nil, err
+	}
+
+	return &boltDedupStore{db: db}, nil
+}
+
+func (s *boltDedupStore) Seen(_ context.Context, key string, window time.Duration) (bool, error) {
+	seen := false
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(boltDeliveriesBucket)
+		now := time.Now()
+
+		// The expired keys are swept once a minute at most, in the
+		// transaction serialized by bbolt.
+		if now.Sub(s.swept) > time.Minute {
+			if err := sweepDeliveries(b, now); err != nil {
+				return err
+			}
+
+			s.swept = now
+		}
+
+		if v := b.Get([]byte(key)); len(v) == 8 && now.UnixNano() < int64(binary.BigEndian.Uint64(v)) {
+			seen = true
+
+			return nil
+		}
+
+		return b.Put([]byte(key), binary.BigEndian.AppendUint64(nil, uint64(now.Add(window).UnixNano())))
+	})
+
+	return seen, err
+}
+
+func sweepDeliveries(b *bolt.Bucket, now time.Time) error {
+	c := b.Cursor()
+
+	for k, v := c.First(); k != nil; {
+		if len(v) == 8 && now.UnixNano() < int64(binary.BigEndian.Uint64(v)) {
+			k, v = c.Next()
+
+			continue
+		}
+
+		if err := c.Delete(); err != nil {
+			return err
+		}
+
+		// Next skips a key after Delete, so seek the deleted key, which
+		// lands on the one following it.
+		k, v = c.Seek(k)
+	}
+
+	return nil
+}
+
+func (s *boltDedupStore) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,226 @@
+package framework
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// redisTimeout bounds each command sent to Redis without a deadline of
+// the context.
+const redisTimeout = 5 * time.Second
+
+// redisDedupStore is the DedupStore of Redis, which sets each key by SET
+// with NX and PX. It speaks the protocol of Redis over a connection
+// serialized by a mutex, which is redialed after an error, rather than
+// depending on a client library, as the webhooks are delivered at a low
+// rate.
+type redisDedupStore struct {
+	addr     string
+	password string
+	db       int
+	tls      *tls.Config
+
+	mu   sync.Mutex
+	conn net.Conn
+	rd   *bufio.Reader
+}
+
+// NewRedisDedupStore returns the DedupStore of the Redis of the URL in the
+// form of redis://[:password@]host:port[/db], or rediss:// for TLS. The
+// keys are prefixed by gitlab-webhook: to share the database.
+func NewRedisDedupStore(rawURL string) (DedupStore, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &redisDedupStore{addr: u.Host}
+
+	if _, _, err := net.SplitHostPort(u.Host); err != nil {
+		s.addr = net.JoinHostPort(u.Host, "6379")
+	}
+
+	if p, ok := u.User.Password(); ok {
+		s.password = p
+	}
+
+	if db := strings.Trim(u.Path, "/"); db != "" {
+		if s.db, err = strconv.Atoi(db); err != nil {
+			return nil, fmt.Errorf("invalid redis db %q", db)
+		}
+	}
+
+	if u.Scheme == "rediss" {
+		s.tls = &tls.Config{ServerName: u.Hostname(), MinVersion: tls.VersionTLS12}
+	}
+
+	return s, nil
+}
+
+func (s *redisDedupStore) Seen(ctx context.Context, key string, window time.Duration) (bool, error) {
+	ms := strconv.FormatInt(window.Milliseconds(), 10)
+
+	v, err := s.do(ctx, "SET", "gitlab-webhook:"+key, "1", "NX", "PX", ms)
+	if err != nil {
+		return false, err
+	}
+
+	// SET with NX replies nil if the key exists.
+	return v == nil, nil
+}
+
+// do sends the command and returns its reply, which is nil, or the string
+// of a simple or bulk string or an integer.
+func (s *redisDedupStore) do(ctx context.Context, args ...string) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		if err := s.dial(ctx); err != nil {
+			return nil, fmt.Errorf("connect to redis: %w", err)
+		}
+	}
+
+	v, err := s.roundTrip(ctx, args)
+
+	var rerr redisError
+	if err != nil && !errors.As(err, &rerr) {
+		// The connection is in an unknown state.
+		s.conn.Close()
+		s.conn = nil
+	}
+
+	return v, err
+}
+
+func (s *redisDedupStore) dial(ctx context.Context) error {
+	var (
+		conn net.Conn
+		err  error
+	)
+
+	d := &net.Dialer{Timeout: redisTimeout}
+	if s.tls != nil {
+		conn, err = (&tls.Dialer{NetDialer: d, Config: s.tls}).DialContext(ctx, "tcp", s.addr)
+	} else {
+		conn, err = d.DialContext(ctx, "tcp", s.addr)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	s.conn, s.rd = conn, bufio.NewReader(conn)
+
+	if s.password != "" {
+		if _, err = s.roundTrip(ctx, []string{"AUTH", s.password}); err != nil {
+			err = fmt.Errorf("auth: %w", err)
+		}
+	}
+
+	if err == nil && s.db != 0 {
+		_, err = s.roundTrip(ctx, []string{"SELECT", strconv.Itoa(s.db)})
+	}
+
+	if err != nil {
+		conn.Close()
+		s.conn = nil
+	}
+
+	return err
+}
+
+func (s *redisDedupStore) roundTrip(ctx context.Context, args []string) (interface{}, error) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(redisTimeout)
+	}
+
+	if err := s.conn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, a := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(a), a)
+	}
+
+	if _, err := s.conn.Write([]byte(b.String())); err != nil {
+		return nil, err
+	}
+
+	return s.readReply()
+}
+
+// redisError is an error replied by Redis, after which the connection is
+// still usable.
+type redisError string
+
+func (e redisError) Error() string {
+	return "redis: " + string(e)
+}
+
+func (s *redisDedupStore) readReply() (interface{}, error) {
+	line, err := s.rd.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, errors.New("redis: empty reply")
+	}
+
+	switch line[0] {
+	case '+', ':':
+		return line[1:], nil
+
+	case '-':
+		return nil, redisError(line[1:])
+
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("redis: invalid reply %q", line)
+		}
+
+		if n < 0 {
+			return nil, nil
+		}
+
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(s.rd, buf); err != nil {
+			return nil, err
+		}
+
+		return string(buf[:n]), nil
+	}
+
+	return nil, fmt.Errorf("redis: unexpected reply %q", line)
+}
+
+func (s *redisDedupStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		return nil
+	}
+
+	err := s.conn.Close()
+	s.conn = nil
+
+	return err
+}
@@ -0,0 +1,64 @@
+package framework
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+const (
+	headerInstance    = "X-Gitlab-Instance"
+	headerWebhookUUID = "X-Gitlab-Webhook-UUID"
+)
+
+// Delivery is the metadata of the delivery of the event being handled,
+// by which the robots can correlate what they do with the delivery log of
+// the webhook, or drop the events they have handled.
+type Delivery struct {
+	// UUID is the UUID of the event, which is the same for the retries of
+	// the delivery.
+	UUID string
+
+	// EventType is the value of the X-Gitlab-Event header.
+	EventType string
+
+	// Instance is the URL of the GitLab instance sending the event.
+	Instance string
+
+	// WebhookUUID is the UUID of the webhook, sent by GitLab 16.11 and
+	// later.
+	WebhookUUID string
+
+	// Received is when the delivery was received.
+	Received time.Time
+
+	// Replay is true if the event is dispatched again by the admin API.
+	Replay bool
+}
+
+type deliveryKey struct{}
+
+// WithDelivery returns the context carrying the delivery, which is done
+// by the framework for the handlers, or by the tests of them.
+func WithDelivery(ctx context.Context, d *Delivery) context.Context {
+	return context.WithValue(ctx, deliveryKey{}, d)
+}
+
+// DeliveryFrom returns the delivery of the event the handler is handling.
+// It returns false for the events not delivered by the webhooks, such as
+// the ones polled from the events API.
+func DeliveryFrom(ctx context.Context) (*Delivery, bool) {
+	d, ok := ctx.Value(deliveryKey{}).(*Delivery)
+
+	return d, ok
+}
+
+func newDelivery(r *http.Request, eventType string) *Delivery {
+	return &Delivery{
+		UUID:        r.Header.Get(headerEventUUID),
+		EventType:   eventType,
+		Instance:    r.Header.Get(headerInstance),
+		WebhookUUID: r.Header.Get(headerWebhookUUID),
+		Received:    time.Now(),
+	}
+}
@@ -0,0 +1,475 @@
+package framework
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime/debug"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/xanzy/go-gitlab"
+
+	"github.com/opensourceways/robot-gitlab-lib/client"
+)
+
+// Dispatcher decodes the payloads of the webhooks by the event types and
+// passes the events to the handlers the robots registered.
+type Dispatcher struct {
+	hs []handlers
+
+	// names are the names of the robots. See NamedRobot.
+	names []string
+
+	mu sync.Mutex
+
+	// lastEvents are the times of the last events of the projects.
+	lastEvents map[string]time.Time
+
+	// alerter is not nil if the failures of the handlers are alerted.
+	alerter *alerter
+
+	// metrics is not nil if the runs of the handlers are reported.
+	metrics *handlerMetrics
+
+	// unknownFields is not nil if the unknown fields of the payloads are
+	// reported.
+	unknownFields *unknownFieldReporter
+}
+
+// NewDispatcher returns a dispatcher for the handlers the robots register.
+// Each robot registers to its own handlers, so that the robots sharing a
+// dispatcher do not replace the handlers of each other.
+func NewDispatcher(bots ...Robot) *Dispatcher {
+	d := &Dispatcher{
+		hs:         make([]handlers, len(bots)),
+		names:      make([]string, len(bots)),
+		lastEvents: map[string]time.Time{},
+	}
+
+	for i, bot := range bots {
+		bot.RegisterEventHandler(registrar{h: &d.hs[i]})
+		d.names[i] = robotName(bot)
+	}
+
+	return d
+}
+
+// Dispatch decodes the payload of the event type, which is the value of
+// the X-Gitlab-Event header, and runs the handlers registered for it by
+// all the robots. The payload is decoded once into the event shared by the
+// handlers, which must not modify it. Each handler runs even if the others
+// fail or panic. It returns the errors of the handlers, or the one of
+// decoding the payload. The events no handler is registered for are
+// ignored without being fully decoded.
+func (d *Dispatcher) Dispatch(ctx context.Context, eventType string, payload []byte, log *logrus.Entry) error {
+	ev, err := parseEvent(eventType, payload)
+	if err != nil {
+		return fmt.Errorf("decode the payload: %w", err)
+	}
+
+	return d.dispatch(ctx, ev, log)
+}
+
+// dispatch runs the handlers of the event.
+func (d *Dispatcher) dispatch(ctx context.Context, ev *event, log *logrus.Entry) error {
+	project := ev.Project.PathWithNamespace
+	if project != "" {
+		d.mu.Lock()
+		d.lastEvents[project] = time.Now()
+		d.mu.Unlock()
+	}
+
+	kinds := d.matchingKinds(ev)
+	if len(kinds) == 0 {
+		log.Debugf("ignoring the event of type %q no handler handles", ev.eventType)
+
+		return nil
+	}
+
+	var errs []error
+	for i := range d.hs {
+		h := &d.hs[i]
+
+		for _, k := range kinds {
+			if !h.toggles.enabled(k.name) {
+				continue
+			}
+
+			for _, inv := range k.handlers(h) {
+				e, err := ev.decode(k.decode)
+				if err != nil {
+					return fmt.Errorf("decode the payload: %w", err)
+				}
+
+				if err := d.run(ctx, i, k, inv, e, project, log); err != nil {
+					errs = append(errs, err)
+				}
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Check decodes the payload of the event type as its handlers would, and
+// returns the error if it is malformed, so that the delivery can be
+// rejected before it is handled. The payloads of the events no handler is
+// registered for are only checked to be JSON objects. The unknown fields
+// are logged if the handler reports them.
+func (d *Dispatcher) Check(eventType string, payload []byte) error {
+	_, err := d.decode(eventType, payload)
+
+	return err
+}
+
+// decode is Check returning the event, which keeps the decoded payload for
+// dispatch.
+func (d *Dispatcher) decode(eventType string, payload []byte) (*event, error) {
+	ev, err := parseEvent(eventType, payload)
+	if err != nil {
+		return nil, fmt.Errorf("decode the payload: %w", err)
+	}
+
+	kinds := d.handledKinds(ev)
+	if len(kinds) == 0 {
+		return ev, nil
+	}
+
+	// The kinds of an event decode the same event.
+	k := kinds[0]
+
+	e, err := ev.decode(k.decode)
+	if err != nil {
+		return nil, fmt.Errorf("decode the payload: %w", err)
+	}
+
+	if d.unknownFields != nil {
+		unknown, err := unknownFields(payload, reflect.TypeOf(e))
+		if err != nil {
+			return nil, fmt.Errorf("decode the payload: %w", err)
+		}
+
+		if len(unknown) > 0 {
+			d.unknownFields.report(k.name, unknown)
+		}
+	}
+
+	return ev, nil
+}
+
+// handledKinds returns the kinds of the event type and the noteable type
+// of the event which a robot registered a handler of, regardless of
+// whether they match the event.
+func (d *Dispatcher) handledKinds(ev *event) []*handlerKind {
+	var kinds []*handlerKind
+	for i := range handlerKinds {
+		k := &handlerKinds[i]
+		if k.eventType != ev.eventType || k.noteableType != ev.noteableType() {
+			continue
+		}
+
+		for j := range d.hs {
+			if k.set(&d.hs[j]) {
+				kinds = append(kinds, k)
+
+				break
+			}
+		}
+	}
+
+	return kinds
+}
+
+// matchingKinds returns the kinds of handledKinds which match the event.
+func (d *Dispatcher) matchingKinds(ev *event) []*handlerKind {
+	return slices.DeleteFunc(d.handledKinds(ev), func(k *handlerKind) bool {
+		return k.match != nil && !k.match(ev)
+	})
+}
+
+// run runs the handler of the robot, recovering it from panicking, and
+// reports the result.
+func (d *Dispatcher) run(
+	ctx context.Context, robot int, k *handlerKind, inv invocation,
+	e interface{}, project string, log *logrus.Entry,
+) (err error) {
+	if inv.name != "" {
+		log = log.WithField("handler", inv.name)
+	}
+
+	start := time.Now()
+	result := resultSuccess
+
+	defer func() {
+		if v := recover(); v != nil {
+			err = fmt.Errorf("panic: %v", v)
+			result = resultPanic
+
+			log.WithField("stack", string(debug.Stack())).Error("the handler panicked")
+		} else if err != nil {
+			result = resultError
+		}
+
+		if d.metrics != nil {
+			d.metrics.observe(d.names[robot], k.name, inv.name, result, time.Since(start))
+		}
+
+		if err == nil {
+			return
+		}
+
+		if inv.name != "" {
+			err = fmt.Errorf("%s: %w", inv.name, err)
+		}
+
+		if d.alerter != nil {
+			d.alerter.failed(d.names[robot], k.name, inv.name, project, err, log)
+		}
+	}()
+
+	return inv.run(ctx, e, log)
+}
+
+// handlerKind is a kind of the handlers, described by its event type, the
+// type of the noteable for the note events, and the events of the type it
+// matches if it is a subset of them.
+type handlerKind struct {
+	name         string
+	eventType    gitlab.EventType
+	noteableType client.NoteableType
+	match        func(ev *event) bool
+
+	// handlers returns the handlers of the kind registered.
+	handlers func(h *handlers) []invocation
+
+	// decode decodes the payload into the event the handlers of the kind
+	// take.
+	decode decodeFunc
+}
+
+// set returns whether a handler of the kind is registered.
+func (k *handlerKind) set(h *handlers) bool {
+	return len(k.handlers(h)) > 0
+}
+
+// invocation runs a handler on the event decoded by its kind.
+type invocation struct {
+	name string
+	run  func(ctx context.Context, e interface{}, log *logrus.Entry) error
+}
+
+func invocations[T any, F ~func(context.Context, *T, *logrus.Entry) error](hs []named[F]) []invocation {
+	v := make([]invocation, len(hs))
+	for i := range hs {
+		fn := hs[i].fn
+
+		v[i] = invocation{
+			name: hs[i].name,
+			run: func(ctx context.Context, e interface{}, log *logrus.Entry) error {
+				return handle(ctx, fn, e.(*T), log)
+			},
+		}
+	}
+
+	return v
+}
+
+var handlerKinds = []handlerKind{
+	{
+		kindMergeRequest, gitlab.EventTypeMergeRequest, "", nil,
+		func(h *handlers) []invocation { return invocations(h.mergeEventHandlers) },
+		decodeEvent[gitlab.MergeEvent],
+	},
+	{
+		kindMergeApproval, gitlab.EventTypeMergeRequest, "", isApproval,
+		func(h *handlers) []invocation { return invocations(h.mergeApprovalEventHandlers) },
+		decodeEvent[gitlab.MergeEvent],
+	},
+	mergeChangeKind(MergeChangeCommits, client.IsMRUpdatedWithNewCommits),
+	mergeChangeKind(MergeChangeTargetBranch, client.IsMRTargetBranchChanged),
+	mergeChangeKind(MergeChangeLabels, client.IsMRLabelsChanged),
+	mergeChangeKind(MergeChangeReady, client.IsMRMarkedReady),
+	{
+		kindIssue, gitlab.EventTypeIssue, "", nil,
+		func(h *handlers) []invocation { return invocations(h.issueEventHandlers) },
+		decodeEvent[gitlab.IssueEvent],
+	},
+	{
+		kindPush, gitlab.EventTypePush, "", nil,
+		func(h *handlers) []invocation { return invocations(h.pushEventHandlers) },
+		decodeEvent[gitlab.PushEvent],
+	},
+	{
+		kindTagPush, gitlab.EventTypeTagPush, "", nil,
+		func(h *handlers) []invocation { return invocations(h.tagPushEventHandlers) },
+		decodeEvent[gitlab.TagEvent],
+	},
+	{
+		kindPipeline, gitlab.EventTypePipeline, "", nil,
+		func(h *handlers) []invocation { return invocations(h.pipelineEventHandlers) },
+		decodeEvent[gitlab.PipelineEvent],
+	},
+	{
+		kindMergeNote, gitlab.EventTypeNote, client.NoteableMergeRequest, nil,
+		func(h *handlers) []invocation { return invocations(h.mergeCommentEventHandlers) },
+		decodeEvent[gitlab.MergeCommentEvent],
+	},
+	{
+		kindIssueNote, gitlab.EventTypeNote, client.NoteableIssue, nil,
+		func(h *handlers) []invocation { return invocations(h.issueCommentEventHandlers) },
+		decodeEvent[gitlab.IssueCommentEvent],
+	},
+	{
+		kindCommitNote, gitlab.EventTypeNote, client.NoteableCommit, nil,
+		func(h *handlers) []invocation { return invocations(h.commitCommentEventHandlers) },
+		decodeEvent[gitlab.CommitCommentEvent],
+	},
+	{
+		kindMember, gitlab.EventTypeMember, "", nil,
+		func(h *handlers) []invocation { return invocations(h.memberEventHandlers) },
+		decodeEvent[gitlab.MemberEvent],
+	},
+}
+
+// mergeChangeKind returns the kind of the handlers of the change, which
+// is told by is from the update events of the merge requests.
+func mergeChangeKind(change MergeChange, is func(*gitlab.MergeEvent) bool) handlerKind {
+	return handlerKind{
+		kindMergeChange + string(change), gitlab.EventTypeMergeRequest, "",
+		func(ev *event) bool {
+			if ev.ObjectAttributes.Action != client.MRActionUpdate {
+				return false
+			}
+
+			e, err := ev.decode(decodeEvent[gitlab.MergeEvent])
+
+			return err == nil && is(e.(*gitlab.MergeEvent))
+		},
+		func(h *handlers) []invocation { return invocations(h.mergeChangeHandlers[change]) },
+		decodeEvent[gitlab.MergeEvent],
+	}
+}
+
+func isApproval(ev *event) bool {
+	switch ev.ObjectAttributes.Action {
+	case client.MRActionApproval, client.MRActionUnapproval,
+		client.MRActionApproved, client.MRActionUnapproved:
+		return true
+	}
+
+	return false
+}
+
+// eventHeader is the part of a payload deciding how it is dispatched,
+// which is decoded without the rest of the payload.
+type eventHeader struct {
+	Project struct {
+		PathWithNamespace string `json:"path_with_namespace"`
+	} `json:"project"`
+
+	ObjectAttributes struct {
+		NoteableType client.NoteableType `json:"noteable_type"`
+		Action       string              `json:"action"`
+	} `json:"object_attributes"`
+}
+
+// event is a payload being dispatched. Its header is decoded first, and the
+// rest of it is decoded on demand once, so that all the kinds and the
+// handlers of the event share it.
+type event struct {
+	eventHeader
+
+	// eventType is the one of the kinds, which is the same for the
+	// confidential events as the others.
+	eventType gitlab.EventType
+	payload   []byte
+
+	decoded bool
+	value   interface{}
+	err     error
+}
+
+func parseEvent(eventType string, payload []byte) (*event, error) {
+	ev := &event{eventType: gitlab.EventType(eventType), payload: payload}
+	if err := json.Unmarshal(payload, &ev.eventHeader); err != nil {
+		return nil, err
+	}
+
+	switch ev.eventType {
+	case gitlab.EventConfidentialIssue:
+		ev.eventType = gitlab.EventTypeIssue
+
+	case gitlab.EventConfidentialNote:
+		ev.eventType = gitlab.EventTypeNote
+	}
+
+	return ev, nil
+}
+
+// noteableType returns the type of the noteable of the note events, or
+// empty for the other events.
+func (ev *event) noteableType() client.NoteableType {
+	if ev.eventType != gitlab.EventTypeNote {
+		return ""
+	}
+
+	return ev.ObjectAttributes.NoteableType
+}
+
+// decode decodes the payload by fn the first time it is called, and
+// returns the same result afterwards, since all the kinds of the event
+// decode the same event. A panic of decoding is returned as an error.
+func (ev *event) decode(fn decodeFunc) (v interface{}, err error) {
+	if ev.decoded {
+		return ev.value, ev.err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+
+		ev.decoded, ev.value, ev.err = true, v, err
+	}()
+
+	return fn(ev.payload)
+}
+
+// decodeFunc decodes the payload into an event.
+type decodeFunc func(payload []byte) (interface{}, error)
+
+// decodeEvent decodes the payload into the event of type T.
+func decodeEvent[T any](payload []byte) (interface{}, error) {
+	e := new(T)
+	if err := json.Unmarshal(payload, e); err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// handle runs the handler with the log carrying the fields of the event.
+func handle[T any](
+	ctx context.Context, fn func(context.Context, *T, *logrus.Entry) error,
+	e *T, log *logrus.Entry,
+) error {
+	if fn == nil {
+		return nil
+	}
+
+	if v, ok := client.AsEvent(e); ok {
+		log = log.WithFields(logrus.Fields{
+			"org":    v.GetOrg(),
+			"repo":   v.GetRepo(),
+			"author": v.GetAuthor(),
+			"action": v.GetAction(),
+		})
+	}
+
+	return fn(ctx, e, log)
+}
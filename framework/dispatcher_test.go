@@ -0,0 +1,192 @@
+package framework_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/xanzy/go-gitlab"
+
+	"github.com/opensourceways/robot-gitlab-lib/client"
+	"github.com/opensourceways/robot-gitlab-lib/framework"
+	"github.com/opensourceways/robot-gitlab-lib/framework/gitlabtest"
+)
+
+// robotFunc is the robot registering its handlers by the function.
+type robotFunc func(framework.HandlerRegister)
+
+func (f robotFunc) RegisterEventHandler(r framework.HandlerRegister) {
+	f(r)
+}
+
+func discardLog() *logrus.Entry {
+	l := logrus.New()
+	l.SetOutput(io.Discard)
+
+	return logrus.NewEntry(l)
+}
+
+func TestDispatchSharesEvent(t *testing.T) {
+	var events []*gitlab.MergeEvent
+
+	record := func(_ context.Context, e *gitlab.MergeEvent, _ *logrus.Entry) error {
+		events = append(events, e)
+
+		return nil
+	}
+
+	bot := robotFunc(func(r framework.HandlerRegister) {
+		r.RegisterMergeEventHandler(record)
+		r.RegisterMergeChangeHandler(framework.MergeChangeLabels, record)
+	})
+
+	payload, eventType := gitlabtest.NewMergeEvent().
+		WithAction(client.MRActionUpdate).
+		WithLabelChanges([]string{"bug"}, []string{"bug", "lgtm"}).
+		Payload()
+
+	d := framework.NewDispatcher(bot, bot)
+	if err := d.Dispatch(context.Background(), string(eventType), payload, discardLog()); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(events) != 4 {
+		t.Fatalf("got %d handler runs, want 4", len(events))
+	}
+
+	for _, e := range events[1:] {
+		if e != events[0] {
+			t.Error("the handlers got different events")
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	onMerge := robotFunc(func(r framework.HandlerRegister) {
+		r.RegisterMergeEventHandler(func(context.Context, *gitlab.MergeEvent, *logrus.Entry) error {
+			return nil
+		})
+	})
+
+	onLabels := robotFunc(func(r framework.HandlerRegister) {
+		r.RegisterMergeChangeHandler(framework.MergeChangeLabels, func(context.Context, *gitlab.MergeEvent, *logrus.Entry) error {
+			return nil
+		})
+	})
+
+	mr := string(gitlab.EventTypeMergeRequest)
+	malformed := `{"object_attributes":{"action":"update","iid":"one"}}`
+
+	tests := []struct {
+		name      string
+		bot       framework.Robot
+		eventType string
+		payload   string
+		wantErr   bool
+	}{
+		{name: "valid", bot: onMerge, eventType: mr, payload: `{"object_attributes":{"iid":1}}`},
+		{name: "not json", bot: onMerge, eventType: mr, payload: `{`, wantErr: true},
+		{name: "malformed", bot: onMerge, eventType: mr, payload: malformed, wantErr: true},
+		{name: "malformed for change handlers", bot: onLabels, eventType: mr, payload: malformed, wantErr: true},
+		{name: "malformed but not handled", bot: onMerge, eventType: "Push Hook", payload: `{"ref":1}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := framework.NewDispatcher(tt.bot).Check(tt.eventType, []byte(tt.payload))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("got error %v, want error %t", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func benchmarkRobots(n int) []framework.Robot {
+	noop := func(context.Context, *gitlab.MergeEvent, *logrus.Entry) error { return nil }
+
+	bots := make([]framework.Robot, n)
+	for i := range bots {
+		bots[i] = robotFunc(func(r framework.HandlerRegister) {
+			r.RegisterMergeEventHandler(noop)
+			r.RegisterMergeChangeHandler(framework.MergeChangeLabels, noop)
+		})
+	}
+
+	return bots
+}
+
+func BenchmarkDispatch(b *testing.B) {
+	payload, eventType := gitlabtest.NewMergeEvent().
+		WithAction(client.MRActionUpdate).
+		WithLabelChanges([]string{"bug"}, []string{"bug", "lgtm"}).
+		Payload()
+
+	d := framework.NewDispatcher(benchmarkRobots(4)...)
+	log := discardLog()
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(payload)))
+	b.ResetTimer()
+
+	for range b.N {
+		if err := d.Dispatch(ctx, string(eventType), payload, log); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDispatchUnhandled(b *testing.B) {
+	payload, eventType := gitlabtest.Fixture(gitlabtest.FixturePush)
+
+	d := framework.NewDispatcher(benchmarkRobots(4)...)
+	log := discardLog()
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(payload)))
+	b.ResetTimer()
+
+	for range b.N {
+		if err := d.Dispatch(ctx, string(eventType), payload, log); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkServeHTTP(b *testing.B) {
+	payload, eventType := gitlabtest.NewMergeEvent().
+		WithAction(client.MRActionUpdate).
+		WithLabelChanges([]string{"bug"}, []string{"bug", "lgtm"}).
+		Payload()
+
+	logrus.SetOutput(io.Discard)
+	defer logrus.SetOutput(os.Stderr)
+
+	secret := []byte("secret")
+	wh := framework.NewHandler(func() []byte { return secret }, benchmarkRobots(4)...)
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(payload)))
+	b.ResetTimer()
+
+	for range b.N {
+		r := httptest.NewRequest(http.MethodPost, "/gitlab-hook", bytes.NewReader(payload))
+		r.Header.Set("X-Gitlab-Event", string(eventType))
+		r.Header.Set("X-Gitlab-Token", string(secret))
+
+		w := httptest.NewRecorder()
+		wh.ServeHTTP(w, r)
+
+		if w.Code != http.StatusOK {
+			b.Fatalf("got status %d", w.Code)
+		}
+	}
+
+	wh.Wait()
+}
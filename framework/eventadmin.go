@@ -0,0 +1,213 @@
+package framework
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// eventAdmin is the admin API of the stored events.
+type eventAdmin struct {
+	store EventStore
+
+	// handlers are the handlers of the endpoints by the webhook paths.
+	handlers map[string]*Handler
+}
+
+// NewEventAdmin returns the admin API listing the events in store and
+// dispatching them again to the handlers of the webhook paths they were
+// delivered to. The APIs are:
+//
+//	GET  /events                list the events, without the payloads
+//	                            unless payload=true is given
+//	GET  /events/{id}           get the event
+//	POST /events/{id}/replay    dispatch the event again
+//	POST /events/replay         dispatch the events listed again
+//
+// The events are filtered by the query parameters since and until in
+// RFC 3339, endpoint, type, project and limit. At most 100 events are
+// listed unless limit is given. Replaying the events listed requires one
+// of the filters other than limit, or all=true to replay all the events
+// stored. The replays are handled synchronously and respond the errors of
+// the handlers.
+func NewEventAdmin(store EventStore, handlers map[string]*Handler) http.Handler {
+	a := &eventAdmin{store: store, handlers: handlers}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /events", a.list)
+	mux.HandleFunc("GET /events/{id}", a.get)
+	mux.HandleFunc("POST /events/{id}/replay", a.replayOne)
+	mux.HandleFunc("POST /events/replay", a.replayAll)
+
+	return mux
+}
+
+// defaultEventListLimit is the number of the events listed if the limit
+// is not given.
+const defaultEventListLimit = 100
+
+// ReplayResult is the result of replaying an event.
+type ReplayResult struct {
+	ID    uint64 `json:"id"`
+	Error string `json:"error,omitempty"`
+}
+
+func (a *eventAdmin) list(w http.ResponseWriter, r *http.Request) {
+	f, err := parseEventFilter(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	if f.Limit == 0 {
+		f.Limit = defaultEventListLimit
+	}
+
+	v, err := a.store.List(f)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	if r.URL.Query().Get("payload") != "true" {
+		for i := range v {
+			v[i].Payload = nil
+		}
+	}
+
+	writeJSON(w, v)
+}
+
+func (a *eventAdmin) get(w http.ResponseWriter, r *http.Request) {
+	e, ok := a.lookup(w, r)
+	if ok {
+		writeJSON(w, e)
+	}
+}
+
+func (a *eventAdmin) replayOne(w http.ResponseWriter, r *http.Request) {
+	e, ok := a.lookup(w, r)
+	if ok {
+		writeJSON(w, a.replay(r.Context(), &e))
+	}
+}
+
+func (a *eventAdmin) replayAll(w http.ResponseWriter, r *http.Request) {
+	f, err := parseEventFilter(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	if !f.filters() && r.URL.Query().Get("all") != "true" {
+		http.Error(w, "no filter of the events to replay, give all=true to replay all of them", http.StatusBadRequest)
+
+		return
+	}
+
+	v, err := a.store.List(f)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	results := make([]ReplayResult, len(v))
+	for i := range v {
+		results[i] = a.replay(r.Context(), &v[i])
+	}
+
+	writeJSON(w, results)
+}
+
+func (a *eventAdmin) lookup(w http.ResponseWriter, r *http.Request) (StoredEvent, bool) {
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+
+		return StoredEvent{}, false
+	}
+
+	e, err := a.store.Get(id)
+	if err != nil {
+		code := http.StatusInternalServerError
+		if errors.Is(err, ErrEventNotFound) {
+			code = http.StatusNotFound
+		}
+
+		http.Error(w, err.Error(), code)
+
+		return StoredEvent{}, false
+	}
+
+	return e, true
+}
+
+func (a *eventAdmin) replay(ctx context.Context, e *StoredEvent) ReplayResult {
+	r := ReplayResult{ID: e.ID}
+
+	h, ok := a.handlers[e.Endpoint]
+	if !ok {
+		r.Error = "no handler of endpoint " + e.Endpoint
+
+		return r
+	}
+
+	if err := h.Replay(ctx, e); err != nil {
+		r.Error = err.Error()
+	}
+
+	return r
+}
+
+func parseEventFilter(q url.Values) (EventFilter, error) {
+	f := EventFilter{
+		Endpoint:  q.Get("endpoint"),
+		EventType: q.Get("type"),
+		Project:   q.Get("project"),
+	}
+
+	var err error
+
+	if v := q.Get("since"); v != "" {
+		if f.Since, err = time.Parse(time.RFC3339, v); err != nil {
+			return f, err
+		}
+	}
+
+	if v := q.Get("until"); v != "" {
+		if f.Until, err = time.Parse(time.RFC3339, v); err != nil {
+			return f, err
+		}
+	}
+
+	if v := q.Get("limit"); v != "" {
+		if f.Limit, err = strconv.Atoi(v); err != nil {
+			return f, err
+		}
+	}
+
+	return f, nil
+}
+
+// filters reports whether f filters the events by other than the limit.
+func (f *EventFilter) filters() bool {
+	return !f.Since.IsZero() || !f.Until.IsZero() || f.Endpoint != "" || f.EventType != "" || f.Project != ""
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logrus.WithError(err).Error("write the response")
+	}
+}
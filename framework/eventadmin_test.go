@@ -0,0 +1,59 @@
+package framework
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// filterStore is the EventStore of the tests recording the filters listed.
+type filterStore struct {
+	EventStore
+
+	filters []EventFilter
+}
+
+func (s *filterStore) List(f EventFilter) ([]StoredEvent, error) {
+	s.filters = append(s.filters, f)
+
+	return nil, nil
+}
+
+func TestEventAdminFilters(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		query  string
+		code   int
+		limit  int
+	}{
+		{name: "list", method: http.MethodGet, code: http.StatusOK, limit: defaultEventListLimit},
+		{name: "list with limit", method: http.MethodGet, query: "limit=5", code: http.StatusOK, limit: 5},
+		{name: "replay without filter", method: http.MethodPost, query: "limit=5", code: http.StatusBadRequest},
+		{name: "replay all", method: http.MethodPost, query: "all=true", code: http.StatusOK},
+		{name: "replay of project", method: http.MethodPost, query: "project=a%2Fb", code: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &filterStore{}
+			admin := NewEventAdmin(store, nil)
+
+			path := "/events"
+			if tt.method == http.MethodPost {
+				path += "/replay"
+			}
+
+			w := httptest.NewRecorder()
+			admin.ServeHTTP(w, httptest.NewRequest(tt.method, path+"?"+tt.query, nil))
+
+			if w.Code != tt.code {
+				t.Fatalf("got %d, want %d", w.Code, tt.code)
+			}
+
+			if tt.code == http.StatusOK && (len(store.filters) != 1 || store.filters[0].Limit != tt.limit) {
+				t.Errorf("got the filters %+v, want the limit %d", store.filters, tt.limit)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package framework
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ErrEventNotFound is returned by EventStore.Get if the event is not
+// stored.
+var ErrEventNotFound = errors.New("event not found")
+
+// StoredEvent is a delivery of the webhooks accepted and persisted, which
+// can be dispatched again.
+type StoredEvent struct {
+	ID       uint64    `json:"id"`
+	Received time.Time `json:"received"`
+
+	// Endpoint is the webhook path the event was delivered to.
+	Endpoint string `json:"endpoint"`
+
+	EventType string          `json:"event_type"`
+	UUID      string          `json:"uuid,omitempty"`
+	Instance  string          `json:"instance,omitempty"`
+	Project   string          `json:"project,omitempty"`
+	Payload   json.RawMessage `json:"payload,omitempty"`
+}
+
+// EventFilter selects the stored events. The zero values match all.
+type EventFilter struct {
+	Since     time.Time
+	Until     time.Time
+	Endpoint  string
+	EventType string
+	Project   string
+
+	// Limit is the maximum number of the events returned, which are the
+	// oldest ones matching.
+	Limit int
+}
+
+func (f *EventFilter) match(e *StoredEvent) bool {
+	switch {
+	case !f.Since.IsZero() && e.Received.Before(f.Since):
+		return false
+	case !f.Until.IsZero() && !e.Received.Before(f.Until):
+		return false
+	case f.Endpoint != "" && e.Endpoint != f.Endpoint:
+		return false
+	case f.EventType != "" && e.EventType != f.EventType:
+		return false
+	case f.Project != "" && e.Project != f.Project:
+		return false
+	default:
+		return true
+	}
+}
+
+// EventStore persists the events. NewBoltEventStore returns the one
+// embedded in a file.
+type EventStore interface {
+	// Append stores the event and sets its ID, which increases with the
+	// order the events are appended. The payload of the event is reused
+	// after it returns, so it must be copied to be kept.
+	Append(e *StoredEvent) error
+
+	// List returns the events matching f in the order they are appended.
+	List(f EventFilter) ([]StoredEvent, error)
+
+	// Get returns the event of the ID, or ErrEventNotFound.
+	Get(id uint64) (StoredEvent, error)
+
+	// Prune deletes the events received before t and returns the number
+	// of them.
+	Prune(t time.Time) (int, error)
+
+	Close() error
+}
+
+// pruneInterval is the minimum interval of pruning the expired events.
+const pruneInterval = time.Hour
+
+// recorder persists the events accepted by a handler.
+type recorder struct {
+	store     EventStore
+	retention time.Duration
+
+	mu     sync.Mutex
+	pruned time.Time
+}
+
+// RecordTo persists the deliveries the handler accepts into store before
+// they are handled, so that they can be dispatched again by Replay. The
+// events older than retention are deleted if it is positive. It must be
+// called before the handler serves.
+func (wh *Handler) RecordTo(store EventStore, retention time.Duration) {
+	wh.recorder = &recorder{store: store, retention: retention}
+}
+
+// Replay dispatches the stored event again to the robots, and returns the
+// error of the handlers.
+func (wh *Handler) Replay(ctx context.Context, e *StoredEvent) error {
+	log := logrus.WithFields(logrus.Fields{
+		"event-type": e.EventType,
+		"event-uuid": e.UUID,
+		"replay-of":  e.ID,
+	})
+
+	ctx, err := wh.handlerContext(ctx, &Delivery{
+		UUID:      e.UUID,
+		EventType: e.EventType,
+		Instance:  e.Instance,
+		Received:  e.Received,
+		Replay:    true,
+	}, eventProject(e.Payload))
+	if err != nil {
+		return err
+	}
+
+	return wh.d.Dispatch(ctx, e.EventType, e.Payload, log)
+}
+
+func (rec *recorder) record(e *StoredEvent, log *logrus.Entry) {
+	if err := rec.store.Append(e); err != nil {
+		log.WithError(err).Error("store the event")
+	}
+
+	if rec.retention <= 0 {
+		return
+	}
+
+	rec.mu.Lock()
+	due := time.Since(rec.pruned) >= pruneInterval
+	if due {
+		rec.pruned = time.Now()
+	}
+	rec.mu.Unlock()
+
+	if !due {
+		return
+	}
+
+	n, err := rec.store.Prune(time.Now().Add(-rec.retention))
+	if err != nil {
+		log.WithError(err).Error("prune the stored events")
+	} else if n > 0 {
+		log.Infof("pruned %d stored events", n)
+	}
+}
@@ -0,0 +1,142 @@
+package framework
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var boltEventsBucket = []byte("events")
+
+// boltEventStore is the EventStore of a bbolt database, keyed by the IDs
+// in big endian so that the events are iterated in the order appended.
+type boltEventStore struct {
+	db *bolt.DB
+}
+
+// NewBoltEventStore opens, or creates, the event store in the file at
+// path. The file is locked until the store is closed.
+func NewBoltEventStore(path string) (EventStore, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(boltEventsBucket)
+
+		return err
+	})
+	if err != nil {
+		db.Close()
+
+		return nil, err
+	}
+
+	return &boltEventStore{db: db}, nil
+}
+
+func boltKey(id uint64) []byte {
+	return binary.BigEndian.AppendUint64(nil, id)
+}
+
+func (s *boltEventStore) Append(e *StoredEvent) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(boltEventsBucket)
+
+		id, err := b.NextSequence()
+		if err != nil {
+			return err
+		}
+
+		e.ID = id
+
+		v, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+
+		return b.Put(boltKey(id), v)
+	})
+}
+
+func (s *boltEventStore) List(f EventFilter) ([]StoredEvent, error) {
+	var r []StoredEvent
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(boltEventsBucket).Cursor()
+
+		for k, v := c.First(); k != nil; k, v = c.Next() {
+			var e StoredEvent
+			if err := json.Unmarshal(v, &e); err != nil {
+				return err
+			}
+
+			if !f.match(&e) {
+				continue
+			}
+
+			r = append(r, e)
+
+			if f.Limit > 0 && len(r) == f.Limit {
+				return nil
+			}
+		}
+
+		return nil
+	})
+
+	return r, err
+}
+
+func (s *boltEventStore) Get(id uint64) (StoredEvent, error) {
+	var e StoredEvent
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		v := tx.Bucket(boltEventsBucket).Get(boltKey(id))
+		if v == nil {
+			return ErrEventNotFound
+		}
+
+		return json.Unmarshal(v, &e)
+	})
+
+	return e, err
+}
+
+// Prune relies on the events being appended in the order received, so
+// that it stops at the first event received at or after t.
+func (s *boltEventStore) Prune(t time.Time) (int, error) {
+	n := 0
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		c := tx.Bucket(boltEventsBucket).Cursor()
+
+		for k, v := c.First(); k != nil; k, v = c.First() {
+			var e StoredEvent
+			if err := json.Unmarshal(v, &e); err != nil {
+				return err
+			}
+
+			if !e.Received.Before(t) {
+				return nil
+			}
+
+			if err := c.Delete(); err != nil {
+				return err
+			}
+
+			n++
+		}
+
+		return nil
+	})
+
+	return n, err
+}
+
+func (s *boltEventStore) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,369 @@
+// Package framework runs a robot as a webhook service of GitLab. A robot
+// registers the handlers of the events it is interested in, and the
+// framework validates the deliveries, decodes the payloads and runs the
+// handlers.
+package framework
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+
+	"github.com/opensourceways/robot-gitlab-lib/client"
+)
+
+// defaultWebhookPath is the default path the webhooks deliver the events
+// to.
+const defaultWebhookPath = "/gitlab-hook"
+
+// secretReloadInterval is how often the secret files are read again to
+// pick up the rotated secrets.
+const secretReloadInterval = time.Minute
+
+// Robot is a robot run by the framework.
+type Robot interface {
+	// RegisterEventHandler registers the handlers of the robot.
+	RegisterEventHandler(HandlerRegister)
+}
+
+// Endpoint is a webhook path and the robots handling the events delivered
+// to it, so that several logical robots can be hosted by one service.
+type Endpoint struct {
+	Path   string
+	Robots []Robot
+
+	// Sources are the event sources run for the robots in addition to the
+	// webhooks.
+	Sources []Source
+
+	// Webhooks, if not nil, are the webhooks ensured to deliver to the
+	// endpoint, by the leader only if the leader election is configured,
+	// on startup and again each time the secrets are rotated. See
+	// Handler.EnsureWebhooks.
+	Webhooks *WebhookRegistration
+
+	// Clients, if not nil, are the clients of the GitLab instances passed
+	// to the handlers. See Handler.RouteClients.
+	Clients *client.Router
+}
+
+// Run serves the webhooks for the robot at the webhook path of opts, on
+// the port and the Unix socket if configured, until SIGINT or SIGTERM is
+// received, and then waits for the events being handled up to the grace
+// period of opts. The webhook secret and the admin token are read again
+// every minute, so that they can be rotated without restarting. Set
+// WebhookSecret of opts to keep the webhook secret in a secret manager.
+func Run(bot Robot, opts ServiceOptions) error {
+	return RunEndpoints(opts, Endpoint{Path: opts.WebhookPath, Robots: []Robot{bot}})
+}
+
+// RunEndpoints is Run serving each of the endpoints for its robots. The
+// webhook path of opts is ignored.
+func RunEndpoints(opts ServiceOptions, endpoints ...Endpoint) error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	secret := opts.WebhookSecret
+	if secret == nil {
+		f, err := client.LoadTokenFile(opts.WebhookSecretFile)
+		if err != nil {
+			return fmt.Errorf("read the webhook secret: %w", err)
+		}
+
+		go f.Watch(ctx, secretReloadInterval)
+
+		secret = f.Token
+	}
+
+	projectSecrets, err := loadProjectSecrets(ctx, &opts)
+	if err != nil {
+		return err
+	}
+
+	var store EventStore
+	if opts.EventStore != "" {
+		if store, err = NewBoltEventStore(opts.EventStore); err != nil {
+			return fmt.Errorf("open the event store: %w", err)
+		}
+
+		defer store.Close()
+	}
+
+	var alerts *AlertOptions
+	if opts.AlertWebhookFile != "" {
+		f, err := client.LoadTokenFile(opts.AlertWebhookFile)
+		if err != nil {
+			return fmt.Errorf("read the alert webhook: %w", err)
+		}
+
+		alerts = &AlertOptions{
+			URL:       string(f.Token()),
+			Format:    AlertFormat(opts.AlertFormat),
+			Threshold: opts.AlertThreshold,
+			Window:    opts.AlertWindow,
+		}
+	}
+
+	var dedup DedupStore
+	if opts.DedupStore != "" {
+		if dedup, err = NewDedupStore(opts.DedupStore); err != nil {
+			return fmt.Errorf("open the dedup store: %w", err)
+		}
+
+		defer dedup.Close()
+	}
+
+	mux := http.NewServeMux()
+	whs := make([]*Handler, len(endpoints))
+	byPath := make(map[string]*Handler, len(endpoints))
+
+	for i := range endpoints {
+		ep := &endpoints[i]
+		if !strings.HasPrefix(ep.Path, "/") {
+			return fmt.Errorf("webhook path %q must start with /", ep.Path)
+		}
+
+		for j := range endpoints[:i] {
+			if endpoints[j].Path == ep.Path {
+				return fmt.Errorf("duplicate webhook path %q", ep.Path)
+			}
+		}
+
+		whs[i] = NewHandler(secret, ep.Robots...)
+
+		if len(projectSecrets) > 0 {
+			whs[i].SetProjectSecrets(projectSecrets)
+		}
+
+		if opts.MaxInFlight > 0 {
+			whs[i].Limit(opts.MaxInFlight, opts.RetryAfter)
+		}
+
+		if err := whs[i].ReportMetrics(prometheus.DefaultRegisterer); err != nil {
+			return err
+		}
+
+		if alerts != nil {
+			if err := whs[i].AlertOnFailures(*alerts); err != nil {
+				return err
+			}
+		}
+
+		if dedup != nil {
+			if err := whs[i].Dedup(dedup, opts.DedupWindow, prometheus.DefaultRegisterer); err != nil {
+				return err
+			}
+		}
+
+		if ep.Clients != nil {
+			whs[i].RouteClients(ep.Clients)
+		}
+
+		if opts.ReportUnknownFields {
+			whs[i].ReportUnknownFields()
+		}
+
+		if opts.PushDebounce > 0 {
+			whs[i].DebouncePush(opts.PushDebounce)
+		}
+
+		if len(opts.ShardPeers) > 0 {
+			if err := whs[i].Shard(ShardOptions{Self: opts.ShardSelf, Peers: opts.ShardPeers}); err != nil {
+				return err
+			}
+		}
+
+		if store != nil {
+			whs[i].RecordTo(store, opts.EventRetention)
+		}
+
+		byPath[ep.Path] = whs[i]
+		mux.Handle(ep.Path, whs[i])
+	}
+
+	if err := disableHandlers(opts.DisabledHandlers, whs); err != nil {
+		return err
+	}
+
+	if opts.DebugPort != 0 {
+		dl, err := net.Listen("tcp", fmt.Sprintf(":%d", opts.DebugPort))
+		if err != nil {
+			return err
+		}
+
+		admin, err := adminHandler(ctx, &opts, store, byPath)
+		if err != nil {
+			dl.Close()
+
+			return err
+		}
+
+		debug := &http.Server{Handler: debugMux(admin)}
+		defer debug.Close()
+
+		go func() {
+			if err := debug.Serve(dl); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				logrus.WithError(err).Error("serve the debug endpoints")
+			}
+		}()
+	}
+
+	var elector Elector
+	if opts.LeaseName != "" {
+		if elector, err = NewLeaseElector(LeaseOptions{
+			Name:      opts.LeaseName,
+			Namespace: opts.LeaseNamespace,
+		}); err != nil {
+			return err
+		}
+	}
+
+	ls, err := listen(&opts)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err := removeUnixSocket(opts.UnixSocket); err != nil {
+			logrus.WithError(err).Error("remove the unix socket")
+		}
+	}()
+
+	srv := &http.Server{Handler: mux}
+
+	errc := make(chan error, len(ls))
+	for _, l := range ls {
+		go func(l net.Listener) {
+			errc <- srv.Serve(l)
+		}(l)
+	}
+
+	var background sync.WaitGroup
+	background.Add(1)
+
+	go func() {
+		defer background.Done()
+
+		runLeading(ctx, elector, func(ctx context.Context) {
+			runBackground(ctx, endpoints, whs)
+		})
+	}()
+
+	select {
+	case err := <-errc:
+		srv.Close()
+
+		return err
+
+	case <-ctx.Done():
+	}
+
+	logrus.Info("shutting down")
+
+	shutdown, cancel := context.WithTimeout(context.Background(), opts.GracePeriod)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdown); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	done := make(chan struct{})
+	go func() {
+		background.Wait()
+
+		for _, wh := range whs {
+			wh.Wait()
+		}
+
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+
+	case <-shutdown.Done():
+		return errors.New("timed out waiting for the events being handled")
+	}
+}
+
+// runBackground runs the periodic tasks and the event sources of the
+// endpoints, and keeps their webhooks, until ctx is done.
+func runBackground(ctx context.Context, endpoints []Endpoint, whs []*Handler) {
+	var wg sync.WaitGroup
+
+	for i := range endpoints {
+		if reg := endpoints[i].Webhooks; reg != nil {
+			wg.Add(1)
+
+			go func(wh *Handler) {
+				defer wg.Done()
+
+				wh.keepWebhooks(ctx, *reg, secretReloadInterval)
+			}(whs[i])
+		}
+
+		wg.Add(1)
+
+		go func(d *Dispatcher) {
+			defer wg.Done()
+
+			d.runTasks(ctx)
+		}(whs[i].d)
+
+		for _, src := range endpoints[i].Sources {
+			wg.Add(1)
+
+			go func(src Source, d *Dispatcher) {
+				defer wg.Done()
+
+				if err := src.Run(ctx, d); err != nil {
+					logrus.WithError(err).Error("run the event source")
+				}
+			}(src, whs[i].d)
+		}
+	}
+
+	wg.Wait()
+}
+
+// adminHandler returns the admin APIs served on the debug port, which are
+// the ones of the stored events if they are persisted and the status of
+// the endpoints, or nil if the admin token is not configured. All of them
+// require the token, which is reloaded until ctx is done.
+func adminHandler(
+	ctx context.Context, opts *ServiceOptions, store EventStore, handlers map[string]*Handler,
+) (http.Handler, error) {
+	if opts.AdminTokenFile == "" {
+		return nil, nil
+	}
+
+	token, err := client.LoadTokenFile(opts.AdminTokenFile)
+	if err != nil {
+		return nil, fmt.Errorf("read the admin token: %w", err)
+	}
+
+	go token.Watch(ctx, secretReloadInterval)
+
+	mux := http.NewServeMux()
+
+	if store != nil {
+		events := NewEventAdmin(store, handlers)
+		mux.Handle("/events", events)
+		mux.Handle("/events/", events)
+	}
+
+	mux.Handle("/admin/", NewAdminHandler(handlers))
+
+	return RequireToken(token.Token, mux), nil
+}
@@ -0,0 +1,478 @@
+package gitlabtest
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/xanzy/go-gitlab"
+)
+
+// host is the GitLab instance of the fixtures.
+const host = "https://gitlab.example.com"
+
+func projectWebURL(path string) string {
+	return host + "/" + path
+}
+
+func namespaceOf(path string) string {
+	if i := strings.LastIndex(path, "/"); i >= 0 {
+		return path[:i]
+	}
+
+	return ""
+}
+
+func eventUser(username string) *gitlab.EventUser {
+	return &gitlab.EventUser{Username: username, Name: username}
+}
+
+func eventUsers(usernames []string) []*gitlab.EventUser {
+	r := make([]*gitlab.EventUser, len(usernames))
+	for i, v := range usernames {
+		r[i] = eventUser(v)
+	}
+
+	return r
+}
+
+func eventLabels(titles []string) []*gitlab.EventLabel {
+	r := make([]*gitlab.EventLabel, len(titles))
+	for i, v := range titles {
+		r[i] = &gitlab.EventLabel{Title: v}
+	}
+
+	return r
+}
+
+// MergeEventBuilder builds a merge request event from FixtureMergeRequest.
+type MergeEventBuilder struct {
+	e gitlab.MergeEvent
+}
+
+// NewMergeEvent starts building a merge request event.
+func NewMergeEvent() *MergeEventBuilder {
+	b := &MergeEventBuilder{}
+	decodeFixture(FixtureMergeRequest, &b.e)
+
+	return b
+}
+
+// WithAction sets the action, such as client.MRActionMerge.
+func (b *MergeEventBuilder) WithAction(action string) *MergeEventBuilder {
+	b.e.ObjectAttributes.Action = action
+
+	return b
+}
+
+// WithProject sets the full path of the project.
+func (b *MergeEventBuilder) WithProject(path string) *MergeEventBuilder {
+	p := &b.e.Project
+	p.PathWithNamespace = path
+	p.Namespace = namespaceOf(path)
+	p.Name = path[strings.LastIndex(path, "/")+1:]
+	p.WebURL = projectWebURL(path)
+
+	b.e.ObjectAttributes.URL = p.WebURL + "/-/merge_requests/" + strconv.Itoa(b.e.ObjectAttributes.IID)
+
+	return b
+}
+
+// WithIID sets the IID of the merge request.
+func (b *MergeEventBuilder) WithIID(iid int) *MergeEventBuilder {
+	b.e.ObjectAttributes.IID = iid
+	b.e.ObjectAttributes.URL = b.e.Project.WebURL + "/-/merge_requests/" + strconv.Itoa(iid)
+
+	return b
+}
+
+// WithUser sets the username of the user who triggers the event.
+func (b *MergeEventBuilder) WithUser(username string) *MergeEventBuilder {
+	b.e.User = eventUser(username)
+
+	return b
+}
+
+// WithTitle sets the title of the merge request.
+func (b *MergeEventBuilder) WithTitle(title string) *MergeEventBuilder {
+	b.e.ObjectAttributes.Title = title
+
+	return b
+}
+
+// WithDescription sets the description of the merge request.
+func (b *MergeEventBuilder) WithDescription(desc string) *MergeEventBuilder {
+	b.e.ObjectAttributes.Description = desc
+
+	return b
+}
+
+// WithState sets the state of the merge request, such as "merged".
+func (b *MergeEventBuilder) WithState(state string) *MergeEventBuilder {
+	b.e.ObjectAttributes.State = state
+
+	return b
+}
+
+// WithBranches sets the source and the target branches.
+func (b *MergeEventBuilder) WithBranches(source, target string) *MergeEventBuilder {
+	b.e.ObjectAttributes.SourceBranch = source
+	b.e.ObjectAttributes.TargetBranch = target
+
+	return b
+}
+
+// WithDraft sets whether the merge request is a draft.
+func (b *MergeEventBuilder) WithDraft(draft bool) *MergeEventBuilder {
+	b.e.ObjectAttributes.Draft = draft
+	b.e.ObjectAttributes.WorkInProgress = draft
+
+	return b
+}
+
+// WithOldRev sets the head commit before the push which triggers the
+// update event.
+func (b *MergeEventBuilder) WithOldRev(sha string) *MergeEventBuilder {
+	b.e.ObjectAttributes.OldRev = sha
+
+	return b
+}
+
+// WithLabels sets the titles of the labels of the merge request.
+func (b *MergeEventBuilder) WithLabels(titles ...string) *MergeEventBuilder {
+	b.e.ObjectAttributes.Labels = eventLabels(titles)
+	b.e.Labels = eventLabels(titles)
+
+	return b
+}
+
+// WithLabelChanges records that the labels changed from previous to the
+// current ones, which are also set as the labels of the merge request.
+func (b *MergeEventBuilder) WithLabelChanges(previous, current []string) *MergeEventBuilder {
+	b.e.Changes.Labels.Previous = eventLabels(previous)
+	b.e.Changes.Labels.Current = eventLabels(current)
+
+	return b.WithLabels(current...)
+}
+
+// WithAssignees sets the usernames of the assignees.
+func (b *MergeEventBuilder) WithAssignees(usernames ...string) *MergeEventBuilder {
+	b.e.Assignees = eventUsers(usernames)
+
+	return b
+}
+
+// WithAssigneeChanges records that the assignees changed from previous to
+// the current ones, which are also set as the assignees.
+func (b *MergeEventBuilder) WithAssigneeChanges(previous, current []string) *MergeEventBuilder {
+	b.e.Changes.Assignees.Previous = eventUsers(previous)
+	b.e.Changes.Assignees.Current = eventUsers(current)
+
+	return b.WithAssignees(current...)
+}
+
+// Event returns the event built.
+func (b *MergeEventBuilder) Event() *gitlab.MergeEvent {
+	e := b.e
+
+	return &e
+}
+
+// Payload returns the payload of the event built and its event type.
+func (b *MergeEventBuilder) Payload() ([]byte, gitlab.EventType) {
+	return marshal(&b.e), gitlab.EventTypeMergeRequest
+}
+
+// IssueEventBuilder builds an issue event from FixtureIssue.
+type IssueEventBuilder struct {
+	e gitlab.IssueEvent
+}
+
+// NewIssueEvent starts building an issue event.
+func NewIssueEvent() *IssueEventBuilder {
+	b := &IssueEventBuilder{}
+	decodeFixture(FixtureIssue, &b.e)
+
+	return b
+}
+
+// WithAction sets the action, such as client.IssueActionClose.
+func (b *IssueEventBuilder) WithAction(action string) *IssueEventBuilder {
+	b.e.ObjectAttributes.Action = action
+
+	return b
+}
+
+// WithProject sets the full path of the project.
+func (b *IssueEventBuilder) WithProject(path string) *IssueEventBuilder {
+	p := &b.e.Project
+	p.PathWithNamespace = path
+	p.Namespace = namespaceOf(path)
+	p.Name = path[strings.LastIndex(path, "/")+1:]
+	p.WebURL = projectWebURL(path)
+
+	b.e.ObjectAttributes.URL = p.WebURL + "/-/issues/" + strconv.Itoa(b.e.ObjectAttributes.IID)
+
+	return b
+}
+
+// WithIID sets the IID of the issue.
+func (b *IssueEventBuilder) WithIID(iid int) *IssueEventBuilder {
+	b.e.ObjectAttributes.IID = iid
+	b.e.ObjectAttributes.URL = b.e.Project.WebURL + "/-/issues/" + strconv.Itoa(iid)
+
+	return b
+}
+
+// WithUser sets the username of the user who triggers the event.
+func (b *IssueEventBuilder) WithUser(username string) *IssueEventBuilder {
+	b.e.User = eventUser(username)
+
+	return b
+}
+
+// WithTitle sets the title of the issue.
+func (b *IssueEventBuilder) WithTitle(title string) *IssueEventBuilder {
+	b.e.ObjectAttributes.Title = title
+
+	return b
+}
+
+// WithDescription sets the description of the issue.
+func (b *IssueEventBuilder) WithDescription(desc string) *IssueEventBuilder {
+	b.e.ObjectAttributes.Description = desc
+
+	return b
+}
+
+// WithState sets the state of the issue, such as "closed".
+func (b *IssueEventBuilder) WithState(state string) *IssueEventBuilder {
+	b.e.ObjectAttributes.State = state
+
+	return b
+}
+
+// WithLabels sets the titles of the labels of the issue.
+func (b *IssueEventBuilder) WithLabels(titles ...string) *IssueEventBuilder {
+	b.e.ObjectAttributes.Labels = eventLabels(titles)
+	b.e.Labels = eventLabels(titles)
+
+	return b
+}
+
+// WithLabelChanges records that the labels changed from previous to the
+// current ones, which are also set as the labels of the issue.
+func (b *IssueEventBuilder) WithLabelChanges(previous, current []string) *IssueEventBuilder {
+	b.e.Changes.Labels.Previous = eventLabels(previous)
+	b.e.Changes.Labels.Current = eventLabels(current)
+
+	return b.WithLabels(current...)
+}
+
+// WithAssignees sets the usernames of the assignees.
+func (b *IssueEventBuilder) WithAssignees(usernames ...string) *IssueEventBuilder {
+	v := make([]gitlab.EventUser, len(usernames))
+	for i, name := range usernames {
+		v[i] = *eventUser(name)
+	}
+
+	b.e.Assignees = &v
+
+	return b
+}
+
+// WithAssigneeChanges records that the assignees changed from previous to
+// the current ones, which are also set as the assignees.
+func (b *IssueEventBuilder) WithAssigneeChanges(previous, current []string) *IssueEventBuilder {
+	b.e.Changes.Assignees.Previous = eventUsers(previous)
+	b.e.Changes.Assignees.Current = eventUsers(current)
+
+	return b.WithAssignees(current...)
+}
+
+// Event returns the event built.
+func (b *IssueEventBuilder) Event() *gitlab.IssueEvent {
+	e := b.e
+
+	return &e
+}
+
+// Payload returns the payload of the event built and its event type.
+func (b *IssueEventBuilder) Payload() ([]byte, gitlab.EventType) {
+	return marshal(&b.e), gitlab.EventTypeIssue
+}
+
+// PushEventBuilder builds a push event from FixturePush.
+type PushEventBuilder struct {
+	e gitlab.PushEvent
+}
+
+// NewPushEvent starts building a push event.
+func NewPushEvent() *PushEventBuilder {
+	b := &PushEventBuilder{}
+	decodeFixture(FixturePush, &b.e)
+
+	return b
+}
+
+// WithProject sets the full path of the project.
+func (b *PushEventBuilder) WithProject(path string) *PushEventBuilder {
+	p := &b.e.Project
+	p.PathWithNamespace = path
+	p.Namespace = namespaceOf(path)
+	p.Name = path[strings.LastIndex(path, "/")+1:]
+	p.WebURL = projectWebURL(path)
+
+	return b
+}
+
+// WithUser sets the username of the user who pushes.
+func (b *PushEventBuilder) WithUser(username string) *PushEventBuilder {
+	b.e.UserUsername = username
+	b.e.UserName = username
+
+	return b
+}
+
+// WithBranch sets the branch pushed to.
+func (b *PushEventBuilder) WithBranch(branch string) *PushEventBuilder {
+	b.e.Ref = "refs/heads/" + branch
+
+	return b
+}
+
+// WithRevs sets the head commits before and after the push.
+func (b *PushEventBuilder) WithRevs(before, after string) *PushEventBuilder {
+	b.e.Before = before
+	b.e.After = after
+	b.e.CheckoutSHA = after
+
+	return b
+}
+
+// Event returns the event built.
+func (b *PushEventBuilder) Event() *gitlab.PushEvent {
+	e := b.e
+
+	return &e
+}
+
+// Payload returns the payload of the event built and its event type.
+func (b *PushEventBuilder) Payload() ([]byte, gitlab.EventType) {
+	return marshal(&b.e), gitlab.EventTypePush
+}
+
+// MergeCommentEventBuilder builds a note event of a merge request from
+// FixtureNoteMergeRequest.
+type MergeCommentEventBuilder struct {
+	e gitlab.MergeCommentEvent
+}
+
+// NewMergeCommentEvent starts building a note event of a merge request.
+func NewMergeCommentEvent() *MergeCommentEventBuilder {
+	b := &MergeCommentEventBuilder{}
+	decodeFixture(FixtureNoteMergeRequest, &b.e)
+
+	return b
+}
+
+// WithProject sets the full path of the project.
+func (b *MergeCommentEventBuilder) WithProject(path string) *MergeCommentEventBuilder {
+	p := &b.e.Project
+	p.PathWithNamespace = path
+	p.Namespace = namespaceOf(path)
+	p.Name = path[strings.LastIndex(path, "/")+1:]
+	p.WebURL = projectWebURL(path)
+
+	return b
+}
+
+// WithIID sets the IID of the merge request.
+func (b *MergeCommentEventBuilder) WithIID(iid int) *MergeCommentEventBuilder {
+	b.e.MergeRequest.IID = iid
+
+	return b
+}
+
+// WithUser sets the username of the user who comments.
+func (b *MergeCommentEventBuilder) WithUser(username string) *MergeCommentEventBuilder {
+	b.e.User = eventUser(username)
+
+	return b
+}
+
+// WithNote sets the content of the comment.
+func (b *MergeCommentEventBuilder) WithNote(note string) *MergeCommentEventBuilder {
+	b.e.ObjectAttributes.Note = note
+
+	return b
+}
+
+// Event returns the event built.
+func (b *MergeCommentEventBuilder) Event() *gitlab.MergeCommentEvent {
+	e := b.e
+
+	return &e
+}
+
+// Payload returns the payload of the event built and its event type.
+func (b *MergeCommentEventBuilder) Payload() ([]byte, gitlab.EventType) {
+	return marshal(&b.e), gitlab.EventTypeNote
+}
+
+// IssueCommentEventBuilder builds a note event of an issue from
+// FixtureNoteIssue.
+type IssueCommentEventBuilder struct {
+	e gitlab.IssueCommentEvent
+}
+
+// NewIssueCommentEvent starts building a note event of an issue.
+func NewIssueCommentEvent() *IssueCommentEventBuilder {
+	b := &IssueCommentEventBuilder{}
+	decodeFixture(FixtureNoteIssue, &b.e)
+
+	return b
+}
+
+// WithProject sets the full path of the project.
+func (b *IssueCommentEventBuilder) WithProject(path string) *IssueCommentEventBuilder {
+	p := &b.e.Project
+	p.PathWithNamespace = path
+	p.Namespace = namespaceOf(path)
+	p.Name = path[strings.LastIndex(path, "/")+1:]
+	p.WebURL = projectWebURL(path)
+
+	return b
+}
+
+// WithIID sets the IID of the issue.
+func (b *IssueCommentEventBuilder) WithIID(iid int) *IssueCommentEventBuilder {
+	b.e.Issue.IID = iid
+
+	return b
+}
+
+// WithUser sets the username of the user who comments.
+func (b *IssueCommentEventBuilder) WithUser(username string) *IssueCommentEventBuilder {
+	b.e.User = &gitlab.User{Username: username, Name: username}
+
+	return b
+}
+
+// WithNote sets the content of the comment.
+func (b *IssueCommentEventBuilder) WithNote(note string) *IssueCommentEventBuilder {
+	b.e.ObjectAttributes.Note = note
+
+	return b
+}
+
+// Event returns the event built.
+func (b *IssueCommentEventBuilder) Event() *gitlab.IssueCommentEvent {
+	e := b.e
+
+	return &e
+}
+
+// Payload returns the payload of the event built and its event type.
+func (b *IssueCommentEventBuilder) Payload() ([]byte, gitlab.EventType) {
+	return marshal(&b.e), gitlab.EventTypeNote
+}
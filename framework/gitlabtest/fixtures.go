@@ -0,0 +1,79 @@
+// Package gitlabtest helps to test robots with the payloads of the webhook
+// events, so that the tests don't have to carry their own copies of them.
+package gitlabtest
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+
+	"github.com/xanzy/go-gitlab"
+)
+
+//go:embed fixtures/*.json
+var fixtures embed.FS
+
+// The names of the fixtures, which are payloads of the events on the
+// project opensourceways/robot-test, triggered by the user alice, except
+// that FixtureMember is alice added to the group opensourceways.
+const (
+	FixtureMergeRequest     = "merge_request"
+	FixtureIssue            = "issue"
+	FixturePush             = "push"
+	FixtureTagPush          = "tag_push"
+	FixturePipeline         = "pipeline"
+	FixtureNoteMergeRequest = "note_merge_request"
+	FixtureNoteIssue        = "note_issue"
+	FixtureNoteCommit       = "note_commit"
+	FixtureNoteSnippet      = "note_snippet"
+	FixtureMember           = "member"
+)
+
+var fixtureEventTypes = map[string]gitlab.EventType{
+	FixtureMergeRequest:     gitlab.EventTypeMergeRequest,
+	FixtureIssue:            gitlab.EventTypeIssue,
+	FixturePush:             gitlab.EventTypePush,
+	FixtureTagPush:          gitlab.EventTypeTagPush,
+	FixturePipeline:         gitlab.EventTypePipeline,
+	FixtureNoteMergeRequest: gitlab.EventTypeNote,
+	FixtureNoteIssue:        gitlab.EventTypeNote,
+	FixtureNoteCommit:       gitlab.EventTypeNote,
+	FixtureNoteSnippet:      gitlab.EventTypeNote,
+	FixtureMember:           gitlab.EventTypeMember,
+}
+
+// Fixture returns the payload of the fixture and the event type of it,
+// which is the value of the X-Gitlab-Event header. It panics if there is
+// no such fixture.
+func Fixture(name string) ([]byte, gitlab.EventType) {
+	t, ok := fixtureEventTypes[name]
+	if !ok {
+		panic(fmt.Sprintf("no fixture %s", name))
+	}
+
+	payload, err := fixtures.ReadFile("fixtures/" + name + ".json")
+	if err != nil {
+		panic(err)
+	}
+
+	return payload, t
+}
+
+// decodeFixture decodes the payload of the fixture into v.
+func decodeFixture(name string, v interface{}) {
+	payload, _ := Fixture(name)
+
+	if err := json.Unmarshal(payload, v); err != nil {
+		panic(fmt.Sprintf("decode fixture %s: %v", name, err))
+	}
+}
+
+// marshal returns the payload of the event.
+func marshal(v interface{}) []byte {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+
+	return payload
+}
@@ -0,0 +1,162 @@
+package gitlabtest_test
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/xanzy/go-gitlab"
+
+	"github.com/opensourceways/robot-gitlab-lib/client"
+	"github.com/opensourceways/robot-gitlab-lib/framework"
+	"github.com/opensourceways/robot-gitlab-lib/framework/gitlabtest"
+)
+
+// recorder is the robot recording the kinds of the handlers run and the
+// users of the events.
+type recorder struct {
+	runs []string
+}
+
+func record[T any](r *recorder, kind string, user func(*T) string) func(context.Context, *T, *logrus.Entry) error {
+	return func(_ context.Context, e *T, _ *logrus.Entry) error {
+		r.runs = append(r.runs, kind+":"+user(e))
+
+		return nil
+	}
+}
+
+func (r *recorder) RegisterEventHandler(reg framework.HandlerRegister) {
+	reg.RegisterMergeEventHandler(record(r, "merge", func(e *gitlab.MergeEvent) string { return e.User.Username }))
+	reg.RegisterIssueEventHandler(record(r, "issue", func(e *gitlab.IssueEvent) string { return e.User.Username }))
+	reg.RegisterPushEventHandler(record(r, "push", func(e *gitlab.PushEvent) string { return e.UserUsername }))
+	reg.RegisterTagPushEventHandler(record(r, "tag", func(e *gitlab.TagEvent) string { return e.UserUsername }))
+	reg.RegisterPipelineEventHandler(record(r, "pipeline", func(e *gitlab.PipelineEvent) string { return e.User.Username }))
+	reg.RegisterMergeCommentEventHandler(record(r, "merge-note", func(e *gitlab.MergeCommentEvent) string { return e.User.Username }))
+	reg.RegisterIssueCommentEventHandler(record(r, "issue-note", func(e *gitlab.IssueCommentEvent) string { return e.User.Username }))
+	reg.RegisterCommitCommentEventHandler(record(r, "commit-note", func(e *gitlab.CommitCommentEvent) string { return e.User.Username }))
+	reg.RegisterMemberEventHandler(record(r, "member", func(e *gitlab.MemberEvent) string { return e.UserUsername }))
+}
+
+func TestFixtures(t *testing.T) {
+	tests := []struct {
+		fixture string
+		want    []string
+	}{
+		{gitlabtest.FixtureMergeRequest, []string{"merge:alice"}},
+		{gitlabtest.FixtureIssue, []string{"issue:alice"}},
+		{gitlabtest.FixturePush, []string{"push:alice"}},
+		{gitlabtest.FixtureTagPush, []string{"tag:alice"}},
+		{gitlabtest.FixturePipeline, []string{"pipeline:alice"}},
+		{gitlabtest.FixtureNoteMergeRequest, []string{"merge-note:alice"}},
+		{gitlabtest.FixtureNoteIssue, []string{"issue-note:alice"}},
+		{gitlabtest.FixtureNoteCommit, []string{"commit-note:alice"}},
+		{gitlabtest.FixtureNoteSnippet, nil},
+		{gitlabtest.FixtureMember, []string{"member:alice"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			bot := &recorder{}
+
+			payload, eventType := gitlabtest.Fixture(tt.fixture)
+			if err := gitlabtest.Dispatch(bot, string(eventType), payload); err != nil {
+				t.Fatal(err)
+			}
+
+			if !slices.Equal(bot.runs, tt.want) {
+				t.Errorf("got %v, want %v", bot.runs, tt.want)
+			}
+		})
+	}
+}
+
+func TestDispatchMalformed(t *testing.T) {
+	tests := []struct {
+		name      string
+		eventType gitlab.EventType
+		payload   string
+	}{
+		{name: "not json", eventType: gitlab.EventTypeMergeRequest, payload: "merge"},
+		{name: "not object", eventType: gitlab.EventTypeIssue, payload: "[]"},
+		{name: "wrong type", eventType: gitlab.EventTypePush, payload: `{"object_kind": "push", "commits": "abc"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bot := &recorder{}
+
+			if err := gitlabtest.Dispatch(bot, string(tt.eventType), []byte(tt.payload)); err == nil {
+				t.Error("got no error, want the error of the malformed payload")
+			}
+
+			if len(bot.runs) != 0 {
+				t.Errorf("got %v run", bot.runs)
+			}
+		})
+	}
+}
+
+// lgtmBot labels the issue lgtm and replies when it is commented /lgtm.
+type lgtmBot struct {
+	cli client.Interface
+}
+
+func (b *lgtmBot) RegisterEventHandler(r framework.HandlerRegister) {
+	r.RegisterIssueCommentEventHandler(b.handle)
+}
+
+func (b *lgtmBot) handle(_ context.Context, e *gitlab.IssueCommentEvent, _ *logrus.Entry) error {
+	if strings.TrimSpace(e.ObjectAttributes.Note) != "/lgtm" {
+		return nil
+	}
+
+	project, iid := e.Project.PathWithNamespace, e.Issue.IID
+
+	if err := b.cli.SetIssueLabels(project, iid, func(current []string) []string {
+		return append(current, "lgtm")
+	}); err != nil {
+		return err
+	}
+
+	_, err := b.cli.CreateIssueComment(project, iid, "Labeled lgtm for @"+e.User.Username)
+
+	return err
+}
+
+func TestServer(t *testing.T) {
+	srv := gitlabtest.NewServer()
+	defer srv.Close()
+
+	const project = "opensourceways/robot-test"
+
+	srv.AddProject(project)
+	srv.AddIssue(project, &gitlab.Issue{Title: "first"})
+	srv.AddIssue(project, &gitlab.Issue{Title: "second", Labels: gitlab.Labels{"bug"}})
+
+	cli, err := client.NewClient(func() []byte { return []byte("token") }, srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payload, eventType := gitlabtest.Fixture(gitlabtest.FixtureNoteIssue)
+	if err := gitlabtest.Dispatch(&lgtmBot{cli: cli}, string(eventType), payload); err != nil {
+		t.Fatal(err)
+	}
+
+	issue := srv.Issue(project, 2)
+	if want := []string{"bug", "lgtm"}; !slices.Equal(issue.Labels, want) {
+		t.Errorf("got labels %v, want %v", issue.Labels, want)
+	}
+
+	notes := srv.IssueNotes(project, 2)
+	if len(notes) != 1 || notes[0].Body != "Labeled lgtm for @alice" || notes[0].Author.Username != "robot" {
+		t.Errorf("got notes %+v", notes)
+	}
+
+	if notes := srv.IssueNotes(project, 1); len(notes) != 0 {
+		t.Errorf("got notes %+v on the other issue", notes)
+	}
+}
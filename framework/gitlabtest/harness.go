@@ -0,0 +1,31 @@
+package gitlabtest
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/opensourceways/robot-gitlab-lib/framework"
+)
+
+// Dispatch runs the payload of the event type, such as "Merge Request
+// Hook", through the same registration, decoding, routing and logging
+// as a delivery of the webhooks to the robot, and returns the error of
+// the handler. The payload is checked first the same as the webhook does,
+// so a malformed one returns the error the delivery would be rejected by
+// with 400, without running the handlers. Since the handler runs
+// synchronously, its effects can be asserted right after Dispatch returns.
+func Dispatch(bot framework.Robot, eventType string, payload []byte) error {
+	return DispatchContext(context.Background(), bot, eventType, payload)
+}
+
+// DispatchContext is Dispatch with the context passed to the handler.
+func DispatchContext(ctx context.Context, bot framework.Robot, eventType string, payload []byte) error {
+	d := framework.NewDispatcher(bot)
+
+	if err := d.Check(eventType, payload); err != nil {
+		return err
+	}
+
+	return d.Dispatch(ctx, eventType, payload, logrus.WithField("event-type", eventType))
+}
@@ -0,0 +1,557 @@
+package gitlabtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/xanzy/go-gitlab"
+)
+
+// Server is a fake GitLab API serving the projects, the merge requests, the
+// issues, the notes and the labels added to it, so that a robot can be
+// tested end to end by pointing its client to URL. It supports reading
+// them and the common changes, such as commenting, labeling and closing.
+// The other APIs respond 404.
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	user     gitlab.User
+	projects []*serverProject
+	nextID   int
+}
+
+type serverProject struct {
+	p      *gitlab.Project
+	mrs    []*gitlab.MergeRequest
+	issues []*gitlab.Issue
+	labels []*gitlab.Label
+
+	// notes are keyed by the kind and the IID of the noteable, such as
+	// "merge_requests/1".
+	notes map[string][]*gitlab.Note
+}
+
+// NewServer starts a fake GitLab API. The token is of the user "robot".
+// Close it when done.
+func NewServer() *Server {
+	s := &Server{
+		user:   gitlab.User{ID: 1, Username: "robot", Name: "robot"},
+		nextID: 100,
+	}
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /api/v4/user", s.getUser)
+	mux.HandleFunc("GET /api/v4/projects/{pid}", s.getProject)
+	mux.HandleFunc("GET /api/v4/projects/{pid}/labels", s.listLabels)
+	mux.HandleFunc("POST /api/v4/projects/{pid}/labels", s.createLabel)
+
+	for _, kind := range []string{"merge_requests", "issues"} {
+		prefix := "/api/v4/projects/{pid}/" + kind
+
+		mux.HandleFunc("GET "+prefix, s.listItems(kind))
+		mux.HandleFunc("GET "+prefix+"/{iid}", s.getItem(kind))
+		mux.HandleFunc("PUT "+prefix+"/{iid}", s.updateItem(kind))
+		mux.HandleFunc("GET "+prefix+"/{iid}/notes", s.listNotes(kind))
+		mux.HandleFunc("POST "+prefix+"/{iid}/notes", s.createNote(kind))
+	}
+
+	mux.HandleFunc("PUT /api/v4/projects/{pid}/merge_requests/{iid}/merge", s.mergeMR)
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, "404 Not Found")
+	})
+
+	s.Server = httptest.NewServer(mux)
+
+	return s
+}
+
+// SetUser sets the user owning the token.
+func (s *Server) SetUser(id int, username string) {
+	s.mu.Lock()
+	s.user = gitlab.User{ID: id, Username: username, Name: username}
+	s.mu.Unlock()
+}
+
+// AddProject adds the project at the full path and returns it.
+func (s *Server) AddProject(path string) *gitlab.Project {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := &gitlab.Project{
+		ID:                s.newID(),
+		Path:              path[strings.LastIndex(path, "/")+1:],
+		PathWithNamespace: path,
+		DefaultBranch:     "main",
+		WebURL:            s.URL + "/" + path,
+	}
+	p.Name = p.Path
+
+	s.projects = append(s.projects, &serverProject{p: p, notes: map[string][]*gitlab.Note{}})
+
+	return p
+}
+
+// AddMergeRequest adds mr to the project. The ID, the IID and the URL of
+// mr are set, and so is the state if empty.
+func (s *Server) AddMergeRequest(project string, mr *gitlab.MergeRequest) *gitlab.MergeRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := s.mustProject(project)
+
+	mr.ID = s.newID()
+	mr.IID = len(p.mrs) + 1
+	mr.ProjectID = p.p.ID
+	mr.WebURL = p.p.WebURL + "/-/merge_requests/" + strconv.Itoa(mr.IID)
+	if mr.State == "" {
+		mr.State = "opened"
+	}
+
+	p.mrs = append(p.mrs, mr)
+
+	return mr
+}
+
+// AddIssue adds issue to the project. The ID, the IID and the URL of issue
+// are set, and so is the state if empty.
+func (s *Server) AddIssue(project string, issue *gitlab.Issue) *gitlab.Issue {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := s.mustProject(project)
+
+	issue.ID = s.newID()
+	issue.IID = len(p.issues) + 1
+	issue.ProjectID = p.p.ID
+	issue.WebURL = p.p.WebURL + "/-/issues/" + strconv.Itoa(issue.IID)
+	if issue.State == "" {
+		issue.State = "opened"
+	}
+
+	p.issues = append(p.issues, issue)
+
+	return issue
+}
+
+// AddLabel adds the label to the project.
+func (s *Server) AddLabel(project, name, color string) *gitlab.Label {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l := &gitlab.Label{ID: s.newID(), Name: name, Color: color}
+
+	p := s.mustProject(project)
+	p.labels = append(p.labels, l)
+
+	return l
+}
+
+// MergeRequest returns a copy of the merge request, or nil if not found.
+func (s *Server) MergeRequest(project string, iid int) *gitlab.MergeRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if p := s.project(project); p != nil && iid > 0 && iid <= len(p.mrs) {
+		v := *p.mrs[iid-1]
+
+		return &v
+	}
+
+	return nil
+}
+
+// Issue returns a copy of the issue, or nil if not found.
+func (s *Server) Issue(project string, iid int) *gitlab.Issue {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if p := s.project(project); p != nil && iid > 0 && iid <= len(p.issues) {
+		v := *p.issues[iid-1]
+
+		return &v
+	}
+
+	return nil
+}
+
+// MergeRequestNotes returns the notes of the merge request in the order of
+// creation.
+func (s *Server) MergeRequestNotes(project string, iid int) []*gitlab.Note {
+	return s.notesOf(project, "merge_requests", iid)
+}
+
+// IssueNotes returns the notes of the issue in the order of creation.
+func (s *Server) IssueNotes(project string, iid int) []*gitlab.Note {
+	return s.notesOf(project, "issues", iid)
+}
+
+func (s *Server) notesOf(project, kind string, iid int) []*gitlab.Note {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := s.project(project)
+	if p == nil {
+		return nil
+	}
+
+	return slices.Clone(p.notes[kind+"/"+strconv.Itoa(iid)])
+}
+
+func (s *Server) newID() int {
+	s.nextID++
+
+	return s.nextID
+}
+
+// project returns the project with the ID or the full path.
+func (s *Server) project(pid string) *serverProject {
+	for _, p := range s.projects {
+		if p.p.PathWithNamespace == pid || strconv.Itoa(p.p.ID) == pid {
+			return p
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) mustProject(pid string) *serverProject {
+	p := s.project(pid)
+	if p == nil {
+		panic("no project " + pid)
+	}
+
+	return p
+}
+
+// item is a merge request or an issue, which share the fields the server
+// changes.
+type item struct {
+	state       *string
+	title       *string
+	description *string
+	labels      *gitlab.Labels
+	updatedAt   **time.Time
+	locked      *bool
+}
+
+func (p *serverProject) item(kind string, iid int) (interface{}, *item) {
+	switch kind {
+	case "merge_requests":
+		if iid > 0 && iid <= len(p.mrs) {
+			v := p.mrs[iid-1]
+
+			return v, &item{
+				&v.State, &v.Title, &v.Description, &v.Labels, &v.UpdatedAt, &v.DiscussionLocked,
+			}
+		}
+
+	case "issues":
+		if iid > 0 && iid <= len(p.issues) {
+			v := p.issues[iid-1]
+
+			return v, &item{
+				&v.State, &v.Title, &v.Description, &v.Labels, &v.UpdatedAt, &v.DiscussionLocked,
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+func (s *Server) getUser(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, &s.user)
+}
+
+func (s *Server) getProject(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if p := s.requestProject(w, r); p != nil {
+		writeJSON(w, http.StatusOK, p.p)
+	}
+}
+
+func (s *Server) listLabels(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if p := s.requestProject(w, r); p != nil {
+		writeJSON(w, http.StatusOK, p.labels)
+	}
+}
+
+func (s *Server) createLabel(w http.ResponseWriter, r *http.Request) {
+	var opts struct {
+		Name  string `json:"name"`
+		Color string `json:"color"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&opts); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := s.requestProject(w, r)
+	if p == nil {
+		return
+	}
+
+	for _, l := range p.labels {
+		if l.Name == opts.Name {
+			writeError(w, http.StatusConflict, "Label already exists")
+
+			return
+		}
+	}
+
+	l := &gitlab.Label{ID: s.newID(), Name: opts.Name, Color: opts.Color}
+	p.labels = append(p.labels, l)
+
+	writeJSON(w, http.StatusCreated, l)
+}
+
+func (s *Server) listItems(kind string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		p := s.requestProject(w, r)
+		if p == nil {
+			return
+		}
+
+		state := r.URL.Query().Get("state")
+
+		items := []interface{}{}
+		for i := 1; ; i++ {
+			v, it := p.item(kind, i)
+			if it == nil {
+				break
+			}
+
+			if state == "" || state == "all" || state == *it.state {
+				items = append(items, v)
+			}
+		}
+
+		writeJSON(w, http.StatusOK, items)
+	}
+}
+
+func (s *Server) getItem(kind string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if v, _ := s.requestItem(w, r, kind); v != nil {
+			writeJSON(w, http.StatusOK, v)
+		}
+	}
+}
+
+func (s *Server) updateItem(kind string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var opts struct {
+			Title            *string `json:"title"`
+			Description      *string `json:"description"`
+			StateEvent       *string `json:"state_event"`
+			Labels           *string `json:"labels"`
+			AddLabels        *string `json:"add_labels"`
+			RemoveLabels     *string `json:"remove_labels"`
+			DiscussionLocked *bool   `json:"discussion_locked"`
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&opts); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		v, it := s.requestItem(w, r, kind)
+		if v == nil {
+			return
+		}
+
+		if opts.Title != nil {
+			*it.title = *opts.Title
+		}
+
+		if opts.Description != nil {
+			*it.description = *opts.Description
+		}
+
+		if opts.DiscussionLocked != nil {
+			*it.locked = *opts.DiscussionLocked
+		}
+
+		if opts.StateEvent != nil {
+			switch *opts.StateEvent {
+			case "close":
+				*it.state = "closed"
+			case "reopen":
+				*it.state = "opened"
+			}
+		}
+
+		if opts.Labels != nil {
+			*it.labels = splitLabels(*opts.Labels)
+		}
+
+		if opts.AddLabels != nil {
+			for _, l := range splitLabels(*opts.AddLabels) {
+				if !slices.Contains(*it.labels, l) {
+					*it.labels = append(*it.labels, l)
+				}
+			}
+		}
+
+		if opts.RemoveLabels != nil {
+			remove := splitLabels(*opts.RemoveLabels)
+
+			*it.labels = slices.DeleteFunc(*it.labels, func(l string) bool {
+				return slices.Contains(remove, l)
+			})
+		}
+
+		now := time.Now()
+		*it.updatedAt = &now
+
+		writeJSON(w, http.StatusOK, v)
+	}
+}
+
+func (s *Server) mergeMR(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	v, it := s.requestItem(w, r, "merge_requests")
+	if v == nil {
+		return
+	}
+
+	if *it.state != "opened" {
+		writeError(w, http.StatusMethodNotAllowed, "405 Method Not Allowed")
+
+		return
+	}
+
+	*it.state = "merged"
+
+	writeJSON(w, http.StatusOK, v)
+}
+
+func (s *Server) listNotes(kind string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if v, _ := s.requestItem(w, r, kind); v != nil {
+			p := s.project(r.PathValue("pid"))
+
+			writeJSON(w, http.StatusOK, p.notes[kind+"/"+r.PathValue("iid")])
+		}
+	}
+}
+
+func (s *Server) createNote(kind string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var opts struct {
+			Body string `json:"body"`
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&opts); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if v, _ := s.requestItem(w, r, kind); v == nil {
+			return
+		}
+
+		now := time.Now()
+		n := &gitlab.Note{
+			ID:        s.newID(),
+			Body:      opts.Body,
+			CreatedAt: &now,
+		}
+		n.Author.ID = s.user.ID
+		n.Author.Username = s.user.Username
+		n.Author.Name = s.user.Name
+
+		p := s.project(r.PathValue("pid"))
+		key := kind + "/" + r.PathValue("iid")
+		p.notes[key] = append(p.notes[key], n)
+
+		writeJSON(w, http.StatusCreated, n)
+	}
+}
+
+func (s *Server) requestProject(w http.ResponseWriter, r *http.Request) *serverProject {
+	p := s.project(r.PathValue("pid"))
+	if p == nil {
+		writeError(w, http.StatusNotFound, "404 Project Not Found")
+	}
+
+	return p
+}
+
+func (s *Server) requestItem(w http.ResponseWriter, r *http.Request, kind string) (interface{}, *item) {
+	p := s.requestProject(w, r)
+	if p == nil {
+		return nil, nil
+	}
+
+	iid, _ := strconv.Atoi(r.PathValue("iid"))
+
+	v, it := p.item(kind, iid)
+	if v == nil {
+		writeError(w, http.StatusNotFound, "404 Not Found")
+	}
+
+	return v, it
+}
+
+func splitLabels(s string) gitlab.Labels {
+	r := gitlab.Labels{}
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			r = append(r, v)
+		}
+	}
+
+	return r
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"message": msg})
+}
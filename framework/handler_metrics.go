@@ -0,0 +1,75 @@
+package framework
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// The results of the runs of the handlers.
+const (
+	resultSuccess = "success"
+	resultError   = "error"
+	resultPanic   = "panic"
+)
+
+type handlerMetrics struct {
+	runs      *prometheus.CounterVec
+	duration  *prometheus.HistogramVec
+	malformed prometheus.Counter
+}
+
+// ReportMetrics makes the handler report the runs of the handlers of the
+// robots to reg:
+//
+//   - gitlab_robot_handler_runs_total, the runs by robot, kind, handler
+//     and result, which is one of success, error and panic.
+//   - gitlab_robot_handler_duration_seconds, the latency of the runs by
+//     robot, kind and handler.
+//   - gitlab_webhook_malformed_payloads_total, the deliveries rejected for
+//     the malformed payloads.
+//
+// The handler is the name of the handler given by HandlerRegister.Named.
+// Several handlers can report to the same reg. It must be called before
+// the handler serves.
+func (wh *Handler) ReportMetrics(reg prometheus.Registerer) error {
+	m := &handlerMetrics{
+		runs: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "gitlab_robot_handler_runs_total",
+			Help: "Runs of the handlers of the robots.",
+		}, []string{"robot", "kind", "handler", "result"}),
+
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "gitlab_robot_handler_duration_seconds",
+			Help:    "Latency of the runs of the handlers of the robots.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"robot", "kind", "handler"}),
+
+		malformed: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "gitlab_webhook_malformed_payloads_total",
+			Help: "Deliveries of the webhooks rejected for the malformed payloads.",
+		}),
+	}
+
+	var err error
+	if m.runs, err = register(reg, m.runs); err != nil {
+		return err
+	}
+
+	if m.duration, err = register(reg, m.duration); err != nil {
+		return err
+	}
+
+	if m.malformed, err = register(reg, m.malformed); err != nil {
+		return err
+	}
+
+	wh.d.metrics = m
+
+	return nil
+}
+
+func (m *handlerMetrics) observe(robot, kind, handler, result string, d time.Duration) {
+	m.runs.WithLabelValues(robot, kind, handler, result).Inc()
+	m.duration.WithLabelValues(robot, kind, handler).Observe(d.Seconds())
+}
@@ -0,0 +1,194 @@
+package framework
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+	"github.com/xanzy/go-gitlab"
+)
+
+// MergeEventHandler handles the merge request events.
+type MergeEventHandler func(ctx context.Context, e *gitlab.MergeEvent, log *logrus.Entry) error
+
+// MergeApprovalEventHandler handles the merge request events of approving,
+// which are of the actions client.MRActionApproval, MRActionUnapproval,
+// MRActionApproved and MRActionUnapproved. They are also passed to the
+// MergeEventHandler of the robot.
+type MergeApprovalEventHandler func(ctx context.Context, e *gitlab.MergeEvent, log *logrus.Entry) error
+
+// MergeChange is a change of a merge request told from its update events,
+// which the handlers registered by RegisterMergeChangeHandler react to.
+type MergeChange string
+
+const (
+	// MergeChangeCommits is pushing commits to the source branch, including
+	// by a force push or a rebase.
+	MergeChangeCommits MergeChange = "commits"
+
+	MergeChangeTargetBranch MergeChange = "target_branch"
+	MergeChangeLabels       MergeChange = "labels"
+
+	// MergeChangeReady is marking the draft merge request as ready.
+	MergeChangeReady MergeChange = "ready"
+)
+
+// IssueEventHandler handles the issue events, including the confidential
+// ones.
+type IssueEventHandler func(ctx context.Context, e *gitlab.IssueEvent, log *logrus.Entry) error
+
+// PushEventHandler handles the push events of branches.
+type PushEventHandler func(ctx context.Context, e *gitlab.PushEvent, log *logrus.Entry) error
+
+// TagPushEventHandler handles the push events of tags.
+type TagPushEventHandler func(ctx context.Context, e *gitlab.TagEvent, log *logrus.Entry) error
+
+// PipelineEventHandler handles the pipeline events.
+type PipelineEventHandler func(ctx context.Context, e *gitlab.PipelineEvent, log *logrus.Entry) error
+
+// MergeCommentEventHandler handles the note events of merge requests.
+type MergeCommentEventHandler func(ctx context.Context, e *gitlab.MergeCommentEvent, log *logrus.Entry) error
+
+// IssueCommentEventHandler handles the note events of issues.
+type IssueCommentEventHandler func(ctx context.Context, e *gitlab.IssueCommentEvent, log *logrus.Entry) error
+
+// CommitCommentEventHandler handles the note events of commits.
+type CommitCommentEventHandler func(ctx context.Context, e *gitlab.CommitCommentEvent, log *logrus.Entry) error
+
+// MemberEventHandler handles the member events of the groups, which are
+// delivered by the group webhooks only.
+type MemberEventHandler func(ctx context.Context, e *gitlab.MemberEvent, log *logrus.Entry) error
+
+// HandlerRegister is what a robot registers its handlers to. A robot can
+// register several handlers of the same kind of events under the names
+// given by Named, which run in the order registered, each isolated from
+// the errors and the panics of the others. Registering a handler of the
+// same kind under the same name again replaces the former one.
+type HandlerRegister interface {
+	RegisterMergeEventHandler(MergeEventHandler)
+	RegisterMergeApprovalEventHandler(MergeApprovalEventHandler)
+
+	// RegisterMergeChangeHandler registers the handler of the update
+	// events of the merge requests making the change, which is one of the
+	// MergeChange constants. They are also passed to the MergeEventHandler
+	// of the robot. The kind of the handler is merge_request_ followed by
+	// the change, such as merge_request_labels.
+	RegisterMergeChangeHandler(change MergeChange, fn MergeEventHandler)
+
+	RegisterIssueEventHandler(IssueEventHandler)
+	RegisterPushEventHandler(PushEventHandler)
+	RegisterTagPushEventHandler(TagPushEventHandler)
+	RegisterPipelineEventHandler(PipelineEventHandler)
+	RegisterMergeCommentEventHandler(MergeCommentEventHandler)
+	RegisterIssueCommentEventHandler(IssueCommentEventHandler)
+	RegisterCommitCommentEventHandler(CommitCommentEventHandler)
+	RegisterMemberEventHandler(MemberEventHandler)
+
+	// RegisterPeriodicTask registers the task run on the schedule, which
+	// is named for logging.
+	RegisterPeriodicTask(name string, s Schedule, fn PeriodicTask)
+
+	// Named returns the register of the handlers of the name, which labels
+	// their logs, errors and metrics. The handlers registered directly
+	// are of the empty name.
+	Named(name string) HandlerRegister
+}
+
+// named is a handler registered under a name.
+type named[F any] struct {
+	name string
+	fn   F
+}
+
+// addHandler adds the handler of the name to hs, replacing the one of the
+// same name.
+func addHandler[F any](hs []named[F], name string, fn F) []named[F] {
+	for i := range hs {
+		if hs[i].name == name {
+			hs[i].fn = fn
+
+			return hs
+		}
+	}
+
+	return append(hs, named[F]{name: name, fn: fn})
+}
+
+type handlers struct {
+	mergeEventHandlers         []named[MergeEventHandler]
+	mergeApprovalEventHandlers []named[MergeApprovalEventHandler]
+	mergeChangeHandlers        map[MergeChange][]named[MergeEventHandler]
+	issueEventHandlers         []named[IssueEventHandler]
+	pushEventHandlers          []named[PushEventHandler]
+	tagPushEventHandlers       []named[TagPushEventHandler]
+	pipelineEventHandlers      []named[PipelineEventHandler]
+	mergeCommentEventHandlers  []named[MergeCommentEventHandler]
+	issueCommentEventHandlers  []named[IssueCommentEventHandler]
+	commitCommentEventHandlers []named[CommitCommentEventHandler]
+	memberEventHandlers        []named[MemberEventHandler]
+
+	tasks []periodicTask
+
+	toggles toggles
+}
+
+// registrar registers the handlers of a name to the handlers of a robot.
+type registrar struct {
+	h    *handlers
+	name string
+}
+
+func (r registrar) Named(name string) HandlerRegister {
+	return registrar{h: r.h, name: name}
+}
+
+func (r registrar) RegisterMergeEventHandler(fn MergeEventHandler) {
+	r.h.mergeEventHandlers = addHandler(r.h.mergeEventHandlers, r.name, fn)
+}
+
+func (r registrar) RegisterMergeApprovalEventHandler(fn MergeApprovalEventHandler) {
+	r.h.mergeApprovalEventHandlers = addHandler(r.h.mergeApprovalEventHandlers, r.name, fn)
+}
+
+func (r registrar) RegisterMergeChangeHandler(change MergeChange, fn MergeEventHandler) {
+	if r.h.mergeChangeHandlers == nil {
+		r.h.mergeChangeHandlers = map[MergeChange][]named[MergeEventHandler]{}
+	}
+
+	r.h.mergeChangeHandlers[change] = addHandler(r.h.mergeChangeHandlers[change], r.name, fn)
+}
+
+func (r registrar) RegisterIssueEventHandler(fn IssueEventHandler) {
+	r.h.issueEventHandlers = addHandler(r.h.issueEventHandlers, r.name, fn)
+}
+
+func (r registrar) RegisterPushEventHandler(fn PushEventHandler) {
+	r.h.pushEventHandlers = addHandler(r.h.pushEventHandlers, r.name, fn)
+}
+
+func (r registrar) RegisterTagPushEventHandler(fn TagPushEventHandler) {
+	r.h.tagPushEventHandlers = addHandler(r.h.tagPushEventHandlers, r.name, fn)
+}
+
+func (r registrar) RegisterPipelineEventHandler(fn PipelineEventHandler) {
+	r.h.pipelineEventHandlers = addHandler(r.h.pipelineEventHandlers, r.name, fn)
+}
+
+func (r registrar) RegisterMergeCommentEventHandler(fn MergeCommentEventHandler) {
+	r.h.mergeCommentEventHandlers = addHandler(r.h.mergeCommentEventHandlers, r.name, fn)
+}
+
+func (r registrar) RegisterIssueCommentEventHandler(fn IssueCommentEventHandler) {
+	r.h.issueCommentEventHandlers = addHandler(r.h.issueCommentEventHandlers, r.name, fn)
+}
+
+func (r registrar) RegisterCommitCommentEventHandler(fn CommitCommentEventHandler) {
+	r.h.commitCommentEventHandlers = addHandler(r.h.commitCommentEventHandlers, r.name, fn)
+}
+
+func (r registrar) RegisterMemberEventHandler(fn MemberEventHandler) {
+	r.h.memberEventHandlers = addHandler(r.h.memberEventHandlers, r.name, fn)
+}
+
+func (r registrar) RegisterPeriodicTask(name string, s Schedule, fn PeriodicTask) {
+	r.h.tasks = append(r.h.tasks, periodicTask{name: name, schedule: s, fn: fn})
+}
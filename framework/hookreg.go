@@ -0,0 +1,180 @@
+package framework
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/xanzy/go-gitlab"
+
+	"github.com/opensourceways/robot-gitlab-lib/client"
+)
+
+// WebhookRegistration describes the webhooks the framework ensures to
+// exist for an endpoint, so that onboarding a project is a change of the
+// configuration only.
+type WebhookRegistration struct {
+	// Client manages the webhooks, and must be allowed to do so in the
+	// projects, which usually needs the Maintainer role.
+	Client client.Interface
+
+	// URL is the URL of the endpoint which GitLab delivers to.
+	URL string
+
+	// Projects and Groups are the full paths of the projects, and the
+	// groups of which all the projects including the ones of the
+	// subgroups, to have the webhooks.
+	Projects []string
+	Groups   []string
+
+	// InsecureSkipVerify disables verifying the TLS certificate of URL.
+	InsecureSkipVerify bool
+}
+
+// hookOptions returns the settings of the webhooks delivering the events
+// which the robots registered the handlers for.
+func (d *Dispatcher) hookOptions(url string, insecure bool) client.HookOptions {
+	v := client.HookOptions{
+		URL:                   url,
+		EnableSSLVerification: !insecure,
+	}
+
+	for i := range d.hs {
+		for j := range handlerKinds {
+			k := &handlerKinds[j]
+			if !k.set(&d.hs[i]) {
+				continue
+			}
+
+			switch k.eventType {
+			case gitlab.EventTypeMergeRequest:
+				v.MergeRequestsEvents = true
+
+			case gitlab.EventTypeIssue:
+				v.IssuesEvents = true
+
+			case gitlab.EventTypePush:
+				v.PushEvents = true
+
+			case gitlab.EventTypeTagPush:
+				v.TagPushEvents = true
+
+			case gitlab.EventTypePipeline:
+				v.PipelineEvents = true
+
+			case gitlab.EventTypeNote:
+				v.NoteEvents = true
+
+			case gitlab.EventTypeMember:
+				v.MemberEvents = true
+			}
+		}
+	}
+
+	v.ConfidentialIssuesEvents = v.IssuesEvents
+	v.ConfidentialNoteEvents = v.NoteEvents
+
+	return v
+}
+
+// EnsureWebhooks creates the webhooks of reg delivering the events the
+// robots handle, or updates the existing ones of the same URL with the
+// events and the secret token of the handler, or the one of the project
+// set by SetProjectSecrets. It goes on with the other
+// projects if it fails on one, and returns all the errors.
+func (wh *Handler) EnsureWebhooks(reg WebhookRegistration) error {
+	if reg.Client == nil || reg.URL == "" {
+		return errors.New("missing client or URL of the webhooks")
+	}
+
+	opts := wh.d.hookOptions(reg.URL, reg.InsecureSkipVerify)
+
+	projects, err := client.ExpandProjects(reg.Client, reg.Projects, reg.Groups)
+
+	errs := []error{err}
+	for _, p := range projects {
+		opts.Token = string(wh.secretOf(p)())
+
+		if err := ensureWebhook(reg.Client, p, &opts); err != nil {
+			errs = append(errs, fmt.Errorf("ensure webhook of %s: %w", p, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// keepWebhooks ensures the webhooks of reg, and ensures them again each
+// time the secrets change, which is checked every interval until ctx is
+// done, since the webhooks otherwise keep delivering with the old secrets.
+// The failures are logged and not retried until the secrets change again.
+func (wh *Handler) keepWebhooks(ctx context.Context, reg WebhookRegistration, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	var ensured []byte
+
+	for {
+		if v := wh.secretsDigest(); !slices.Equal(v, ensured) {
+			if err := wh.EnsureWebhooks(reg); err != nil {
+				logrus.WithError(err).Error("ensure the webhooks")
+			}
+
+			ensured = v
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-t.C:
+		}
+	}
+}
+
+// secretsDigest returns the digest of the secret of the handler and the
+// ones of the projects, which changes when any of them is rotated.
+func (wh *Handler) secretsDigest() []byte {
+	h := sha256.New()
+	h.Write(wh.secret())
+
+	for _, p := range slices.Sorted(maps.Keys(wh.projectSecrets)) {
+		fmt.Fprintf(h, "\x00%s\x00%s", p, wh.projectSecrets[p]())
+	}
+
+	return h.Sum(nil)
+}
+
+func ensureWebhook(cli client.Interface, project string, opts *client.HookOptions) error {
+	hooks, err := cli.ListProjectHooks(project)
+	if err != nil {
+		return err
+	}
+
+	log := logrus.WithField("project", project)
+
+	for _, h := range hooks {
+		if h.URL == opts.URL {
+			// The token can't be read back, so the webhook is always
+			// updated in case it has changed.
+			_, err := cli.EditProjectHook(project, h.ID, *opts)
+			if err == nil {
+				log.Debug("updated the webhook")
+			}
+
+			return err
+		}
+	}
+
+	if _, err := cli.AddProjectHook(project, *opts); err != nil {
+		return err
+	}
+
+	log.Info("added the webhook")
+
+	return nil
+}
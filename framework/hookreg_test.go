@@ -0,0 +1,60 @@
+package framework
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/xanzy/go-gitlab"
+
+	"github.com/opensourceways/robot-gitlab-lib/client"
+	"github.com/opensourceways/robot-gitlab-lib/client/fake"
+)
+
+func TestKeepWebhooks(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		secret = "s1"
+		tokens = make(chan string, 10)
+	)
+
+	cli := &fake.Client{
+		AddProjectHookFunc: func(_ interface{}, opts client.HookOptions) (*gitlab.ProjectHook, error) {
+			tokens <- opts.Token
+
+			return &gitlab.ProjectHook{}, nil
+		},
+	}
+
+	wh := NewHandler(func() []byte {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return []byte(secret)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	reg := WebhookRegistration{Client: cli, URL: "https://robot/webhook", Projects: []string{"a/b"}}
+	go wh.keepWebhooks(ctx, reg, 10*time.Millisecond)
+
+	if got := <-tokens; got != "s1" {
+		t.Fatalf("got token %q, want s1", got)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+
+	if len(tokens) != 0 {
+		t.Fatalf("ensured again %d times without rotating the secret", len(tokens))
+	}
+
+	mu.Lock()
+	secret = "s2"
+	mu.Unlock()
+
+	if got := <-tokens; got != "s2" {
+		t.Errorf("got token %q, want s2", got)
+	}
+}
@@ -0,0 +1,62 @@
+package framework
+
+import (
+	"context"
+
+	"github.com/opensourceways/robot-gitlab-lib/client"
+)
+
+// RouteClients makes the handler pass the client of the GitLab instance
+// sending each event to the handlers by the context, so that a robot
+// serving several instances does not look them up by itself. The instance
+// is the one of the X-Gitlab-Instance header, or the one serving the
+// project of the event for the older versions of GitLab not sending it.
+// The deliveries of the instances router has no client for are rejected.
+// It must be called before the handler serves.
+func (wh *Handler) RouteClients(router *client.Router) {
+	wh.router = router
+}
+
+type clientKey struct{}
+
+// WithClient returns the context carrying the client of the instance of
+// the event, which is done by the framework for the handlers if the
+// clients are routed, or by the tests of them.
+func WithClient(ctx context.Context, cli client.Interface) context.Context {
+	return context.WithValue(ctx, clientKey{}, cli)
+}
+
+// ClientFrom returns the client of the instance of the event the handler
+// is handling. It returns false if the clients are not routed.
+func ClientFrom(ctx context.Context) (client.Interface, bool) {
+	cli, ok := ctx.Value(clientKey{}).(client.Interface)
+
+	return cli, ok
+}
+
+// handlerContext returns the context of the handlers of the delivery of
+// the event of the project, carrying the delivery and the client of its
+// instance.
+func (wh *Handler) handlerContext(parent context.Context, d *Delivery, project string) (context.Context, error) {
+	ctx := WithDelivery(parent, d)
+	if wh.router == nil {
+		return ctx, nil
+	}
+
+	var (
+		cli *client.Client
+		err error
+	)
+
+	if d.Instance != "" {
+		cli, err = wh.router.ForURL(d.Instance)
+	} else {
+		cli, err = wh.router.ForProject(project)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return WithClient(ctx, cli), nil
+}
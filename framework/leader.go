@@ -0,0 +1,29 @@
+package framework
+
+import (
+	"context"
+)
+
+// Elector elects one of the replicas of a robot as the leader, which runs
+// the periodic tasks and the event sources so that they are not run by
+// every replica. NewLeaseElector returns the one of the Kubernetes leases,
+// and the others, such as the one of the Redis locks, can be plugged in by
+// implementing it.
+type Elector interface {
+	// Run campaigns until ctx is done, and calls lead each time the
+	// replica becomes the leader with a context canceled when it is no
+	// longer the leader. Run returns after lead returns.
+	Run(ctx context.Context, lead func(ctx context.Context))
+}
+
+// runLeading runs fn until ctx is done, only while the replica is the
+// leader if e is not nil.
+func runLeading(ctx context.Context, e Elector, fn func(ctx context.Context)) {
+	if e == nil {
+		fn(ctx)
+
+		return
+	}
+
+	e.Run(ctx, fn)
+}
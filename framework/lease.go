@@ -0,0 +1,422 @@
+package framework
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+
+	defaultLeaseDuration = 15 * time.Second
+	defaultRenewDeadline = 10 * time.Second
+	defaultRetryPeriod   = 2 * time.Second
+
+	// microTimeLayout is the layout of the MicroTime of Kubernetes.
+	microTimeLayout = "2006-01-02T15:04:05.000000Z07:00"
+)
+
+var errLeaseHeld = errors.New("lease is held by another replica")
+
+// LeaseOptions are the options of NewLeaseElector.
+type LeaseOptions struct {
+	// Name is the name of the Lease object.
+	Name string
+
+	// Namespace defaults to the one of the pod.
+	Namespace string
+
+	// Identity identifies the replica, and defaults to the hostname, which
+	// is the name of the pod.
+	Identity string
+
+	// LeaseDuration is how long the other replicas wait before taking the
+	// lease not renewed, and defaults to 15 seconds.
+	LeaseDuration time.Duration
+
+	// RenewDeadline is how long the leader keeps trying to renew the lease
+	// before giving up the leadership, and defaults to 10 seconds. It must
+	// be less than LeaseDuration.
+	RenewDeadline time.Duration
+
+	// RetryPeriod is the interval of trying to acquire or to renew the
+	// lease, and defaults to 2 seconds.
+	RetryPeriod time.Duration
+}
+
+func (o *LeaseOptions) setDefault() error {
+	if o.Name == "" {
+		return errors.New("missing name of the lease")
+	}
+
+	if o.Namespace == "" {
+		b, err := os.ReadFile(serviceAccountDir + "/namespace")
+		if err != nil {
+			return fmt.Errorf("read the namespace of the pod: %w", err)
+		}
+
+		o.Namespace = strings.TrimSpace(string(b))
+	}
+
+	if o.Identity == "" {
+		v, err := os.Hostname()
+		if err != nil {
+			return err
+		}
+
+		o.Identity = v
+	}
+
+	if o.LeaseDuration <= 0 {
+		o.LeaseDuration = defaultLeaseDuration
+	}
+
+	if o.RenewDeadline <= 0 {
+		o.RenewDeadline = defaultRenewDeadline
+	}
+
+	if o.RetryPeriod <= 0 {
+		o.RetryPeriod = defaultRetryPeriod
+	}
+
+	if o.RenewDeadline >= o.LeaseDuration {
+		return errors.New("renew deadline must be less than lease duration")
+	}
+
+	return nil
+}
+
+// leaseElector is the Elector holding a Lease of coordination.k8s.io/v1,
+// the same as the leader election of client-go does. It talks to the API
+// server in the cluster with the service account of the pod, which must
+// be allowed to get, create and update the lease.
+type leaseElector struct {
+	opts LeaseOptions
+	kube *kubeClient
+}
+
+// NewLeaseElector returns the Elector of the Kubernetes lease, which works
+// only in a pod of the cluster.
+func NewLeaseElector(opts LeaseOptions) (Elector, error) {
+	if err := opts.setDefault(); err != nil {
+		return nil, err
+	}
+
+	kube, err := newInClusterClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return &leaseElector{opts: opts, kube: kube}, nil
+}
+
+func (e *leaseElector) Run(ctx context.Context, lead func(ctx context.Context)) {
+	log := logrus.WithFields(logrus.Fields{
+		"lease":    e.opts.Namespace + "/" + e.opts.Name,
+		"identity": e.opts.Identity,
+	})
+
+	for {
+		if !e.acquire(ctx, log) {
+			return
+		}
+
+		log.Info("became the leader")
+
+		leading, cancel := context.WithCancel(ctx)
+		done := make(chan struct{})
+
+		go func() {
+			defer close(done)
+
+			lead(leading)
+		}()
+
+		e.renew(leading, log)
+		cancel()
+		<-done
+
+		if ctx.Err() != nil {
+			e.release(log)
+
+			return
+		}
+
+		log.Warn("lost the leadership")
+	}
+}
+
+// acquire tries to acquire the lease until it succeeds or ctx is done.
+func (e *leaseElector) acquire(ctx context.Context, log *logrus.Entry) bool {
+	t := time.NewTicker(e.opts.RetryPeriod)
+	defer t.Stop()
+
+	for {
+		err := e.tryAcquireOrRenew(ctx)
+		if err == nil {
+			return true
+		}
+
+		if !errors.Is(err, errLeaseHeld) {
+			log.WithError(err).Warn("acquire the lease")
+		}
+
+		select {
+		case <-ctx.Done():
+			return false
+
+		case <-t.C:
+		}
+	}
+}
+
+// renew renews the lease until ctx is done or it fails for RenewDeadline.
+func (e *leaseElector) renew(ctx context.Context, log *logrus.Entry) {
+	t := time.NewTicker(e.opts.RetryPeriod)
+	defer t.Stop()
+
+	renewed := time.Now()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-t.C:
+		}
+
+		err := e.tryAcquireOrRenew(ctx)
+		if err == nil {
+			renewed = time.Now()
+
+			continue
+		}
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		log.WithError(err).Warn("renew the lease")
+
+		if time.Since(renewed) >= e.opts.RenewDeadline {
+			return
+		}
+	}
+}
+
+// release gives up the lease, so that another replica can take it without
+// waiting for it to expire.
+func (e *leaseElector) release(log *logrus.Entry) {
+	ctx, cancel := context.WithTimeout(context.Background(), e.opts.RetryPeriod)
+	defer cancel()
+
+	l, err := e.getLease(ctx)
+	if err != nil || l == nil || l.Spec.HolderIdentity != e.opts.Identity {
+		return
+	}
+
+	l.Spec.HolderIdentity = ""
+	l.Spec.LeaseDurationSeconds = 1
+	l.Spec.RenewTime = time.Now().UTC().Format(microTimeLayout)
+
+	if err := e.kube.do(ctx, http.MethodPut, e.leaseURL(), l, nil); err != nil {
+		log.WithError(err).Warn("release the lease")
+	}
+}
+
+type lease struct {
+	APIVersion string                 `json:"apiVersion"`
+	Kind       string                 `json:"kind"`
+	Metadata   map[string]interface{} `json:"metadata"`
+	Spec       leaseSpec              `json:"spec"`
+}
+
+type leaseSpec struct {
+	HolderIdentity       string `json:"holderIdentity"`
+	LeaseDurationSeconds int    `json:"leaseDurationSeconds"`
+	AcquireTime          string `json:"acquireTime,omitempty"`
+	RenewTime            string `json:"renewTime,omitempty"`
+	LeaseTransitions     int    `json:"leaseTransitions"`
+}
+
+// expired reports whether the holder failed to renew the lease in time.
+func (s *leaseSpec) expired(now time.Time) bool {
+	if s.HolderIdentity == "" {
+		return true
+	}
+
+	t, err := time.Parse(microTimeLayout, s.RenewTime)
+	if err != nil {
+		return true
+	}
+
+	return now.After(t.Add(time.Duration(s.LeaseDurationSeconds) * time.Second))
+}
+
+// tryAcquireOrRenew takes the lease if it is free or expired, or renews
+// it if it is held by the replica. The update is rejected by the API
+// server if another replica changed the lease after it was read.
+func (e *leaseElector) tryAcquireOrRenew(ctx context.Context) error {
+	now := time.Now()
+	nowStr := now.UTC().Format(microTimeLayout)
+	duration := int(e.opts.LeaseDuration / time.Second)
+
+	l, err := e.getLease(ctx)
+	if err != nil {
+		return err
+	}
+
+	if l == nil {
+		l = &lease{
+			APIVersion: "coordination.k8s.io/v1",
+			Kind:       "Lease",
+			Metadata:   map[string]interface{}{"name": e.opts.Name, "namespace": e.opts.Namespace},
+			Spec: leaseSpec{
+				HolderIdentity:       e.opts.Identity,
+				LeaseDurationSeconds: duration,
+				AcquireTime:          nowStr,
+				RenewTime:            nowStr,
+			},
+		}
+
+		return e.kube.do(ctx, http.MethodPost, e.leasesURL(), l, nil)
+	}
+
+	if l.Spec.HolderIdentity != e.opts.Identity {
+		if !l.Spec.expired(now) {
+			return errLeaseHeld
+		}
+
+		l.Spec.HolderIdentity = e.opts.Identity
+		l.Spec.AcquireTime = nowStr
+		l.Spec.LeaseTransitions++
+	}
+
+	l.Spec.LeaseDurationSeconds = duration
+	l.Spec.RenewTime = nowStr
+
+	return e.kube.do(ctx, http.MethodPut, e.leaseURL(), l, nil)
+}
+
+// getLease returns the lease, or nil if it does not exist.
+func (e *leaseElector) getLease(ctx context.Context) (*lease, error) {
+	l := new(lease)
+
+	err := e.kube.do(ctx, http.MethodGet, e.leaseURL(), nil, l)
+	if errors.Is(err, errKubeNotFound) {
+		return nil, nil
+	}
+
+	return l, err
+}
+
+func (e *leaseElector) leasesURL() string {
+	return fmt.Sprintf("/apis/coordination.k8s.io/v1/namespaces/%s/leases", e.opts.Namespace)
+}
+
+func (e *leaseElector) leaseURL() string {
+	return e.leasesURL() + "/" + e.opts.Name
+}
+
+var errKubeNotFound = errors.New("not found")
+
+// kubeClient is the minimal client of the Kubernetes API server in the
+// cluster.
+type kubeClient struct {
+	host      string
+	tokenFile string
+	hc        *http.Client
+}
+
+func newInClusterClient() (*kubeClient, error) {
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if host == "" || port == "" {
+		return nil, errors.New("not running in a Kubernetes cluster")
+	}
+
+	ca, err := os.ReadFile(serviceAccountDir + "/ca.crt")
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, errors.New("invalid CA of the service account")
+	}
+
+	return &kubeClient{
+		host:      "https://" + net.JoinHostPort(host, port),
+		tokenFile: serviceAccountDir + "/token",
+		hc: &http.Client{
+			Timeout: 10 * time.Second,
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12},
+			},
+		},
+	}, nil
+}
+
+// do sends the request with the token of the service account, which is
+// read every time since it is rotated by the kubelet.
+func (c *kubeClient) do(ctx context.Context, method, path string, body, result interface{}) error {
+	var r io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+
+		r = bytes.NewReader(b)
+	}
+
+	token, err := os.ReadFile(c.tokenFile)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.host+path, r)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return errKubeNotFound
+	}
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+
+		return fmt.Errorf("%s %s: %s: %s", method, path, resp.Status, b)
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	return json.NewDecoder(resp.Body).Decode(result)
+}
@@ -0,0 +1,67 @@
+package framework
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+)
+
+// listen returns the listeners of the service, which are the TCP one on
+// the port and the one on the Unix socket if configured.
+func listen(opts *ServiceOptions) ([]net.Listener, error) {
+	l, err := net.Listen("tcp", fmt.Sprintf(":%d", opts.Port))
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.UnixSocket == "" {
+		return []net.Listener{l}, nil
+	}
+
+	ul, err := listenUnix(opts.UnixSocket)
+	if err != nil {
+		l.Close()
+
+		return nil, err
+	}
+
+	return []net.Listener{l, ul}, nil
+}
+
+// listenUnix listens on the Unix socket at path. The socket file left by
+// a former run which did not exit cleanly is removed, but the other kinds
+// of files are not.
+func listenUnix(path string) (net.Listener, error) {
+	fi, err := os.Lstat(path)
+	switch {
+	case err == nil:
+		if fi.Mode()&fs.ModeSocket == 0 {
+			return nil, fmt.Errorf("%s exists and is not a socket", path)
+		}
+
+		if err := os.Remove(path); err != nil {
+			return nil, err
+		}
+
+	case !errors.Is(err, fs.ErrNotExist):
+		return nil, err
+	}
+
+	return net.Listen("unix", path)
+}
+
+// removeUnixSocket removes the socket file, in case it is not removed on
+// closing the listener.
+func removeUnixSocket(path string) error {
+	if path == "" {
+		return nil
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
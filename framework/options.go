@@ -0,0 +1,203 @@
+package framework
+
+import (
+	"errors"
+	"flag"
+	"strings"
+	"time"
+)
+
+// ServiceOptions are the options of the webhook service of a robot.
+type ServiceOptions struct {
+	Port int
+
+	// UnixSocket is the path of the Unix socket to listen on in addition to
+	// the port, for the sidecars and the ingress proxies. It is disabled if
+	// empty.
+	UnixSocket string
+
+	// DebugPort is the port serving net/http/pprof and the runtime
+	// statistics, which is separated from the one of the webhooks so that
+	// it is not exposed with them. It is disabled if 0.
+	DebugPort int
+
+	// AdminTokenFile is the file holding the bearer token required by the
+	// admin APIs on the debug port, which enables the status of the
+	// endpoints and switching the handlers at /admin/, and the stored
+	// events at /events. The admin APIs are not served without it.
+	AdminTokenFile string
+
+	// LeaseName is the name of the Kubernetes lease electing the replica
+	// running the periodic tasks and the event sources. Every replica runs
+	// them if it is empty.
+	LeaseName string
+
+	// LeaseNamespace defaults to the namespace of the pod.
+	LeaseNamespace string
+
+	// ShardSelf and ShardPeers are the base URLs of the replica and all
+	// the replicas, which enable sharding the events by the projects
+	// across the replicas if ShardPeers is not empty. See ShardOptions.
+	ShardSelf  string
+	ShardPeers []string
+
+	// EventStore is the file persisting the events delivered, which can be
+	// listed and dispatched again by the admin API on the debug port, so
+	// AdminTokenFile is required with DebugPort. It is disabled if empty.
+	EventStore string
+
+	// EventRetention is how long the persisted events are kept.
+	EventRetention time.Duration
+
+	// MaxInFlight bounds the events being handled and waiting to be
+	// handled by each endpoint, beyond which the deliveries are responded
+	// 503 to be redelivered after RetryAfter. It is unbounded if 0.
+	MaxInFlight int
+	RetryAfter  time.Duration
+
+	// DedupStore is the store of the deliveries accepted, by which the
+	// duplicate ones within DedupWindow are dropped. See NewDedupStore for
+	// its form. It is disabled if empty.
+	DedupStore  string
+	DedupWindow time.Duration
+
+	// AlertWebhookFile is the file holding the URL of the incoming webhook,
+	// which usually embeds an access token, alerted in AlertFormat when a
+	// handler fails AlertThreshold times within AlertWindow. The alerts are
+	// disabled if it is empty. See AlertOptions.
+	AlertWebhookFile string
+	AlertFormat      string
+	AlertThreshold   int
+	AlertWindow      time.Duration
+
+	// ReportUnknownFields logs the fields of the payloads unknown to the
+	// events the handlers decode. See Handler.ReportUnknownFields.
+	ReportUnknownFields bool
+
+	// PushDebounce is the window within which the push events of the same
+	// branch are coalesced into one. It is disabled if 0.
+	PushDebounce time.Duration
+
+	// DisabledHandlers are the handlers, in the form of robot/kind such as
+	// mybot/issue_note, switched off on startup. They can be switched on
+	// and off at runtime by the admin API.
+	DisabledHandlers []string
+
+	// WebhookPath is the path the webhooks deliver the events to.
+	WebhookPath string
+
+	// GracePeriod is how long to wait for the events being handled on
+	// shutdown.
+	GracePeriod time.Duration
+
+	// WebhookSecretFile is the file holding the secret token the webhooks
+	// are configured with.
+	WebhookSecretFile string
+
+	// WebhookSecret, if not nil, returns the secret token instead of
+	// WebhookSecretFile, such as the Get method of a secrets.Secret kept in
+	// a secret manager. It is not bound to a flag.
+	WebhookSecret func() []byte
+
+	// ProjectSecretFiles maps the full paths of the projects and the groups
+	// to the files holding their own secret tokens, which override the
+	// webhook secret for them. See Handler.SetProjectSecrets.
+	ProjectSecretFiles map[string]string
+
+	// ProjectSecrets is ProjectSecretFiles of the secrets kept elsewhere,
+	// such as in a secret manager, overriding the files of the same paths.
+	// It is not bound to a flag.
+	ProjectSecrets map[string]func() []byte
+}
+
+// AddFlags binds the options to the flags of fs.
+func (o *ServiceOptions) AddFlags(fs *flag.FlagSet) {
+	fs.IntVar(&o.Port, "port", 8888, "Port to listen on.")
+	fs.StringVar(&o.UnixSocket, "unix-socket", "", "Path of the Unix socket to listen on in addition to the port.")
+	fs.IntVar(&o.DebugPort, "debug-port", 0, "Port serving pprof and runtime statistics, disabled if 0.")
+	fs.StringVar(&o.AdminTokenFile, "admin-token-file", "", "Path to the file containing the bearer token of the admin APIs.")
+	fs.StringVar(&o.LeaseName, "leader-election-lease", "", "Name of the lease electing the replica running the periodic tasks and the event sources.")
+	fs.StringVar(&o.LeaseNamespace, "leader-election-namespace", "", "Namespace of the lease, defaults to the one of the pod.")
+	fs.StringVar(&o.ShardSelf, "shard-self", "", "Base URL of the replica, one of the shard peers.")
+	fs.Func("shard-peers", "Comma separated base URLs of all the replicas to shard the events across.", func(s string) error {
+		o.ShardPeers = strings.Split(s, ",")
+
+		return nil
+	})
+	fs.StringVar(&o.EventStore, "event-store", "", "Path of the file persisting the events delivered, disabled if empty.")
+	fs.DurationVar(&o.EventRetention, "event-retention", 7*24*time.Hour, "How long the persisted events are kept.")
+	fs.IntVar(&o.MaxInFlight, "max-in-flight", 0, "Events being handled beyond which the deliveries are rejected, unbounded if 0.")
+	fs.DurationVar(&o.RetryAfter, "retry-after", 30*time.Second, "How long the rejected deliveries are asked to be retried after.")
+	fs.StringVar(&o.DedupStore, "dedup-store", "", "Store of the deliveries accepted to drop the duplicates, one of memory, bolt:path and redis://host:port.")
+	fs.DurationVar(&o.DedupWindow, "dedup-window", time.Hour, "How long the deliveries accepted are remembered to drop the duplicates.")
+	fs.StringVar(&o.AlertWebhookFile, "alert-webhook-file", "", "Path to the file containing the URL of the webhook alerted of the failing handlers.")
+	fs.StringVar(&o.AlertFormat, "alert-format", string(AlertFormatJSON), "Format of the alerts, one of json, slack, wecom and dingtalk.")
+	fs.IntVar(&o.AlertThreshold, "alert-threshold", 5, "Failures of a handler within the alert window raising an alert.")
+	fs.DurationVar(&o.AlertWindow, "alert-window", 10*time.Minute, "Window counting the failures of a handler.")
+	fs.BoolVar(&o.ReportUnknownFields, "report-unknown-fields", false, "Log the fields of the payloads unknown to the handlers, to detect the changes of the schema.")
+	fs.DurationVar(&o.PushDebounce, "push-debounce", 0, "Window coalescing the push events of the same branch, disabled if 0.")
+	fs.Func("disable-handlers", "Comma separated handlers in the form of robot/kind to switch off.", func(s string) error {
+		o.DisabledHandlers = strings.Split(s, ",")
+
+		return nil
+	})
+	fs.StringVar(&o.WebhookPath, "webhook-path", defaultWebhookPath, "Path the webhooks deliver the events to.")
+	fs.DurationVar(
+		&o.GracePeriod, "grace-period", 180*time.Second,
+		"On shutdown, try to handle remaining events for the specified duration.",
+	)
+	fs.StringVar(
+		&o.WebhookSecretFile, "webhook-secret-file", "/etc/webhook/secret",
+		"Path to the file containing the secret token of the webhooks.",
+	)
+	fs.Func(
+		"project-webhook-secret-files",
+		"Comma separated path=file mapping the projects and the groups to the files containing their own secret tokens.",
+		func(s string) (err error) {
+			o.ProjectSecretFiles, err = parseProjectSecretFiles(s)
+
+			return err
+		},
+	)
+}
+
+// Validate checks the options.
+func (o *ServiceOptions) Validate() error {
+	if o.Port <= 0 || o.Port > 65535 {
+		return errors.New("invalid port")
+	}
+
+	if o.DebugPort < 0 || o.DebugPort > 65535 || (o.DebugPort != 0 && o.DebugPort == o.Port) {
+		return errors.New("invalid debug port")
+	}
+
+	if o.DebugPort != 0 && o.EventStore != "" && o.AdminTokenFile == "" {
+		return errors.New("missing admin token file to serve the stored events on the debug port")
+	}
+
+	if o.MaxInFlight < 0 {
+		return errors.New("invalid max in flight")
+	}
+
+	if o.DedupStore != "" && o.DedupWindow <= 0 {
+		return errors.New("invalid dedup window")
+	}
+
+	if o.AlertWebhookFile != "" && (o.AlertThreshold <= 0 || o.AlertWindow <= 0) {
+		return errors.New("invalid alert threshold or window")
+	}
+
+	if o.PushDebounce < 0 {
+		return errors.New("invalid push debounce")
+	}
+
+	if !strings.HasPrefix(o.WebhookPath, "/") {
+		return errors.New("webhook path must start with /")
+	}
+
+	if o.WebhookSecretFile == "" && o.WebhookSecret == nil {
+		return errors.New("missing webhook secret file")
+	}
+
+	return nil
+}
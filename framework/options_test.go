@@ -0,0 +1,69 @@
+package framework
+
+import (
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr bool
+	}{
+		{name: "defaults"},
+		{name: "debug port", args: []string{"--debug-port=8889"}},
+		{name: "debug port same as port", args: []string{"--debug-port=8888"}, wantErr: true},
+		{name: "event store without debug port", args: []string{"--event-store=/tmp/events"}},
+		{
+			name:    "event store on debug port without admin token",
+			args:    []string{"--debug-port=8889", "--event-store=/tmp/events"},
+			wantErr: true,
+		},
+		{
+			name: "event store on debug port with admin token",
+			args: []string{"--debug-port=8889", "--event-store=/tmp/events", "--admin-token-file=/tmp/token"},
+		},
+		{name: "negative push debounce", args: []string{"--push-debounce=-1s"}, wantErr: true},
+		{name: "relative webhook path", args: []string{"--webhook-path=hook"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var o ServiceOptions
+
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			o.AddFlags(fs)
+
+			if err := fs.Parse(tt.args); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := o.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("got error %v, want error %t", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDebugMuxWithoutAdmin(t *testing.T) {
+	mux := debugMux(nil)
+
+	for _, path := range []string{"/events", "/events/1/replay", "/admin/status"} {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, nil))
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("%s: got %d, want %d", path, w.Code, http.StatusNotFound)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/runtime", nil))
+
+	if w.Code != http.StatusOK {
+		t.Errorf("/debug/runtime: got %d, want %d", w.Code, http.StatusOK)
+	}
+}
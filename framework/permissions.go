@@ -0,0 +1,50 @@
+package framework
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+	"github.com/xanzy/go-gitlab"
+
+	"github.com/opensourceways/robot-gitlab-lib/client"
+)
+
+// NewPermissionInvalidator returns the robot dropping the access levels
+// cached by client.WithPermissionCache of the users whose membership is
+// added, changed or removed, so that it takes effect before the cache
+// expires. It uses the client passed to the handlers if the clients are
+// routed, and cli otherwise. Host it with the robots using the cache.
+//
+// The member events are delivered by the group webhooks only, which are
+// not created by Handler.EnsureWebhooks, so a group webhook with the
+// member events enabled must deliver to the endpoint of the robot.
+func NewPermissionInvalidator(cli client.Interface) Robot {
+	return &permissionInvalidator{cli: cli}
+}
+
+type permissionInvalidator struct {
+	cli client.Interface
+}
+
+func (p *permissionInvalidator) Name() string {
+	return "permission_invalidator"
+}
+
+func (p *permissionInvalidator) RegisterEventHandler(r HandlerRegister) {
+	r.RegisterMemberEventHandler(p.handleMemberEvent)
+}
+
+func (p *permissionInvalidator) handleMemberEvent(
+	ctx context.Context, e *gitlab.MemberEvent, log *logrus.Entry,
+) error {
+	cli, ok := ClientFrom(ctx)
+	if !ok {
+		cli = p.cli
+	}
+
+	cli.InvalidatePermissions(e.UserUsername)
+
+	log.WithField("user", e.UserUsername).Debug("invalidated the cached permissions")
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package framework_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/opensourceways/robot-gitlab-lib/client/fake"
+	"github.com/opensourceways/robot-gitlab-lib/framework"
+	"github.com/opensourceways/robot-gitlab-lib/framework/gitlabtest"
+)
+
+func TestPermissionInvalidator(t *testing.T) {
+	payload, eventType := gitlabtest.Fixture(gitlabtest.FixtureMember)
+
+	tests := []struct {
+		name   string
+		routed bool
+	}{
+		{name: "default client"},
+		{name: "routed client", routed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli, routed := &fake.Client{}, &fake.Client{}
+
+			ctx := context.Background()
+			if tt.routed {
+				ctx = framework.WithClient(ctx, routed)
+			}
+
+			bot := framework.NewPermissionInvalidator(cli)
+			if err := gitlabtest.DispatchContext(ctx, bot, string(eventType), payload); err != nil {
+				t.Fatal(err)
+			}
+
+			want, other := cli, routed
+			if tt.routed {
+				want, other = routed, cli
+			}
+
+			calls := want.CallsOf("InvalidatePermissions")
+			if len(calls) != 1 || calls[0].Args[0] != "alice" {
+				t.Errorf("got %v, want alice invalidated", calls)
+			}
+
+			if calls := other.CallsOf("InvalidatePermissions"); len(calls) != 0 {
+				t.Errorf("got %v on the other client", calls)
+			}
+		})
+	}
+}
@@ -0,0 +1,331 @@
+package framework
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/xanzy/go-gitlab"
+)
+
+// zeroSHA is the SHA of the push events for the created or the deleted
+// refs.
+const zeroSHA = "0000000000000000000000000000000000000000"
+
+// pollMRActions maps the actions of the events API on the merge requests
+// to the ones of the webhooks.
+var pollMRActions = map[string]string{
+	"opened":   "open",
+	"closed":   "close",
+	"reopened": "reopen",
+	"accepted": "merge",
+	"updated":  "update",
+	"approved": "approved",
+}
+
+// pollIssueActions maps the actions of the events API on the issues to the
+// ones of the webhooks.
+var pollIssueActions = map[string]string{
+	"opened":   "open",
+	"closed":   "close",
+	"reopened": "reopen",
+	"updated":  "update",
+}
+
+type jsonObject = map[string]interface{}
+
+// convert converts the event of the events API into the payload of the
+// webhook of the same kind. The event type is empty if the event has no
+// counterpart supported.
+func (p *Poller) convert(project string, e *gitlab.ProjectEvent) (gitlab.EventType, []byte, error) {
+	user := jsonObject{
+		"id":         e.Author.ID,
+		"name":       e.Author.Name,
+		"username":   e.Author.Username,
+		"avatar_url": e.Author.AvatarURL,
+	}
+
+	proj := jsonObject{
+		"id":                  e.ProjectID,
+		"name":                project[strings.LastIndex(project, "/")+1:],
+		"path_with_namespace": project,
+	}
+
+	switch {
+	case e.PushData.Ref != "":
+		return convertPush(e, user, proj)
+
+	case e.TargetType == "MergeRequest":
+		action, ok := pollMRActions[e.ActionName]
+		if !ok {
+			return "", nil, nil
+		}
+
+		return p.convertMR(project, e.TargetIID, action, user, proj)
+
+	case e.TargetType == "Issue":
+		action, ok := pollIssueActions[e.ActionName]
+		if !ok {
+			return "", nil, nil
+		}
+
+		return p.convertIssue(project, e.TargetIID, action, user, proj)
+
+	case e.Note.ID != 0 && !e.Note.System:
+		return p.convertNote(project, e, user, proj)
+
+	default:
+		return "", nil, nil
+	}
+}
+
+func convertPush(e *gitlab.ProjectEvent, user, proj jsonObject) (gitlab.EventType, []byte, error) {
+	d := &e.PushData
+
+	before, after := d.CommitFrom, d.CommitTo
+	if before == "" {
+		before = zeroSHA
+	}
+
+	if after == "" || d.Action == "removed" {
+		after = zeroSHA
+	}
+
+	v := jsonObject{
+		"before":              before,
+		"after":               after,
+		"user_id":             user["id"],
+		"user_name":           user["name"],
+		"user_username":       user["username"],
+		"user_avatar":         user["avatar_url"],
+		"project_id":          e.ProjectID,
+		"project":             proj,
+		"total_commits_count": d.CommitCount,
+	}
+
+	if d.RefType == "tag" {
+		v["object_kind"], v["event_name"] = "tag_push", "tag_push"
+		v["ref"] = "refs/tags/" + d.Ref
+
+		return marshalPayload(gitlab.EventTypeTagPush, v)
+	}
+
+	v["object_kind"], v["event_name"] = "push", "push"
+	v["ref"] = "refs/heads/" + d.Ref
+
+	return marshalPayload(gitlab.EventTypePush, v)
+}
+
+func (p *Poller) convertMR(
+	project string, iid int, action string, user, proj jsonObject,
+) (gitlab.EventType, []byte, error) {
+	attrs, labels, err := p.mrAttributes(project, iid)
+	if err != nil {
+		return "", nil, err
+	}
+
+	attrs["action"] = action
+
+	return marshalPayload(gitlab.EventTypeMergeRequest, jsonObject{
+		"object_kind":       "merge_request",
+		"event_type":        "merge_request",
+		"user":              user,
+		"project":           proj,
+		"object_attributes": attrs,
+		"labels":            labels,
+	})
+}
+
+func (p *Poller) convertIssue(
+	project string, iid int, action string, user, proj jsonObject,
+) (gitlab.EventType, []byte, error) {
+	attrs, labels, err := p.issueAttributes(project, iid)
+	if err != nil {
+		return "", nil, err
+	}
+
+	attrs["action"] = action
+
+	return marshalPayload(gitlab.EventTypeIssue, jsonObject{
+		"object_kind":       "issue",
+		"event_type":        "issue",
+		"user":              user,
+		"project":           proj,
+		"object_attributes": attrs,
+		"labels":            labels,
+	})
+}
+
+func (p *Poller) convertNote(
+	project string, e *gitlab.ProjectEvent, user, proj jsonObject,
+) (gitlab.EventType, []byte, error) {
+	n := &e.Note
+
+	v := jsonObject{
+		"object_kind": "note",
+		"event_type":  "note",
+		"user":        user,
+		"project_id":  e.ProjectID,
+		"project":     proj,
+	}
+
+	attrs := jsonObject{
+		"id":            n.ID,
+		"note":          n.Body,
+		"noteable_type": n.NoteableType,
+		"noteable_id":   n.NoteableID,
+		"author_id":     n.Author.ID,
+		"project_id":    e.ProjectID,
+		"created_at":    n.CreatedAt,
+		"attachment":    n.Attachment,
+	}
+
+	switch n.NoteableType {
+	case "MergeRequest":
+		mr, _, err := p.mrAttributes(project, n.NoteableIID)
+		if err != nil {
+			return "", nil, err
+		}
+
+		attrs["url"] = mr["url"].(string) + noteAnchor(n.ID)
+		v["merge_request"] = mr
+
+	case "Issue":
+		issue, _, err := p.issueAttributes(project, n.NoteableIID)
+		if err != nil {
+			return "", nil, err
+		}
+
+		attrs["url"] = issue["url"].(string) + noteAnchor(n.ID)
+		v["issue"] = issue
+
+	default:
+		return "", nil, nil
+	}
+
+	v["object_attributes"] = attrs
+
+	return marshalPayload(gitlab.EventTypeNote, v)
+}
+
+// mrAttributes returns the attributes of the merge request and its labels
+// in the forms of the webhooks.
+func (p *Poller) mrAttributes(project string, iid int) (jsonObject, []jsonObject, error) {
+	mr, err := p.cli.GetMR(project, iid)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	attrs, err := toJSONObject(mr)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The fields in different forms from the ones of the webhooks.
+	delete(attrs, "approvals_before_merge")
+	delete(attrs, "head_pipeline")
+
+	labels := eventLabels(mr.Labels)
+
+	attrs["url"] = mr.WebURL
+	attrs["labels"] = labels
+	attrs["last_commit"] = jsonObject{"id": mr.SHA}
+
+	if mr.Author != nil {
+		attrs["author_id"] = mr.Author.ID
+	}
+
+	attrs["assignee_ids"] = basicUserIDs(mr.Assignees)
+	attrs["reviewer_ids"] = basicUserIDs(mr.Reviewers)
+
+	if mr.Milestone != nil {
+		attrs["milestone_id"] = mr.Milestone.ID
+	}
+
+	if mr.HeadPipeline != nil {
+		attrs["head_pipeline_id"] = mr.HeadPipeline.ID
+	}
+
+	return attrs, labels, nil
+}
+
+// issueAttributes returns the attributes of the issue and its labels in
+// the forms of the webhooks.
+func (p *Poller) issueAttributes(project string, iid int) (jsonObject, []jsonObject, error) {
+	issue, err := p.cli.GetIssue(project, iid)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	attrs, err := toJSONObject(issue)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	labels := eventLabels(issue.Labels)
+
+	attrs["url"] = issue.WebURL
+	attrs["labels"] = labels
+
+	if issue.Author != nil {
+		attrs["author_id"] = issue.Author.ID
+	}
+
+	ids := make([]int, 0, len(issue.Assignees))
+	for _, u := range issue.Assignees {
+		ids = append(ids, u.ID)
+	}
+
+	attrs["assignee_ids"] = ids
+
+	if issue.Milestone != nil {
+		attrs["milestone_id"] = issue.Milestone.ID
+	}
+
+	return attrs, labels, nil
+}
+
+func eventLabels(names []string) []jsonObject {
+	r := make([]jsonObject, len(names))
+	for i, name := range names {
+		r[i] = jsonObject{"title": name}
+	}
+
+	return r
+}
+
+func basicUserIDs(users []*gitlab.BasicUser) []int {
+	r := make([]int, 0, len(users))
+	for _, u := range users {
+		r = append(r, u.ID)
+	}
+
+	return r
+}
+
+func noteAnchor(id int) string {
+	return "#note_" + strconv.Itoa(id)
+}
+
+func toJSONObject(v interface{}) (jsonObject, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var r jsonObject
+	if err := json.Unmarshal(b, &r); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func marshalPayload(t gitlab.EventType, v jsonObject) (gitlab.EventType, []byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return t, b, nil
+}
@@ -0,0 +1,143 @@
+package framework
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/xanzy/go-gitlab"
+
+	"github.com/opensourceways/robot-gitlab-lib/client"
+)
+
+const defaultPollInterval = time.Minute
+
+// Source is an event source other than the webhooks, such as Poller, for
+// the projects which can't deliver the webhooks to the robots.
+type Source interface {
+	// Run passes the events to d until ctx is done.
+	Run(ctx context.Context, d *Dispatcher) error
+}
+
+// PollOptions are the options of Poller.
+type PollOptions struct {
+	// Projects are the full paths of the projects to poll.
+	Projects []string
+
+	// Interval is the interval of polling, and defaults to one minute.
+	Interval time.Duration
+}
+
+// Poller is the Source polling the events API of the projects. The events
+// are converted into the same events as the webhooks deliver, with the
+// merge requests and the issues fetched at the time of polling, and are
+// dispatched in order. The pushes, the tag pushes, the actions on the
+// merge requests and the issues and the notes on them are supported.
+//
+// Only the events happening after the poller starts are dispatched.
+type Poller struct {
+	cli  client.Interface
+	opts PollOptions
+
+	// cursors are the polling states of the projects.
+	cursors map[string]*pollCursor
+}
+
+type pollCursor struct {
+	// lastID is the ID of the last event seen.
+	lastID int
+}
+
+// NewPoller returns a poller polling with cli.
+func NewPoller(cli client.Interface, opts PollOptions) *Poller {
+	if opts.Interval <= 0 {
+		opts.Interval = defaultPollInterval
+	}
+
+	return &Poller{
+		cli:     cli,
+		opts:    opts,
+		cursors: make(map[string]*pollCursor, len(opts.Projects)),
+	}
+}
+
+// Run implements Source.
+func (p *Poller) Run(ctx context.Context, d *Dispatcher) error {
+	t := time.NewTicker(p.opts.Interval)
+	defer t.Stop()
+
+	for {
+		for _, project := range p.opts.Projects {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			p.poll(ctx, d, project)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case <-t.C:
+		}
+	}
+}
+
+// poll dispatches the new events of the project. The first polling of a
+// project only records the last event.
+func (p *Poller) poll(ctx context.Context, d *Dispatcher, project string) {
+	log := logrus.WithFields(logrus.Fields{
+		"event-source": "poll",
+		"project":      project,
+	})
+
+	cur, ok := p.cursors[project]
+	if !ok {
+		cur = &pollCursor{}
+	}
+
+	// The first polling only needs the last event.
+	limit := 0
+	if !ok {
+		limit = 1
+	}
+
+	events, err := p.cli.ListNewProjectEvents(project, cur.lastID, limit)
+	if err != nil {
+		log.WithError(err).Error("list the events")
+
+		return
+	}
+
+	p.cursors[project] = cur
+
+	for _, e := range events {
+		if ok {
+			p.dispatch(ctx, d, project, e, log)
+		}
+
+		cur.lastID = e.ID
+	}
+}
+
+func (p *Poller) dispatch(ctx context.Context, d *Dispatcher, project string, e *gitlab.ProjectEvent, log *logrus.Entry) {
+	log = log.WithField("event-id", e.ID)
+
+	eventType, payload, err := p.convert(project, e)
+	if err != nil {
+		log.WithError(err).Error("convert the event")
+
+		return
+	}
+
+	if eventType == "" {
+		return
+	}
+
+	log = log.WithField("event-type", eventType)
+
+	if err := d.Dispatch(ctx, string(eventType), payload, log); err != nil {
+		log.WithError(err).Error("handle the event")
+	}
+}
@@ -0,0 +1,82 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/opensourceways/robot-gitlab-lib/client"
+)
+
+// SetProjectSecrets verifies the deliveries of the projects, and the ones
+// of the projects under the groups, against their own secret tokens
+// instead of the one of the handler, so that the secret of a tenant can be
+// rotated, or revoked if compromised, without touching the others. The
+// secrets are keyed by the full paths of the projects and the groups, of
+// which the longest one containing the project wins. The deliveries of the
+// other projects, and the ones without a project such as the member
+// events, are verified against the secret of the handler. It must be
+// called before the handler serves.
+func (wh *Handler) SetProjectSecrets(secrets map[string]func() []byte) {
+	wh.projectSecrets = make(map[string]func() []byte, len(secrets))
+	for k, v := range secrets {
+		wh.projectSecrets[strings.Trim(k, "/")] = v
+	}
+}
+
+// secretOf returns the secret token of the webhooks of the project.
+func (wh *Handler) secretOf(project string) func() []byte {
+	secret, longest := wh.secret, -1
+
+	for p, v := range wh.projectSecrets {
+		if project != p && !strings.HasPrefix(project, p+"/") {
+			continue
+		}
+
+		if len(p) > longest {
+			secret, longest = v, len(p)
+		}
+	}
+
+	return secret
+}
+
+// parseProjectSecretFiles parses the mapping in the form of path=file, such
+// as mygroup=/etc/webhook/mygroup.
+func parseProjectSecretFiles(s string) (map[string]string, error) {
+	m := map[string]string{}
+
+	for _, item := range strings.Split(s, ",") {
+		path, file, ok := strings.Cut(strings.TrimSpace(item), "=")
+		if !ok || path == "" || file == "" {
+			return nil, fmt.Errorf("invalid project secret %q, want path=file", item)
+		}
+
+		m[strings.Trim(path, "/")] = file
+	}
+
+	return m, nil
+}
+
+// loadProjectSecrets returns the secret tokens of the projects of opts,
+// reading the files again every secretReloadInterval until ctx is done.
+func loadProjectSecrets(ctx context.Context, opts *ServiceOptions) (map[string]func() []byte, error) {
+	secrets := make(map[string]func() []byte, len(opts.ProjectSecretFiles)+len(opts.ProjectSecrets))
+
+	for path, file := range opts.ProjectSecretFiles {
+		f, err := client.LoadTokenFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("read the webhook secret of %s: %w", path, err)
+		}
+
+		go f.Watch(ctx, secretReloadInterval)
+
+		secrets[path] = f.Token
+	}
+
+	for path, secret := range opts.ProjectSecrets {
+		secrets[path] = secret
+	}
+
+	return secrets, nil
+}
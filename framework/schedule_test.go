@@ -0,0 +1,97 @@
+package framework
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestParseCron(t *testing.T) {
+	tests := []struct {
+		expr    string
+		wantErr bool
+	}{
+		{expr: "* * * * *"},
+		{expr: "*/15 0-6 1,15 * 1-5"},
+		{expr: "0-30/10 12 * 1-12/3 0"},
+		{expr: "5/20 * * * *"},
+		{expr: "* * * *", wantErr: true},
+		{expr: "* * * * * *", wantErr: true},
+		{expr: "60 * * * *", wantErr: true},
+		{expr: "* 24 * * *", wantErr: true},
+		{expr: "* * 0 * *", wantErr: true},
+		{expr: "* * * 13 *", wantErr: true},
+		{expr: "* * * * 7", wantErr: true},
+		{expr: "5-1 * * * *", wantErr: true},
+		{expr: "*/0 * * * *", wantErr: true},
+		{expr: "a * * * *", wantErr: true},
+		{expr: "1-b * * * *", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			if _, err := ParseCron(tt.expr); (err != nil) != tt.wantErr {
+				t.Errorf("got error %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	// 2024-01-01 is a Monday.
+	from := time.Date(2024, 1, 1, 10, 30, 20, 0, time.UTC)
+
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{expr: "* * * * *", want: time.Date(2024, 1, 1, 10, 31, 0, 0, time.UTC)},
+		{expr: "*/15 * * * *", want: time.Date(2024, 1, 1, 10, 45, 0, 0, time.UTC)},
+		{expr: "30 10 * * *", want: time.Date(2024, 1, 2, 10, 30, 0, 0, time.UTC)},
+		{expr: "0 9 * * *", want: time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC)},
+		{expr: "0 0 1 * *", want: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 12 * * 5", want: time.Date(2024, 1, 5, 12, 0, 0, 0, time.UTC)},
+		{expr: "0 0 29 2 *", want: time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 0 31 * *", want: time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 0 1 12 *", want: time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC)},
+		// Either the day of month or the day of week matches if neither
+		// is *, so the 15th or the next Friday.
+		{expr: "0 0 15 * 5", want: time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 0 2 * 5", want: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 0 30 2 *", want: time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			s, err := ParseCron(tt.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			s.(*cronSchedule).loc = time.UTC
+
+			if got := s.Next(from); !got.Equal(tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPeriodicTaskRecovers(t *testing.T) {
+	task := &periodicTask{
+		name: "panic",
+		fn: func(context.Context, *logrus.Entry) error {
+			panic("boom")
+		},
+	}
+
+	log := logrus.New()
+	log.Out = io.Discard
+
+	if err := task.runOnce(context.Background(), logrus.NewEntry(log)); err == nil || err.Error() != "panic: boom" {
+		t.Errorf("got error %v, want the panic", err)
+	}
+}
@@ -0,0 +1,87 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// PeriodicTask is a task of a robot run on a schedule, such as sweeping
+// the stale merge requests. The context is canceled on shutdown.
+type PeriodicTask func(ctx context.Context, log *logrus.Entry) error
+
+type periodicTask struct {
+	name     string
+	schedule Schedule
+	fn       PeriodicTask
+}
+
+// runTasks runs the periodic tasks registered to the dispatcher until ctx
+// is done. Each task runs in its own goroutine, and a run of a task
+// starts only after the former one finishes.
+func (d *Dispatcher) runTasks(ctx context.Context) {
+	var wg sync.WaitGroup
+
+	for i := range d.hs {
+		for _, task := range d.hs[i].tasks {
+			wg.Add(1)
+
+			go func(task periodicTask) {
+				defer wg.Done()
+
+				task.run(ctx)
+			}(task)
+		}
+	}
+
+	wg.Wait()
+}
+
+func (task *periodicTask) run(ctx context.Context) {
+	log := logrus.WithField("task", task.name)
+
+	for {
+		next := task.schedule.Next(time.Now())
+		if next.IsZero() {
+			log.Warn("the task will never run again")
+
+			return
+		}
+
+		t := time.NewTimer(time.Until(next))
+
+		select {
+		case <-ctx.Done():
+			t.Stop()
+
+			return
+
+		case <-t.C:
+		}
+
+		start := time.Now()
+		if err := task.runOnce(ctx, log); err != nil {
+			log.WithError(err).Error("run the task")
+		} else {
+			log.WithField("duration", time.Since(start)).Debug("task done")
+		}
+	}
+}
+
+// runOnce runs the task, recovering its panic as the error, so that a
+// panic neither kills the service nor stops the later runs.
+func (task *periodicTask) runOnce(ctx context.Context, log *logrus.Entry) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = fmt.Errorf("panic: %v", v)
+
+			log.WithField("stack", string(debug.Stack())).Error("the task panicked")
+		}
+	}()
+
+	return task.fn(ctx, log)
+}
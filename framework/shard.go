@@ -0,0 +1,229 @@
+package framework
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// headerForwardedBy marks the deliveries forwarded by a replica to the
+	// owner of the project, which are handled by the receiver whatever the
+	// ring says, so that the replicas disagreeing on the ring during a
+	// rollout do not forward a delivery in circles.
+	headerForwardedBy = "X-Robot-Forwarded-By"
+
+	defaultVirtualNodes = 100
+	forwardTimeout      = 10 * time.Second
+)
+
+// ShardOptions are the options of sharding the events across the replicas
+// by the projects, so that the events of a project are handled by one
+// replica and in the order they are delivered.
+type ShardOptions struct {
+	// Self is the base URL of the replica, which must be one of Peers.
+	Self string
+
+	// Peers are the base URLs of all the replicas, such as the ones of the
+	// pods of a StatefulSet like http://robot-0.robot:8888.
+	Peers []string
+
+	// VirtualNodes is the number of the points of each replica on the hash
+	// ring, and defaults to 100.
+	VirtualNodes int
+}
+
+// hashRing is the consistent hash ring of the replicas, so that only the
+// projects of a replica joining or leaving move to another replica.
+type hashRing struct {
+	points []uint64
+	owners map[uint64]string
+}
+
+func newHashRing(peers []string, virtualNodes int) *hashRing {
+	r := &hashRing{owners: make(map[uint64]string, len(peers)*virtualNodes)}
+
+	for _, peer := range peers {
+		for i := 0; i < virtualNodes; i++ {
+			h := hashKey(peer + "#" + strconv.Itoa(i))
+			if _, ok := r.owners[h]; ok {
+				continue
+			}
+
+			r.owners[h] = peer
+			r.points = append(r.points, h)
+		}
+	}
+
+	slices.Sort(r.points)
+
+	return r
+}
+
+// owner returns the replica owning the key, which is the first point at or
+// after the hash of the key clockwise.
+func (r *hashRing) owner(key string) string {
+	h := hashKey(key)
+
+	i, _ := slices.BinarySearch(r.points, h)
+	if i == len(r.points) {
+		i = 0
+	}
+
+	return r.owners[r.points[i]]
+}
+
+func hashKey(s string) uint64 {
+	h := sha256.Sum256([]byte(s))
+
+	return binary.BigEndian.Uint64(h[:8])
+}
+
+// sharder forwards the deliveries of the projects owned by the other
+// replicas to them, and serializes the handling of the events of each
+// project owned by the replica.
+type sharder struct {
+	self  string
+	peers []string
+	ring  *hashRing
+	hc    *http.Client
+
+	mu     sync.Mutex
+	queues map[string][]func()
+}
+
+// Shard enables sharding the events across the replicas. It must be called
+// before the handler serves.
+func (wh *Handler) Shard(opts ShardOptions) error {
+	self := strings.TrimSuffix(opts.Self, "/")
+
+	peers := make([]string, len(opts.Peers))
+	for i, p := range opts.Peers {
+		peers[i] = strings.TrimSuffix(p, "/")
+	}
+
+	if !slices.Contains(peers, self) {
+		return errors.New("the replica itself is not one of the peers")
+	}
+
+	if opts.VirtualNodes <= 0 {
+		opts.VirtualNodes = defaultVirtualNodes
+	}
+
+	wh.shard = &sharder{
+		self:   self,
+		peers:  peers,
+		ring:   newHashRing(peers, opts.VirtualNodes),
+		hc:     &http.Client{Timeout: forwardTimeout},
+		queues: map[string][]func(){},
+	}
+
+	return nil
+}
+
+// eventProject returns the path of the project of the payload, or empty
+// if it is not found.
+func eventProject(payload []byte) string {
+	var v eventHeader
+	if err := json.Unmarshal(payload, &v); err != nil {
+		return ""
+	}
+
+	return v.Project.PathWithNamespace
+}
+
+// route returns the replica owning the project, or empty if the event is
+// to be handled by this replica.
+func (s *sharder) route(r *http.Request, project string) string {
+	if project == "" || r.Header.Get(headerForwardedBy) != "" {
+		return ""
+	}
+
+	if owner := s.ring.owner(project); owner != s.self {
+		return owner
+	}
+
+	return ""
+}
+
+// forward sends the delivery to the owner and copies its response. A
+// failure of forwarding is responded 503, so that GitLab delivers it
+// again later.
+func (s *sharder) forward(w http.ResponseWriter, r *http.Request, owner string, payload []byte) error {
+	req, err := http.NewRequestWithContext(
+		r.Context(), http.MethodPost, owner+r.URL.RequestURI(), bytes.NewReader(payload),
+	)
+	if err != nil {
+		return err
+	}
+
+	for k, v := range r.Header {
+		if strings.HasPrefix(k, "X-Gitlab-") || k == "Content-Type" || k == "User-Agent" {
+			req.Header[k] = v
+		}
+	}
+
+	req.Header.Set(headerForwardedBy, s.self)
+
+	resp, err := s.hc.Do(req)
+	if err != nil {
+		http.Error(w, "503 Service Unavailable: Failed to forward the event", http.StatusServiceUnavailable)
+
+		return err
+	}
+	defer resp.Body.Close()
+
+	// Pass on the response of the owner as it is, such as the Retry-After
+	// of rejecting the delivery when it is busy.
+	for k, v := range resp.Header {
+		if k != "Connection" {
+			w.Header()[k] = v
+		}
+	}
+
+	w.WriteHeader(resp.StatusCode)
+	_, err = io.Copy(w, resp.Body)
+
+	return err
+}
+
+// serialize runs fn after the former ones of the project finish. The ones
+// of different projects run concurrently.
+func (s *sharder) serialize(project string, fn func()) {
+	s.mu.Lock()
+	q, running := s.queues[project]
+	s.queues[project] = append(q, fn)
+	s.mu.Unlock()
+
+	if running {
+		return
+	}
+
+	go func() {
+		for {
+			s.mu.Lock()
+			q := s.queues[project]
+			if len(q) == 0 {
+				delete(s.queues, project)
+				s.mu.Unlock()
+
+				return
+			}
+
+			fn := q[0]
+			s.queues[project] = q[1:]
+			s.mu.Unlock()
+
+			fn()
+		}
+	}()
+}
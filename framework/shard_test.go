@@ -0,0 +1,102 @@
+package framework
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHashRing(t *testing.T) {
+	peers := []string{
+		"http://robot-0.robot:8888",
+		"http://robot-1.robot:8888",
+		"http://robot-2.robot:8888",
+	}
+
+	tests := []struct {
+		name   string
+		before []string
+		after  []string
+		// maxMoved is the maximum share of the keys moving to another
+		// replica, in percent.
+		maxMoved int
+	}{
+		{name: "unchanged", before: peers, after: peers, maxMoved: 0},
+		{name: "reordered", before: peers, after: []string{peers[2], peers[0], peers[1]}, maxMoved: 0},
+		{name: "joined", before: peers[:2], after: peers, maxMoved: 50},
+		{name: "left", before: peers, after: peers[:2], maxMoved: 50},
+	}
+
+	keys := make([]string, 1000)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("opensourceways/project-%d", i)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := newHashRing(tt.before, defaultVirtualNodes)
+			after := newHashRing(tt.after, defaultVirtualNodes)
+
+			moved := 0
+			owned := map[string]int{}
+
+			for _, k := range keys {
+				owner := after.owner(k)
+				owned[owner]++
+
+				if before.owner(k) != owner {
+					moved++
+				}
+
+				if after.owner(k) != owner {
+					t.Fatalf("got the owners of %s changing", k)
+				}
+			}
+
+			if moved*100 > tt.maxMoved*len(keys) {
+				t.Errorf("got %d of %d keys moved, want at most %d%%", moved, len(keys), tt.maxMoved)
+			}
+
+			// Each replica owns a fair share of the keys.
+			for _, peer := range tt.after {
+				if n := owned[peer]; n*len(tt.after)*2 < len(keys) {
+					t.Errorf("got %d of %d keys owned by %s", n, len(keys), peer)
+				}
+			}
+		})
+	}
+}
+
+func TestShardForwardHeaders(t *testing.T) {
+	owner := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(headerForwardedBy) != "http://robot-0" {
+			t.Errorf("got forwarded by %q", r.Header.Get(headerForwardedBy))
+		}
+
+		w.Header().Set("Retry-After", "5")
+		http.Error(w, "busy", http.StatusTooManyRequests)
+	}))
+	defer owner.Close()
+
+	s := &sharder{self: "http://robot-0", hc: owner.Client()}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/webhook", nil)
+
+	if err := s.forward(w, r, owner.URL, []byte("{}")); err != nil {
+		t.Fatal(err)
+	}
+
+	if w.Code != http.StatusTooManyRequests {
+		t.Errorf("got %d, want %d", w.Code, http.StatusTooManyRequests)
+	}
+
+	if got := w.Header().Get("Retry-After"); got != "5" {
+		t.Errorf("got Retry-After %q, want 5", got)
+	}
+
+	if got := w.Header().Get("Content-Type"); got != "text/plain; charset=utf-8" {
+		t.Errorf("got Content-Type %q", got)
+	}
+}
@@ -0,0 +1,191 @@
+package framework
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ReportUnknownFields makes the handler log the fields of the payloads
+// which the events the handlers decode do not have, such as the ones added
+// or renamed by an upgrade of GitLab, so that the changes of the schema
+// are noticed before the robots misbehave. Each field is logged once for
+// each kind. It costs decoding the payloads once more, and must be called
+// before the handler serves.
+func (wh *Handler) ReportUnknownFields() {
+	wh.d.unknownFields = &unknownFieldReporter{}
+}
+
+// unknownFieldReporter logs the unknown fields of the kinds once.
+type unknownFieldReporter struct {
+	reported sync.Map
+}
+
+func (r *unknownFieldReporter) report(kind string, fields []string) {
+	var fresh []string
+	for _, f := range fields {
+		if _, loaded := r.reported.LoadOrStore(kind+"\x00"+f, true); !loaded {
+			fresh = append(fresh, f)
+		}
+	}
+
+	if len(fresh) > 0 {
+		logrus.WithFields(logrus.Fields{
+			"kind":   kind,
+			"fields": fresh,
+		}).Warn("unknown fields of the payload")
+	}
+}
+
+var (
+	jsonUnmarshalerType = reflect.TypeFor[json.Unmarshaler]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// unknownFields returns the paths of the fields of the payload which type
+// t does not have, such as object_attributes.foo, in order. The elements
+// of the arrays are denoted by [].
+func unknownFields(payload []byte, t reflect.Type) ([]string, error) {
+	d := json.NewDecoder(bytes.NewReader(payload))
+	d.UseNumber()
+
+	var v interface{}
+	if err := d.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	walkUnknownFields(v, t, "", seen)
+
+	fields := make([]string, 0, len(seen))
+	for f := range seen {
+		fields = append(fields, f)
+	}
+
+	slices.Sort(fields)
+
+	return fields, nil
+}
+
+func walkUnknownFields(v interface{}, t reflect.Type, path string, seen map[string]bool) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	// The types decoding themselves are opaque.
+	if reflect.PointerTo(t).Implements(jsonUnmarshalerType) || reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return
+		}
+
+		fields := jsonFields(t)
+
+		for k, fv := range obj {
+			p := k
+			if path != "" {
+				p = path + "." + k
+			}
+
+			f, ok := fields[k]
+			if !ok {
+				// encoding/json matches the names case-insensitively.
+				for name, v := range fields {
+					if strings.EqualFold(name, k) {
+						f, ok = v, true
+
+						break
+					}
+				}
+			}
+
+			if !ok {
+				seen[p] = true
+
+				continue
+			}
+
+			walkUnknownFields(fv, f.Type, p, seen)
+		}
+
+	case reflect.Slice, reflect.Array:
+		arr, ok := v.([]interface{})
+		if !ok {
+			return
+		}
+
+		for _, ev := range arr {
+			walkUnknownFields(ev, t.Elem(), path+"[]", seen)
+		}
+
+	case reflect.Map:
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return
+		}
+
+		for _, ev := range obj {
+			walkUnknownFields(ev, t.Elem(), path+".*", seen)
+		}
+	}
+}
+
+// jsonFields returns the fields of the struct type by their JSON names,
+// including the ones promoted from the embedded structs.
+func jsonFields(t reflect.Type) map[string]reflect.StructField {
+	fields := map[string]reflect.StructField{}
+
+	for _, f := range reflect.VisibleFields(t) {
+		if !f.IsExported() || len(f.Index) > 1 && !promoted(t, f.Index) {
+			continue
+		}
+
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		if f.Anonymous && name == "" && (f.Type.Kind() == reflect.Struct ||
+			f.Type.Kind() == reflect.Pointer && f.Type.Elem().Kind() == reflect.Struct) {
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+
+		fields[name] = f
+	}
+
+	return fields
+}
+
+// promoted returns whether the nested field of the index is promoted by
+// encoding/json, which is if all the structs containing it are embedded
+// without JSON names.
+func promoted(t reflect.Type, index []int) bool {
+	for _, i := range index[:len(index)-1] {
+		f := t.Field(i)
+		if !f.Anonymous || f.Tag.Get("json") != "" {
+			return false
+		}
+
+		t = f.Type
+		if t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+	}
+
+	return true
+}
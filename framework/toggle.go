@@ -0,0 +1,149 @@
+package framework
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// The kinds of the handlers, which name them in the admin APIs and the
+// flags disabling them.
+const (
+	kindMergeRequest  = "merge_request"
+	kindMergeApproval = "merge_request_approval"
+	kindMergeChange   = "merge_request_" // followed by the MergeChange
+	kindIssue         = "issue"
+	kindPush          = "push"
+	kindTagPush       = "tag_push"
+	kindPipeline      = "pipeline"
+	kindMergeNote     = "merge_request_note"
+	kindIssueNote     = "issue_note"
+	kindCommitNote    = "commit_note"
+	kindMember        = "member"
+)
+
+// NamedRobot is a robot naming itself. The name identifies the robot in
+// the admin APIs and the flags, and defaults to its type name.
+type NamedRobot interface {
+	Robot
+
+	Name() string
+}
+
+// robotName returns the name of the robot.
+func robotName(bot Robot) string {
+	if v, ok := bot.(NamedRobot); ok {
+		return v.Name()
+	}
+
+	return fmt.Sprintf("%T", bot)
+}
+
+// toggles are the kinds of the handlers of a robot switched off at runtime.
+type toggles struct {
+	mu       sync.RWMutex
+	disabled map[string]bool
+}
+
+func (t *toggles) enabled(kind string) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return !t.disabled[kind]
+}
+
+func (t *toggles) set(kind string, enabled bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if enabled {
+		delete(t.disabled, kind)
+
+		return
+	}
+
+	if t.disabled == nil {
+		t.disabled = map[string]bool{}
+	}
+
+	t.disabled[kind] = true
+}
+
+func (t *toggles) list() []string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	r := make([]string, 0, len(t.disabled))
+	for k := range t.disabled {
+		r = append(r, k)
+	}
+
+	slices.Sort(r)
+
+	return r
+}
+
+// SetHandlerEnabled switches the handler of the kind, such as
+// merge_request or issue_note, of the robot on or off without affecting
+// the other handlers. The events of a disabled handler are ignored. It
+// returns false if the dispatcher has no such robot.
+func (d *Dispatcher) SetHandlerEnabled(robot, kind string, enabled bool) (bool, error) {
+	if !slices.ContainsFunc(handlerKinds, func(k handlerKind) bool { return k.name == kind }) {
+		return false, fmt.Errorf("unknown handler kind %q", kind)
+	}
+
+	found := false
+
+	for i, name := range d.names {
+		if name == robot {
+			d.hs[i].toggles.set(kind, enabled)
+			found = true
+		}
+	}
+
+	return found, nil
+}
+
+// SetHandlerEnabled is Dispatcher.SetHandlerEnabled of the handler.
+func (wh *Handler) SetHandlerEnabled(robot, kind string, enabled bool) (bool, error) {
+	return wh.d.SetHandlerEnabled(robot, kind, enabled)
+}
+
+// parseHandlerID splits the ID of a handler in the form of robot/kind.
+func parseHandlerID(s string) (robot, kind string, err error) {
+	i := strings.LastIndex(s, "/")
+	if i <= 0 || i == len(s)-1 {
+		return "", "", fmt.Errorf("invalid handler %q, want robot/kind", s)
+	}
+
+	return s[:i], s[i+1:], nil
+}
+
+// disableHandlers disables the handlers in the form of robot/kind on all
+// the handlers of the webhooks having the robots.
+func disableHandlers(ids []string, whs []*Handler) error {
+	for _, id := range ids {
+		robot, kind, err := parseHandlerID(id)
+		if err != nil {
+			return err
+		}
+
+		found := false
+
+		for _, wh := range whs {
+			ok, err := wh.SetHandlerEnabled(robot, kind, false)
+			if err != nil {
+				return err
+			}
+
+			found = found || ok
+		}
+
+		if !found {
+			return fmt.Errorf("disable handler %q: no robot %q", id, robot)
+		}
+	}
+
+	return nil
+}
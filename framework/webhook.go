@@ -0,0 +1,320 @@
+package framework
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/xanzy/go-gitlab"
+
+	"github.com/opensourceways/robot-gitlab-lib/client"
+)
+
+const (
+	headerEvent     = "X-Gitlab-Event"
+	headerEventUUID = "X-Gitlab-Event-UUID"
+	headerToken     = "X-Gitlab-Token"
+)
+
+// Handler is an http.Handler serving the deliveries of the webhooks, which
+// can be mounted on the mux of an existing server instead of running the
+// service by Run. The events are handled in the background after the
+// deliveries are responded, so that GitLab does not time out on the slow
+// handlers. Call Wait after the server is shut down to wait for them.
+type Handler struct {
+	d *Dispatcher
+
+	// secret returns the secret token the webhooks are configured with.
+	secret func() []byte
+
+	// projectSecrets are the secret tokens of the projects and the groups
+	// overriding secret. See SetProjectSecrets.
+	projectSecrets map[string]func() []byte
+
+	// shard is not nil if the events are sharded across the replicas.
+	shard *sharder
+
+	// recorder is not nil if the events are persisted.
+	recorder *recorder
+
+	// debounce is not nil if the push events are coalesced.
+	debounce *pushDebouncer
+
+	// dedup is not nil if the duplicate deliveries are dropped.
+	dedup *deduper
+
+	// router is not nil if the clients of the instances are passed to
+	// the handlers.
+	router *client.Router
+
+	// maxInFlight, if positive, is the number of the events handled or
+	// waiting to be handled, beyond which the deliveries are rejected to
+	// be redelivered after retryAfter.
+	maxInFlight int64
+	retryAfter  time.Duration
+
+	inFlight atomic.Int64
+	wg       sync.WaitGroup
+}
+
+// NewHandler returns the handler serving the webhooks configured with the
+// secret token returned by secret for the robots. secret is called on
+// every delivery, so that the token can be rotated.
+func NewHandler(secret func() []byte, bots ...Robot) *Handler {
+	return &Handler{
+		d:      NewDispatcher(bots...),
+		secret: secret,
+	}
+}
+
+func (wh *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	eventType, buf, ok := wh.validate(w, r)
+	if !ok {
+		return
+	}
+
+	// The buffer is reused once the event is handled, or at once if it is
+	// not accepted.
+	accepted := false
+	defer func() {
+		if !accepted {
+			putPayloadBuffer(buf)
+		}
+	}()
+
+	payload := buf.Bytes()
+	delivery := newDelivery(r, eventType)
+
+	log := logrus.WithFields(logrus.Fields{
+		"event-type": eventType,
+		"event-uuid": r.Header.Get(headerEventUUID),
+	})
+
+	ev, err := wh.d.decode(eventType, payload)
+	if err != nil {
+		if wh.d.metrics != nil {
+			wh.d.metrics.malformed.Inc()
+		}
+
+		log.WithError(err).Warn("reject the malformed payload")
+		http.Error(w, "400 Bad Request: Malformed payload: "+err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	project := ev.Project.PathWithNamespace
+
+	ctx, err := wh.handlerContext(context.Background(), delivery, project)
+	if err != nil {
+		log.WithError(err).WithField("instance", delivery.Instance).Warn("reject the event of unknown instance")
+		http.Error(w, "400 Bad Request: Unknown GitLab instance", http.StatusBadRequest)
+
+		return
+	}
+
+	if wh.shard != nil {
+		if owner := wh.shard.route(r, project); owner != "" {
+			if err := wh.shard.forward(w, r, owner, payload); err != nil {
+				log.WithError(err).WithField("owner", owner).Error("forward the event")
+			}
+
+			return
+		}
+	}
+
+	if !wh.reserve() {
+		log.WithField("in-flight", wh.inFlight.Load()).Warn("reject the event, too many events being handled")
+
+		w.Header().Set("Retry-After", strconv.Itoa(int(wh.retryAfter.Round(time.Second)/time.Second)))
+		http.Error(w, "503 Service Unavailable: Too many events being handled", http.StatusServiceUnavailable)
+
+		return
+	}
+
+	if wh.dedup != nil && wh.dedup.duplicate(r, log) {
+		wh.release()
+		log.Info("drop the duplicate delivery")
+		fmt.Fprint(w, "Duplicate event ignored.")
+
+		return
+	}
+
+	if wh.recorder != nil {
+		wh.recorder.record(&StoredEvent{
+			Received:  delivery.Received,
+			Endpoint:  r.URL.Path,
+			EventType: eventType,
+			UUID:      delivery.UUID,
+			Instance:  delivery.Instance,
+			Project:   project,
+			Payload:   payload,
+		}, log)
+	}
+
+	fmt.Fprint(w, "Event received. Have a nice day.")
+
+	accepted = true
+
+	handle := func() {
+		defer func() {
+			putPayloadBuffer(buf)
+			wh.release()
+		}()
+
+		if wh.debounce != nil && eventType == string(gitlab.EventTypePush) && wh.hold(ev, delivery, log) {
+			return
+		}
+
+		if err := wh.d.dispatch(ctx, ev, log); err != nil {
+			log.WithError(err).Error("handle the event")
+		}
+	}
+
+	if wh.shard != nil && project != "" {
+		wh.shard.serialize(project, handle)
+	} else {
+		go handle()
+	}
+}
+
+// Limit bounds the events being handled and waiting to be handled to n.
+// The deliveries beyond it are responded 503 with the Retry-After header
+// of retryAfter instead of being queued without bound, so that GitLab
+// redelivers them later. It must be called before the handler serves.
+func (wh *Handler) Limit(n int, retryAfter time.Duration) {
+	wh.maxInFlight = int64(n)
+	wh.retryAfter = max(retryAfter, time.Second)
+}
+
+// reserve counts an event to be handled and returns false if there are too
+// many ones.
+func (wh *Handler) reserve() bool {
+	if n := wh.inFlight.Add(1); wh.maxInFlight > 0 && n > wh.maxInFlight {
+		wh.inFlight.Add(-1)
+
+		return false
+	}
+
+	wh.wg.Add(1)
+
+	return true
+}
+
+// release uncounts an event reserved.
+func (wh *Handler) release() {
+	wh.inFlight.Add(-1)
+	wh.wg.Done()
+}
+
+// validate checks the delivery and returns its event type and the buffer
+// of its payload, which is to be put back by putPayloadBuffer. It responds
+// the error and returns false if the delivery is invalid.
+func (wh *Handler) validate(w http.ResponseWriter, r *http.Request) (string, *bytes.Buffer, bool) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "405 Method not allowed", http.StatusMethodNotAllowed)
+
+		return "", nil, false
+	}
+
+	eventType := r.Header.Get(headerEvent)
+	if eventType == "" {
+		http.Error(w, "400 Bad Request: Missing X-Gitlab-Event Header", http.StatusBadRequest)
+
+		return "", nil, false
+	}
+
+	token := []byte(r.Header.Get(headerToken))
+
+	// The token is checked before reading the payload against all the
+	// secrets, and against the one of the project of the payload after.
+	if !wh.knownSecret(token) {
+		http.Error(w, "403 Forbidden: Invalid X-Gitlab-Token", http.StatusForbidden)
+
+		return "", nil, false
+	}
+
+	buf := getPayloadBuffer()
+	if _, err := buf.ReadFrom(http.MaxBytesReader(w, r.Body, maxPayloadSize)); err != nil {
+		putPayloadBuffer(buf)
+
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, "413 Request Entity Too Large", http.StatusRequestEntityTooLarge)
+		} else {
+			http.Error(w, "500 Internal Server Error: Failed to read request body", http.StatusInternalServerError)
+		}
+
+		return "", nil, false
+	}
+
+	if len(wh.projectSecrets) > 0 {
+		if subtle.ConstantTimeCompare(token, wh.secretOf(eventProject(buf.Bytes()))()) != 1 {
+			putPayloadBuffer(buf)
+			http.Error(w, "403 Forbidden: Invalid X-Gitlab-Token", http.StatusForbidden)
+
+			return "", nil, false
+		}
+	}
+
+	return eventType, buf, true
+}
+
+// knownSecret reports whether the token is the secret of the handler or
+// the one of any project.
+func (wh *Handler) knownSecret(token []byte) bool {
+	ok := subtle.ConstantTimeCompare(token, wh.secret()) == 1
+
+	for _, secret := range wh.projectSecrets {
+		if subtle.ConstantTimeCompare(token, secret()) == 1 {
+			ok = true
+		}
+	}
+
+	return ok
+}
+
+// maxPayloadSize is the size of the payloads beyond which the deliveries
+// are rejected with 413. GitLab caps the commits of a push event to 20,
+// so the payloads rarely exceed a few hundred KB.
+const maxPayloadSize = 5 << 20
+
+// maxPooledPayload is the capacity of the payload buffers beyond which
+// they are not reused, so that a few large pushes do not pin the memory.
+const maxPooledPayload = 1 << 20
+
+var payloadPool = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+func getPayloadBuffer() *bytes.Buffer {
+	return payloadPool.Get().(*bytes.Buffer)
+}
+
+func putPayloadBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledPayload {
+		return
+	}
+
+	buf.Reset()
+	payloadPool.Put(buf)
+}
+
+// Wait waits for the events being handled.
+func (wh *Handler) Wait() {
+	wh.wg.Wait()
+}
+
+// RunTasks runs the periodic tasks of the robots until ctx is done, which
+// Run does for the robots it serves. Pass it to Elector.Run to run them
+// only on the leader of the replicas.
+func (wh *Handler) RunTasks(ctx context.Context) {
+	wh.d.runTasks(ctx)
+}
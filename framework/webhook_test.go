@@ -0,0 +1,84 @@
+package framework_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/opensourceways/robot-gitlab-lib/framework"
+	"github.com/opensourceways/robot-gitlab-lib/framework/gitlabtest"
+)
+
+// unreadBody is the body of a delivery failing the test if it is read.
+type unreadBody struct {
+	t *testing.T
+}
+
+func (b unreadBody) Read([]byte) (int, error) {
+	b.t.Error("the body is read")
+
+	return 0, errors.New("read")
+}
+
+func TestServeHTTPSecrets(t *testing.T) {
+	logrus.SetOutput(io.Discard)
+	defer logrus.SetOutput(os.Stderr)
+
+	tests := []struct {
+		name    string
+		project string
+		token   string
+		body    io.Reader
+		want    int
+	}{
+		{name: "handler secret", project: "other/project", token: "secret", want: http.StatusOK},
+		{name: "project secret", project: "tenant/project", token: "tenant", want: http.StatusOK},
+		{name: "handler secret of tenant", project: "tenant/project", token: "secret", want: http.StatusForbidden},
+		{name: "project secret of other", project: "other/project", token: "tenant", want: http.StatusForbidden},
+		{name: "unknown secret", project: "other/project", token: "wrong", want: http.StatusForbidden},
+		{name: "unknown secret unread", token: "wrong", body: unreadBody{t}, want: http.StatusForbidden},
+		{
+			name:  "too large",
+			token: "secret",
+			body:  strings.NewReader(`{"object_kind": "` + strings.Repeat("x", 6<<20) + `"}`),
+			want:  http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wh := framework.NewHandler(
+				func() []byte { return []byte("secret") },
+				robotFunc(func(framework.HandlerRegister) {}),
+			)
+			wh.SetProjectSecrets(map[string]func() []byte{
+				"tenant": func() []byte { return []byte("tenant") },
+			})
+
+			body := tt.body
+			if body == nil {
+				payload, _ := gitlabtest.NewMergeEvent().WithProject(tt.project).Payload()
+				body = bytes.NewReader(payload)
+			}
+
+			r := httptest.NewRequest(http.MethodPost, "/gitlab-hook", body)
+			r.Header.Set("X-Gitlab-Event", "Merge Request Hook")
+			r.Header.Set("X-Gitlab-Token", tt.token)
+
+			w := httptest.NewRecorder()
+			wh.ServeHTTP(w, r)
+			wh.Wait()
+
+			if w.Code != tt.want {
+				t.Errorf("got status %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,217 @@
+// Package labels keeps the labels of the projects consistent with a
+// declarative scheme, so that the communities share the same labels.
+package labels
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/xanzy/go-gitlab"
+
+	"github.com/opensourceways/robot-gitlab-lib/client"
+	"github.com/opensourceways/robot-gitlab-lib/framework"
+)
+
+// archivedPrefix marks the descriptions of the archived labels.
+const archivedPrefix = "[Archived] "
+
+// Label is a label of the scheme.
+type Label struct {
+	Name        string `json:"name"`
+	Color       string `json:"color"`
+	Description string `json:"description,omitempty"`
+
+	// Previously are the former names of the label. The label of such a
+	// name is renamed, so that the issues and the merge requests keep it.
+	Previously []string `json:"previously,omitempty"`
+
+	// Archived labels are no longer to be used, but are kept with the
+	// description marked for the issues and the merge requests having
+	// them.
+	Archived bool `json:"archived,omitempty"`
+}
+
+func (l *Label) description() string {
+	if l.Archived {
+		return archivedPrefix + strings.TrimPrefix(l.Description, archivedPrefix)
+	}
+
+	return l.Description
+}
+
+// Scheme is the labels of the projects.
+type Scheme struct {
+	Labels []Label `json:"labels"`
+
+	// Projects and Groups are the full paths of the projects, and the
+	// groups of which all the projects including the ones of the
+	// subgroups, to have the labels.
+	Projects []string `json:"projects,omitempty"`
+	Groups   []string `json:"groups,omitempty"`
+
+	// Prune deletes the labels of the projects which are not in the
+	// scheme. They are kept if false.
+	Prune bool `json:"prune,omitempty"`
+}
+
+// Validate checks the scheme.
+func (s *Scheme) Validate() error {
+	names := map[string]bool{}
+
+	for i := range s.Labels {
+		l := &s.Labels[i]
+		if l.Name == "" || l.Color == "" {
+			return fmt.Errorf("missing name or color of the label %d", i)
+		}
+
+		for _, n := range append([]string{l.Name}, l.Previously...) {
+			k := strings.ToLower(n)
+			if names[k] {
+				return fmt.Errorf("duplicate label %s", n)
+			}
+
+			names[k] = true
+		}
+	}
+
+	return nil
+}
+
+// The actions of the changes.
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionRename = "rename"
+	ActionDelete = "delete"
+)
+
+// Change is a change made to a label of a project.
+type Change struct {
+	Project string
+	Label   string
+	Action  string
+}
+
+// Sync reconciles the labels of all the projects of the scheme. It goes on
+// with the other projects if it fails on one, and returns the changes made
+// and all the errors.
+func Sync(cli client.Interface, s *Scheme) ([]Change, error) {
+	projects, err := client.ExpandProjects(cli, s.Projects, s.Groups)
+
+	var changes []Change
+
+	errs := []error{err}
+	for _, p := range projects {
+		v, err := SyncProject(cli, p, s)
+		changes = append(changes, v...)
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("sync the labels of %s: %w", p, err))
+		}
+	}
+
+	return changes, errors.Join(errs...)
+}
+
+// SyncProject reconciles the labels of the project with the scheme. The
+// projects of the scheme are ignored.
+func SyncProject(cli client.Interface, project string, s *Scheme) ([]Change, error) {
+	existing, err := cli.ListLabels(project)
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]*gitlab.Label, len(existing))
+	for _, l := range existing {
+		byName[strings.ToLower(l.Name)] = l
+	}
+
+	var (
+		changes []Change
+		errs    []error
+	)
+
+	record := func(label, action string, err error) {
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s label %s: %w", action, label, err))
+		} else {
+			changes = append(changes, Change{Project: project, Label: label, Action: action})
+		}
+	}
+
+	for i := range s.Labels {
+		want := &s.Labels[i]
+		opts := client.LabelOptions{Name: want.Name, Color: want.Color, Description: want.description()}
+
+		cur, action := find(byName, want)
+		switch {
+		case cur == nil:
+			_, err := cli.CreateLabel(project, opts)
+			record(want.Name, ActionCreate, err)
+
+		case action == ActionRename || !same(cur, &opts):
+			_, err := cli.UpdateLabel(project, cur.Name, opts)
+			record(want.Name, action, err)
+		}
+	}
+
+	if s.Prune {
+		for _, l := range byName {
+			record(l.Name, ActionDelete, cli.DeleteLabel(project, l.Name))
+		}
+	}
+
+	return changes, errors.Join(errs...)
+}
+
+// find returns the existing label of the label of the scheme, and the
+// action to take if it differs, and removes it from byName. The one of a
+// former name is to be renamed.
+func find(byName map[string]*gitlab.Label, want *Label) (*gitlab.Label, string) {
+	k := strings.ToLower(want.Name)
+	if l, ok := byName[k]; ok {
+		delete(byName, k)
+
+		if l.Name != want.Name {
+			return l, ActionRename
+		}
+
+		return l, ActionUpdate
+	}
+
+	for _, n := range want.Previously {
+		k := strings.ToLower(n)
+		if l, ok := byName[k]; ok {
+			delete(byName, k)
+
+			return l, ActionRename
+		}
+	}
+
+	return nil, ActionCreate
+}
+
+func same(l *gitlab.Label, opts *client.LabelOptions) bool {
+	return strings.EqualFold(l.Color, opts.Color) && l.Description == opts.Description
+}
+
+// Task returns the periodic task reconciling the labels of the scheme,
+// which logs the changes it makes.
+func Task(cli client.Interface, s *Scheme) framework.PeriodicTask {
+	return func(ctx context.Context, log *logrus.Entry) error {
+		changes, err := Sync(cli, s)
+
+		for _, c := range changes {
+			log.WithFields(logrus.Fields{
+				"project": c.Project,
+				"label":   c.Label,
+				"action":  c.Action,
+			}).Info("synced the label")
+		}
+
+		return err
+	}
+}
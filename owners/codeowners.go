@@ -0,0 +1,372 @@
+// Package owners finds the owners of the files of a repository, by the
+// CODEOWNERS file of GitLab or the OWNERS files of Kubernetes, and
+// suggests the reviewers of the merge requests by them.
+package owners
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/opensourceways/robot-gitlab-lib/client"
+)
+
+// CodeOwnersPaths are the paths GitLab looks for the CODEOWNERS file at,
+// in the order of precedence.
+var CodeOwnersPaths = []string{"CODEOWNERS", "docs/CODEOWNERS", ".gitlab/CODEOWNERS"}
+
+// CodeOwners is a parsed CODEOWNERS file.
+type CodeOwners struct {
+	// Sections are in the order of the file. The entries before any
+	// section header are in the one with the empty name.
+	Sections []*Section
+}
+
+// Section is a section of a CODEOWNERS file, such as:
+//
+//	^[Documentation][2] @docs-team
+//
+// which is optional, requires 2 approvals, and has the default owner
+// @docs-team.
+type Section struct {
+	Name string
+
+	// Optional sections do not require approvals.
+	Optional bool
+
+	// Approvals is the number of approvals required, which is 1 if not
+	// specified.
+	Approvals int
+
+	// DefaultOwners are the owners of the entries without owners.
+	DefaultOwners []string
+
+	Entries []*Entry
+}
+
+// Entry is a pattern of paths and its owners.
+type Entry struct {
+	Pattern string
+
+	// Owners are the users and the groups, starting with @, and the
+	// emails as they are written.
+	Owners []string
+
+	re *regexp.Regexp
+}
+
+var sectionHeader = regexp.MustCompile(`^(\^)?\[([^\]]+)\](?:\[(\d+)\])?(.*)$`)
+
+// ParseCodeOwners parses the content of a CODEOWNERS file.
+func ParseCodeOwners(data []byte) (*CodeOwners, error) {
+	v := &CodeOwners{}
+	sec := &Section{Approvals: 1}
+
+	for n, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if m := sectionHeader.FindStringSubmatch(line); m != nil {
+			if sec.Name != "" || len(sec.Entries) > 0 {
+				v.Sections = append(v.Sections, sec)
+			}
+
+			sec = &Section{
+				Name:          m[2],
+				Optional:      m[1] != "",
+				Approvals:     1,
+				DefaultOwners: strings.Fields(stripComment(m[4])),
+			}
+
+			if m[3] != "" {
+				sec.Approvals, _ = strconv.Atoi(m[3])
+			}
+
+			continue
+		}
+
+		pattern, owners := splitEntry(line)
+
+		re, err := compilePattern(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n+1, err)
+		}
+
+		sec.Entries = append(sec.Entries, &Entry{Pattern: pattern, Owners: owners, re: re})
+	}
+
+	if sec.Name != "" || len(sec.Entries) > 0 {
+		v.Sections = append(v.Sections, sec)
+	}
+
+	return v, nil
+}
+
+// splitEntry splits the line of an entry into the pattern, in which the
+// spaces and # can be escaped by \, and the owners.
+func splitEntry(line string) (string, []string) {
+	var b strings.Builder
+
+	i := 0
+	for ; i < len(line); i++ {
+		c := line[i]
+		if c == '\\' && i+1 < len(line) {
+			i++
+			b.WriteByte(line[i])
+
+			continue
+		}
+
+		if c == ' ' || c == '\t' {
+			break
+		}
+
+		b.WriteByte(c)
+	}
+
+	return b.String(), strings.Fields(stripComment(line[i:]))
+}
+
+func stripComment(s string) string {
+	if i := strings.Index(s, "#"); i >= 0 {
+		return s[:i]
+	}
+
+	return s
+}
+
+// compilePattern compiles the pattern of a CODEOWNERS entry. The patterns
+// starting with / match from the root, and the others at any directory.
+// The ones ending with / match all the files under the directories. A **/
+// matches any directories including none, and a trailing ** matches
+// everything.
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, fmt.Errorf("empty pattern")
+	}
+
+	var b strings.Builder
+	b.WriteString("^")
+
+	p := pattern
+	if strings.HasPrefix(p, "/") {
+		p = p[1:]
+	} else {
+		b.WriteString("(?:.*/)?")
+	}
+
+	dir := strings.HasSuffix(p, "/")
+	p = strings.TrimSuffix(p, "/")
+
+	for i := 0; i < len(p); i++ {
+		switch c := p[i]; c {
+		case '*':
+			switch {
+			case strings.HasPrefix(p[i:], "**/"):
+				b.WriteString("(?:.*/)?")
+				i += 2
+
+			case p[i:] == "**":
+				b.WriteString(".*")
+				i++
+
+			default:
+				b.WriteString("[^/]*")
+			}
+
+		case '?':
+			b.WriteString("[^/]")
+
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	if dir {
+		b.WriteString("/.*$")
+	} else {
+		b.WriteString("(?:/.*)?$")
+	}
+
+	return regexp.Compile(b.String())
+}
+
+// Match returns the entry matching the path in the section, which is the
+// last one matching it, or nil.
+func (s *Section) Match(path string) *Entry {
+	path = strings.TrimPrefix(path, "/")
+
+	for i := len(s.Entries) - 1; i >= 0; i-- {
+		if s.Entries[i].re.MatchString(path) {
+			return s.Entries[i]
+		}
+	}
+
+	return nil
+}
+
+// owners returns the owners of the entry in the section.
+func (s *Section) owners(e *Entry) []string {
+	if len(e.Owners) > 0 {
+		return e.Owners
+	}
+
+	return s.DefaultOwners
+}
+
+// Owners returns the owners of the path by all the sections.
+func (c *CodeOwners) Owners(path string) []string {
+	var r []string
+
+	for _, s := range c.Sections {
+		if e := s.Match(path); e != nil {
+			for _, o := range s.owners(e) {
+				if !slices.Contains(r, o) {
+					r = append(r, o)
+				}
+			}
+		}
+	}
+
+	return r
+}
+
+// Suggestion is a reviewer suggested for the changed files.
+type Suggestion struct {
+	// Owner is the user or the group, starting with @, or the email.
+	Owner string
+
+	// Files is the number of the files the owner owns.
+	Files int
+
+	// Required is true if the owner is of a section requiring approvals.
+	Required bool
+}
+
+// SuggestReviewers returns the owners of the files, the ones of the
+// required sections first and then the ones owning more files. The
+// excluded owners, such as the author, are left out, with or without @.
+func (c *CodeOwners) SuggestReviewers(files []string, exclude ...string) []Suggestion {
+	skip := map[string]bool{}
+	for _, v := range exclude {
+		skip["@"+strings.TrimPrefix(v, "@")] = true
+		skip[v] = true
+	}
+
+	byOwner := map[string]*Suggestion{}
+
+	var r []*Suggestion
+
+	for _, f := range files {
+		seen := map[string]bool{}
+
+		for _, s := range c.Sections {
+			e := s.Match(f)
+			if e == nil {
+				continue
+			}
+
+			for _, o := range s.owners(e) {
+				if skip[o] {
+					continue
+				}
+
+				v, ok := byOwner[o]
+				if !ok {
+					v = &Suggestion{Owner: o}
+					byOwner[o] = v
+					r = append(r, v)
+				}
+
+				v.Required = v.Required || (!s.Optional && s.Approvals > 0)
+
+				if !seen[o] {
+					seen[o] = true
+					v.Files++
+				}
+			}
+		}
+	}
+
+	slices.SortStableFunc(r, func(a, b *Suggestion) int {
+		switch {
+		case a.Required != b.Required:
+			if a.Required {
+				return -1
+			}
+
+			return 1
+
+		case a.Files != b.Files:
+			return b.Files - a.Files
+
+		default:
+			return strings.Compare(a.Owner, b.Owner)
+		}
+	})
+
+	v := make([]Suggestion, len(r))
+	for i, s := range r {
+		v[i] = *s
+	}
+
+	return v
+}
+
+// LoadCodeOwners reads and parses the CODEOWNERS file of the repository at
+// ref, which is the first one found at CodeOwnersPaths. It returns nil if
+// there is none.
+func LoadCodeOwners(cli client.Interface, pid interface{}, ref string) (*CodeOwners, error) {
+	for _, p := range CodeOwnersPaths {
+		b, err := cli.GetPathContent(pid, p, ref)
+		if err != nil {
+			if client.IsNotFound(err) {
+				continue
+			}
+
+			return nil, err
+		}
+
+		return ParseCodeOwners(b)
+	}
+
+	return nil, nil
+}
+
+// SuggestMRReviewers returns the reviewers of the merge request suggested
+// by the CODEOWNERS file at ref, usually the target branch, for its
+// changed files. The renamed files count for both the paths. It returns
+// nil if there is no CODEOWNERS file.
+func SuggestMRReviewers(cli client.Interface, pid interface{}, iid int, ref, author string) ([]Suggestion, error) {
+	c, err := LoadCodeOwners(cli, pid, ref)
+	if err != nil || c == nil {
+		return nil, err
+	}
+
+	changes, err := cli.GetMRChangedFiles(pid, iid)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.SuggestReviewers(changedPaths(changes), author), nil
+}
+
+// changedPaths returns the paths of the changed files, including the old
+// paths of the renamed ones.
+func changedPaths(changes []client.ChangedFile) []string {
+	r := make([]string, 0, len(changes))
+
+	for i := range changes {
+		r = append(r, changes[i].Path)
+
+		if changes[i].Renamed && changes[i].OldPath != changes[i].Path {
+			r = append(r, changes[i].OldPath)
+		}
+	}
+
+	return r
+}
@@ -0,0 +1,149 @@
+package owners
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCompilePattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		match   []string
+		noMatch []string
+	}{
+		{
+			pattern: "/README.md",
+			match:   []string{"README.md"},
+			noMatch: []string{"docs/README.md", "README.mdx"},
+		},
+		{
+			pattern: "README.md",
+			match:   []string{"README.md", "docs/README.md", "a/b/README.md"},
+			noMatch: []string{"README.mdx", "xREADME.md"},
+		},
+		{
+			pattern: "*.go",
+			match:   []string{"main.go", "cmd/robot/main.go"},
+			noMatch: []string{"main.golang", "go.mod"},
+		},
+		{
+			pattern: "/docs/",
+			match:   []string{"docs/index.md", "docs/guide/intro.md"},
+			noMatch: []string{"docs", "api/docs/index.md", "docsx/index.md"},
+		},
+		{
+			pattern: "docs/",
+			match:   []string{"docs/index.md", "api/docs/index.md"},
+			noMatch: []string{"docs", "api/docsx/index.md"},
+		},
+		{
+			pattern: "/docs/*.md",
+			match:   []string{"docs/index.md"},
+			noMatch: []string{"docs/guide/intro.md", "docs/index.txt"},
+		},
+		{
+			pattern: "/docs/**/*.md",
+			match:   []string{"docs/index.md", "docs/guide/intro.md", "docs/a/b/c.md"},
+			noMatch: []string{"docs/index.txt", "api/docs/index.md"},
+		},
+		{
+			pattern: "**/test/",
+			match:   []string{"test/a.go", "pkg/test/a.go", "a/b/test/c/d.go"},
+			noMatch: []string{"pkg/testdata/a.go"},
+		},
+		{
+			pattern: "/config/**",
+			match:   []string{"config/a.yaml", "config/a/b.yaml"},
+			noMatch: []string{"configs/a.yaml"},
+		},
+		{
+			pattern: "/src/**.go",
+			match:   []string{"src/main.go"},
+			noMatch: []string{"src/pkg/main.go"},
+		},
+		{
+			pattern: "/file?.txt",
+			match:   []string{"file1.txt"},
+			noMatch: []string{"file10.txt", "file/.txt"},
+		},
+		{
+			pattern: "/My Docs/",
+			match:   []string{"My Docs/a.md"},
+			noMatch: []string{"MyDocs/a.md"},
+		},
+		{
+			pattern: "/a+b(c).md",
+			match:   []string{"a+b(c).md"},
+			noMatch: []string{"aab(c).md", "abc.md"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			re, err := compilePattern(tt.pattern)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, v := range tt.match {
+				if !re.MatchString(v) {
+					t.Errorf("%s: got no match, want match", v)
+				}
+			}
+
+			for _, v := range tt.noMatch {
+				if re.MatchString(v) {
+					t.Errorf("%s: got match, want no match", v)
+				}
+			}
+		})
+	}
+}
+
+func TestParseCodeOwners(t *testing.T) {
+	data := `# The default owners.
+* @alice
+
+/docs/ @docs-team # the docs
+My\ Docs/ @bob
+internal/\#private/ @carol
+
+^[Security][2] @security
+*.go
+/vendor/ @dave
+`
+
+	c, err := ParseCodeOwners([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n := len(c.Sections); n != 2 {
+		t.Fatalf("got %d sections, want 2", n)
+	}
+
+	if s := c.Sections[1]; s.Name != "Security" || !s.Optional || s.Approvals != 2 {
+		t.Errorf("got section %+v", s)
+	}
+
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{path: "README.md", want: []string{"@alice"}},
+		{path: "docs/index.md", want: []string{"@docs-team"}},
+		{path: "/docs/index.md", want: []string{"@docs-team"}},
+		{path: "api/My Docs/a.md", want: []string{"@bob"}},
+		{path: "internal/#private/a.txt", want: []string{"@carol"}},
+		{path: "cmd/main.go", want: []string{"@alice", "@security"}},
+		{path: "vendor/lib/lib.go", want: []string{"@alice", "@dave"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := c.Owners(tt.path); !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
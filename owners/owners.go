@@ -0,0 +1,343 @@
+package owners
+
+import (
+	"fmt"
+	"maps"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/opensourceways/robot-gitlab-lib/client"
+)
+
+// The names of the files of the Kubernetes style owners.
+const (
+	OwnersFileName   = "OWNERS"
+	AliasesFileName  = "OWNERS_ALIASES"
+	defaultCacheSize = 64
+)
+
+// OwnersConfig is the approvers and the reviewers of an OWNERS file, or of
+// a filter of it.
+type OwnersConfig struct {
+	Approvers         []string `yaml:"approvers,omitempty"`
+	Reviewers         []string `yaml:"reviewers,omitempty"`
+	RequiredReviewers []string `yaml:"required_reviewers,omitempty"`
+	Labels            []string `yaml:"labels,omitempty"`
+}
+
+// OwnersFile is an OWNERS file.
+type OwnersFile struct {
+	OwnersConfig `yaml:",inline"`
+
+	Options struct {
+		// NoParentOwners stops inheriting the owners of the parent
+		// directories.
+		NoParentOwners bool `yaml:"no_parent_owners,omitempty"`
+	} `yaml:"options,omitempty"`
+
+	// Filters are the owners of the files whose paths, relative to the
+	// directory of the OWNERS file, match the regular expressions, in
+	// addition to the ones of the top level. They apply in the order of
+	// the expressions.
+	Filters map[string]OwnersConfig `yaml:"filters,omitempty"`
+
+	filters []ownersFilter
+}
+
+type ownersFilter struct {
+	re  *regexp.Regexp
+	cfg *OwnersConfig
+}
+
+// ParseOwners parses the content of an OWNERS file.
+func ParseOwners(data []byte) (*OwnersFile, error) {
+	v := new(OwnersFile)
+	if err := yaml.Unmarshal(data, v); err != nil {
+		return nil, err
+	}
+
+	for _, expr := range slices.Sorted(maps.Keys(v.Filters)) {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("filter %q: %w", expr, err)
+		}
+
+		cfg := v.Filters[expr]
+		v.filters = append(v.filters, ownersFilter{re: re, cfg: &cfg})
+	}
+
+	return v, nil
+}
+
+// configs returns the configs applying to the file of the path, which is
+// relative to the directory of the OWNERS file. The top level applies to all the
+// files as if it is the filter of .*.
+func (f *OwnersFile) configs(file string) []*OwnersConfig {
+	r := []*OwnersConfig{&f.OwnersConfig}
+
+	for _, v := range f.filters {
+		if v.re.MatchString(file) {
+			r = append(r, v.cfg)
+		}
+	}
+
+	return r
+}
+
+// Aliases are the aliases of the groups of the users in OWNERS_ALIASES.
+type Aliases map[string][]string
+
+// ParseAliases parses the content of an OWNERS_ALIASES file.
+func ParseAliases(data []byte) (Aliases, error) {
+	var v struct {
+		Aliases Aliases `yaml:"aliases"`
+	}
+
+	if err := yaml.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+
+	return v.Aliases, nil
+}
+
+// Expand returns the users with the aliases replaced by their members. The
+// users are lowercased, as GitLab usernames are case insensitive.
+func (a Aliases) Expand(users []string) []string {
+	var r []string
+
+	add := func(u string) {
+		if u = strings.ToLower(u); !slices.Contains(r, u) {
+			r = append(r, u)
+		}
+	}
+
+	for _, u := range users {
+		if m, ok := a[u]; ok {
+			for _, v := range m {
+				add(v)
+			}
+		} else {
+			add(u)
+		}
+	}
+
+	return r
+}
+
+// Owners is the owners of a file, or of a set of files.
+type Owners struct {
+	Approvers         []string
+	Reviewers         []string
+	RequiredReviewers []string
+	Labels            []string
+}
+
+func (o *Owners) add(aliases Aliases, cfg *OwnersConfig) {
+	merge := func(dst *[]string, v []string) {
+		for _, u := range aliases.Expand(v) {
+			if !slices.Contains(*dst, u) {
+				*dst = append(*dst, u)
+			}
+		}
+	}
+
+	merge(&o.Approvers, cfg.Approvers)
+	merge(&o.Reviewers, cfg.Reviewers)
+	merge(&o.RequiredReviewers, cfg.RequiredReviewers)
+
+	for _, l := range cfg.Labels {
+		if !slices.Contains(o.Labels, l) {
+			o.Labels = append(o.Labels, l)
+		}
+	}
+}
+
+// RepoOwners are the owners of the files of a repository at a commit. The
+// OWNERS files are read on demand and kept, so that it is cheap to look up
+// the owners of the files again.
+type RepoOwners struct {
+	cli     client.Interface
+	pid     interface{}
+	sha     string
+	aliases Aliases
+
+	mu    sync.Mutex
+	files map[string]*OwnersFile
+}
+
+// ownersFile returns the OWNERS file of the directory, or nil if there is
+// none.
+func (r *RepoOwners) ownersFile(dir string) (*OwnersFile, error) {
+	r.mu.Lock()
+	f, ok := r.files[dir]
+	r.mu.Unlock()
+
+	if ok {
+		return f, nil
+	}
+
+	b, err := r.cli.GetPathContent(r.pid, path.Join(dir, OwnersFileName), r.sha)
+	switch {
+	case err == nil:
+		if f, err = ParseOwners(b); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", path.Join(dir, OwnersFileName), err)
+		}
+
+	case !client.IsNotFound(err):
+		return nil, err
+	}
+
+	r.mu.Lock()
+	r.files[dir] = f
+	r.mu.Unlock()
+
+	return f, nil
+}
+
+// Owners returns the owners of the file of the path, which are the ones of
+// the OWNERS files of its directory and all the parents, until one of them
+// sets no_parent_owners.
+func (r *RepoOwners) Owners(file string) (Owners, error) {
+	file = strings.TrimPrefix(path.Clean("/"+file), "/")
+
+	var v Owners
+
+	for dir := path.Dir(file); ; dir = path.Dir(dir) {
+		if dir == "." {
+			dir = ""
+		}
+
+		f, err := r.ownersFile(dir)
+		if err != nil {
+			return Owners{}, err
+		}
+
+		if f != nil {
+			rel := file
+			if dir != "" {
+				rel = strings.TrimPrefix(file, dir+"/")
+			}
+
+			for _, cfg := range f.configs(rel) {
+				v.add(r.aliases, cfg)
+			}
+
+			if f.Options.NoParentOwners {
+				break
+			}
+		}
+
+		if dir == "" {
+			break
+		}
+	}
+
+	return v, nil
+}
+
+// OwnersOf returns the owners of all the files.
+func (r *RepoOwners) OwnersOf(files []string) (Owners, error) {
+	var v Owners
+
+	for _, f := range files {
+		o, err := r.Owners(f)
+		if err != nil {
+			return Owners{}, err
+		}
+
+		v.add(nil, &OwnersConfig{
+			Approvers:         o.Approvers,
+			Reviewers:         o.Reviewers,
+			RequiredReviewers: o.RequiredReviewers,
+			Labels:            o.Labels,
+		})
+	}
+
+	return v, nil
+}
+
+// OwnersCache keeps the owners of the recent commits of the repositories.
+// It is safe for concurrent use.
+type OwnersCache struct {
+	cli  client.Interface
+	size int
+
+	mu    sync.Mutex
+	repos map[string]*RepoOwners
+
+	// keys are the keys of repos from the least recently used.
+	keys []string
+}
+
+// NewOwnersCache returns the cache of the owners of at most size commits,
+// which defaults to 64 if size is not positive.
+func NewOwnersCache(cli client.Interface, size int) *OwnersCache {
+	if size <= 0 {
+		size = defaultCacheSize
+	}
+
+	return &OwnersCache{
+		cli:   cli,
+		size:  size,
+		repos: map[string]*RepoOwners{},
+	}
+}
+
+// Load returns the owners of the repository at the commit. Pass a commit
+// SHA rather than a branch, since the owners are kept by it.
+func (c *OwnersCache) Load(pid interface{}, sha string) (*RepoOwners, error) {
+	key := fmt.Sprintf("%v@%s", pid, sha)
+
+	c.mu.Lock()
+	if r, ok := c.repos[key]; ok {
+		c.touch(key)
+		c.mu.Unlock()
+
+		return r, nil
+	}
+	c.mu.Unlock()
+
+	r := &RepoOwners{cli: c.cli, pid: pid, sha: sha, files: map[string]*OwnersFile{}}
+
+	b, err := c.cli.GetPathContent(pid, AliasesFileName, sha)
+	switch {
+	case err == nil:
+		if r.aliases, err = ParseAliases(b); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", AliasesFileName, err)
+		}
+
+	case !client.IsNotFound(err):
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if v, ok := c.repos[key]; ok {
+		c.touch(key)
+
+		return v, nil
+	}
+
+	c.repos[key] = r
+	c.keys = append(c.keys, key)
+
+	if len(c.keys) > c.size {
+		delete(c.repos, c.keys[0])
+		c.keys = c.keys[1:]
+	}
+
+	return r, nil
+}
+
+// touch marks the key as the most recently used.
+func (c *OwnersCache) touch(key string) {
+	if i := slices.Index(c.keys, key); i >= 0 {
+		c.keys = append(append(c.keys[:i:i], c.keys[i+1:]...), key)
+	}
+}
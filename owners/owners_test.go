@@ -0,0 +1,158 @@
+package owners
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/xanzy/go-gitlab"
+
+	"github.com/opensourceways/robot-gitlab-lib/client/fake"
+)
+
+func TestParseOwners(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		file    string
+		want    []OwnersConfig
+		wantErr bool
+	}{
+		{
+			name: "top level",
+			data: "approvers: [alice]\nreviewers: [bob]\nlabels: [sig/infra]\n",
+			file: "main.go",
+			want: []OwnersConfig{{Approvers: []string{"alice"}, Reviewers: []string{"bob"}, Labels: []string{"sig/infra"}}},
+		},
+		{
+			name: "filter matched",
+			data: "approvers: [alice]\nfilters:\n  \"\\\\.md$\":\n    reviewers: [carol]\n",
+			file: "docs/README.md",
+			want: []OwnersConfig{{Approvers: []string{"alice"}}, {Reviewers: []string{"carol"}}},
+		},
+		{
+			name: "filter not matched",
+			data: "approvers: [alice]\nfilters:\n  \"\\\\.md$\":\n    reviewers: [carol]\n",
+			file: "main.go",
+			want: []OwnersConfig{{Approvers: []string{"alice"}}},
+		},
+		{
+			name: "filters in order",
+			data: "filters:\n  \"b\":\n    reviewers: [bob]\n  \"a\":\n    reviewers: [alice]\n  \"c\":\n    reviewers: [carol]\n",
+			file: "abc.go",
+			want: []OwnersConfig{{}, {Reviewers: []string{"alice"}}, {Reviewers: []string{"bob"}}, {Reviewers: []string{"carol"}}},
+		},
+		{name: "empty", data: "", file: "main.go", want: []OwnersConfig{{}}},
+		{name: "invalid yaml", data: "approvers: [alice", wantErr: true},
+		{name: "invalid filter", data: "filters:\n  \"[\":\n    approvers: [alice]\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := ParseOwners([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+
+			if err != nil {
+				return
+			}
+
+			var got []OwnersConfig
+			for _, v := range f.configs(tt.file) {
+				got = append(got, *v)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAliasesExpand(t *testing.T) {
+	aliases, err := ParseAliases([]byte("aliases:\n  sig-infra: [Alice, bob]\n  sig-docs: [bob, carol]\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		users []string
+		want  []string
+	}{
+		{name: "none", users: nil, want: nil},
+		{name: "users", users: []string{"Dave", "eve"}, want: []string{"dave", "eve"}},
+		{name: "alias", users: []string{"sig-infra"}, want: []string{"alice", "bob"}},
+		{name: "overlapping", users: []string{"sig-infra", "sig-docs", "alice"}, want: []string{"alice", "bob", "carol"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := aliases.Expand(tt.users); !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRepoOwners(t *testing.T) {
+	files := map[string]string{
+		OwnersFileName:             "approvers: [sig-infra]\nreviewers: [dave]\n",
+		AliasesFileName:            "aliases:\n  sig-infra: [alice, bob]\n",
+		"docs/" + OwnersFileName:   "approvers: [carol]\nlabels: [kind/docs]\nfilters:\n  \"^guide/\":\n    reviewers: [frank]\n",
+		"vendor/" + OwnersFileName: "options:\n  no_parent_owners: true\napprovers: [eve]\n",
+	}
+
+	cli := &fake.Client{
+		GetPathContentFunc: func(_ interface{}, path, _ string) ([]byte, error) {
+			if v, ok := files[path]; ok {
+				return []byte(v), nil
+			}
+
+			return nil, gitlab.ErrNotFound
+		},
+	}
+
+	tests := []struct {
+		file string
+		want Owners
+	}{
+		{
+			file: "main.go",
+			want: Owners{Approvers: []string{"alice", "bob"}, Reviewers: []string{"dave"}},
+		},
+		{
+			file: "docs/guide/intro.md",
+			want: Owners{
+				Approvers: []string{"carol", "alice", "bob"}, Reviewers: []string{"frank", "dave"}, Labels: []string{"kind/docs"},
+			},
+		},
+		{
+			file: "docs/api.md",
+			want: Owners{Approvers: []string{"carol", "alice", "bob"}, Reviewers: []string{"dave"}, Labels: []string{"kind/docs"}},
+		},
+		{
+			file: "/vendor/lib/lib.go",
+			want: Owners{Approvers: []string{"eve"}},
+		},
+	}
+
+	r, err := NewOwnersCache(cli, 0).Load("opensourceways/robot-test", "abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			got, err := r.Owners(tt.file)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
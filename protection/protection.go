@@ -0,0 +1,488 @@
+// Package protection keeps the protected branches and their approval rules
+// of the projects consistent with a policy, and reports the drift from it.
+package protection
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/xanzy/go-gitlab"
+
+	"github.com/opensourceways/robot-gitlab-lib/client"
+	"github.com/opensourceways/robot-gitlab-lib/framework"
+)
+
+// Role is the minimum role allowed to do something to a protected branch,
+// which is one of no_one, developer, maintainer and admin in the policy.
+type Role gitlab.AccessLevelValue
+
+var roleNames = map[string]Role{
+	"no_one":     Role(gitlab.NoPermissions),
+	"developer":  Role(gitlab.DeveloperPermissions),
+	"maintainer": Role(gitlab.MaintainerPermissions),
+	"admin":      Role(gitlab.AdminPermissions),
+}
+
+func (r Role) String() string {
+	for k, v := range roleNames {
+		if v == r {
+			return k
+		}
+	}
+
+	return fmt.Sprintf("access level %d", int(r))
+}
+
+func (r Role) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.String())
+}
+
+func (r *Role) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+
+	v, ok := roleNames[s]
+	if !ok {
+		return fmt.Errorf("unknown role %q", s)
+	}
+
+	*r = v
+
+	return nil
+}
+
+func (r Role) level() *gitlab.AccessLevelValue {
+	return gitlab.Ptr(gitlab.AccessLevelValue(r))
+}
+
+// ApprovalRule is the approval rule of the project applying to the merge
+// requests targeting a protected branch.
+type ApprovalRule struct {
+	// Name defaults to the one of the branch.
+	Name     string   `json:"name,omitempty"`
+	Required int      `json:"required"`
+	Users    []string `json:"users,omitempty"`
+	GroupIDs []int    `json:"group_ids,omitempty"`
+}
+
+// Rule is the protection of a branch.
+type Rule struct {
+	// Branch is the name of the branch, or a wildcard such as release-*.
+	Branch string `json:"branch"`
+
+	// Push, Merge and Unprotect are the minimum roles allowed to push to,
+	// merge into and unprotect the branch. They default to maintainer.
+	Push      *Role `json:"push,omitempty"`
+	Merge     *Role `json:"merge,omitempty"`
+	Unprotect *Role `json:"unprotect,omitempty"`
+
+	AllowForcePush            bool `json:"allow_force_push,omitempty"`
+	CodeOwnerApprovalRequired bool `json:"code_owner_approval_required,omitempty"`
+
+	// Approvals, if not nil, is the approval rule of the branch.
+	Approvals *ApprovalRule `json:"approvals,omitempty"`
+}
+
+func role(r *Role) Role {
+	if r == nil {
+		return Role(gitlab.MaintainerPermissions)
+	}
+
+	return *r
+}
+
+func (r *Rule) approvalRuleName() string {
+	if r.Approvals.Name != "" {
+		return r.Approvals.Name
+	}
+
+	return r.Branch
+}
+
+// Policy is the protected branches of the projects.
+type Policy struct {
+	Rules []Rule `json:"rules"`
+
+	// Projects and Groups are the full paths of the projects, and the
+	// groups of which all the projects including the ones of the
+	// subgroups, the policy applies to.
+	Projects []string `json:"projects,omitempty"`
+	Groups   []string `json:"groups,omitempty"`
+
+	// Enforce fixes the drift. It is only reported if false.
+	Enforce bool `json:"enforce,omitempty"`
+}
+
+// Validate checks the policy.
+func (p *Policy) Validate() error {
+	seen := map[string]bool{}
+
+	for i := range p.Rules {
+		r := &p.Rules[i]
+		if r.Branch == "" {
+			return fmt.Errorf("missing branch of the rule %d", i)
+		}
+
+		if seen[r.Branch] {
+			return fmt.Errorf("duplicate rule of branch %s", r.Branch)
+		}
+
+		seen[r.Branch] = true
+
+		if r.Approvals != nil && r.Approvals.Required < 0 {
+			return fmt.Errorf("invalid approvals required of branch %s", r.Branch)
+		}
+	}
+
+	return nil
+}
+
+// covers reports whether the project is one of the policy, or of its
+// groups.
+func (p *Policy) covers(project string) bool {
+	if slices.Contains(p.Projects, project) {
+		return true
+	}
+
+	for _, g := range p.Groups {
+		if strings.HasPrefix(project, strings.TrimSuffix(g, "/")+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Drift is a difference of a project from the policy.
+type Drift struct {
+	Project string `json:"project"`
+	Branch  string `json:"branch"`
+
+	// Field is what differs, such as push or approvals_required.
+	Field string `json:"field"`
+	Want  string `json:"want"`
+	Got   string `json:"got"`
+
+	// Fixed is true if the drift has been fixed.
+	Fixed bool `json:"fixed"`
+}
+
+// Reconcile checks all the projects of the policy, and fixes the drift if
+// the policy is enforced. It goes on with the other projects if it fails
+// on one, and returns the drift found and all the errors.
+func Reconcile(cli client.Interface, p *Policy) ([]Drift, error) {
+	projects, err := client.ExpandProjects(cli, p.Projects, p.Groups)
+
+	var drift []Drift
+
+	errs := []error{err}
+	for _, project := range projects {
+		v, err := ReconcileProject(cli, project, p)
+		drift = append(drift, v...)
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("reconcile the protected branches of %s: %w", project, err))
+		}
+	}
+
+	return drift, errors.Join(errs...)
+}
+
+// ReconcileProject checks the project, and fixes the drift if the policy
+// is enforced. A protected branch differing from its rule is updated in
+// place, keeping the access of the users, the groups and the deploy keys.
+// The projects of the policy are ignored.
+func ReconcileProject(cli client.Interface, project string, p *Policy) ([]Drift, error) {
+	protected, err := cli.ListProtectedBranches(project)
+	if err != nil {
+		return nil, err
+	}
+
+	var approvalRules []*gitlab.ProjectApprovalRule
+	if slices.ContainsFunc(p.Rules, func(r Rule) bool { return r.Approvals != nil }) {
+		if approvalRules, err = cli.ListProjectApprovalRules(project); err != nil {
+			return nil, err
+		}
+	}
+
+	var (
+		drift []Drift
+		errs  []error
+	)
+
+	for i := range p.Rules {
+		r := &p.Rules[i]
+
+		pb := findProtectedBranch(protected, r.Branch)
+
+		v := branchDrift(pb, r)
+		for j := range v {
+			v[j].Project = project
+		}
+
+		if len(v) > 0 && p.Enforce {
+			if pb, err = protect(cli, project, pb, r); err != nil {
+				errs = append(errs, fmt.Errorf("protect branch %s: %w", r.Branch, err))
+			} else {
+				markFixed(v)
+			}
+		}
+
+		drift = append(drift, v...)
+
+		if r.Approvals == nil {
+			continue
+		}
+
+		ar := findApprovalRule(approvalRules, r.approvalRuleName())
+
+		v = approvalDrift(ar, pb, r)
+		for j := range v {
+			v[j].Project = project
+		}
+
+		if len(v) > 0 && p.Enforce && pb != nil {
+			if err := ensureApprovalRule(cli, project, ar, pb, r); err != nil {
+				errs = append(errs, fmt.Errorf("ensure the approval rule of branch %s: %w", r.Branch, err))
+			} else {
+				markFixed(v)
+			}
+		}
+
+		drift = append(drift, v...)
+	}
+
+	return drift, errors.Join(errs...)
+}
+
+func markFixed(v []Drift) {
+	for i := range v {
+		v[i].Fixed = true
+	}
+}
+
+func findProtectedBranch(v []*gitlab.ProtectedBranch, branch string) *gitlab.ProtectedBranch {
+	for _, pb := range v {
+		if pb.Name == branch {
+			return pb
+		}
+	}
+
+	return nil
+}
+
+func findApprovalRule(v []*gitlab.ProjectApprovalRule, name string) *gitlab.ProjectApprovalRule {
+	for _, ar := range v {
+		if ar.Name == name {
+			return ar
+		}
+	}
+
+	return nil
+}
+
+// roleOf returns the role of the access levels, ignoring the ones of the
+// users, the groups and the deploy keys.
+func roleOf(v []*gitlab.BranchAccessDescription) Role {
+	for _, d := range v {
+		if d.UserID == 0 && d.GroupID == 0 && d.DeployKeyID == 0 {
+			return Role(d.AccessLevel)
+		}
+	}
+
+	return Role(gitlab.NoPermissions)
+}
+
+func branchDrift(pb *gitlab.ProtectedBranch, r *Rule) []Drift {
+	if pb == nil {
+		return []Drift{{Branch: r.Branch, Field: "protected", Want: "true", Got: "false"}}
+	}
+
+	var v []Drift
+
+	diff := func(field string, want, got interface{}) {
+		if w, g := fmt.Sprint(want), fmt.Sprint(got); w != g {
+			v = append(v, Drift{Branch: r.Branch, Field: field, Want: w, Got: g})
+		}
+	}
+
+	diff("push", role(r.Push), roleOf(pb.PushAccessLevels))
+	diff("merge", role(r.Merge), roleOf(pb.MergeAccessLevels))
+	diff("unprotect", role(r.Unprotect), roleOf(pb.UnprotectAccessLevels))
+	diff("allow_force_push", r.AllowForcePush, pb.AllowForcePush)
+	diff("code_owner_approval_required", r.CodeOwnerApprovalRequired, pb.CodeOwnerApprovalRequired)
+
+	return v
+}
+
+func approvalDrift(ar *gitlab.ProjectApprovalRule, pb *gitlab.ProtectedBranch, r *Rule) []Drift {
+	name := r.approvalRuleName()
+	if ar == nil {
+		return []Drift{{Branch: r.Branch, Field: "approval_rule", Want: name, Got: ""}}
+	}
+
+	var v []Drift
+
+	diff := func(field, want, got string) {
+		if want != got {
+			v = append(v, Drift{Branch: r.Branch, Field: field, Want: want, Got: got})
+		}
+	}
+
+	diff("approvals_required", fmt.Sprint(r.Approvals.Required), fmt.Sprint(ar.ApprovalsRequired))
+
+	users := make([]string, len(ar.Users))
+	for i, u := range ar.Users {
+		users[i] = u.Username
+	}
+
+	diff("approvers", sortedList(r.Approvals.Users), sortedList(users))
+
+	groups := make([]string, len(ar.Groups))
+	for i, g := range ar.Groups {
+		groups[i] = fmt.Sprint(g.ID)
+	}
+
+	want := make([]string, len(r.Approvals.GroupIDs))
+	for i, id := range r.Approvals.GroupIDs {
+		want[i] = fmt.Sprint(id)
+	}
+
+	diff("approver_groups", sortedList(want), sortedList(groups))
+
+	applies := pb != nil && slices.ContainsFunc(ar.ProtectedBranches, func(b *gitlab.ProtectedBranch) bool {
+		return b.ID == pb.ID
+	})
+	diff("approval_rule_branch", r.Branch, map[bool]string{true: r.Branch}[applies])
+
+	return v
+}
+
+func sortedList(v []string) string {
+	v = slices.Clone(v)
+	slices.Sort(v)
+
+	return strings.Join(v, ",")
+}
+
+func protect(cli client.Interface, project string, pb *gitlab.ProtectedBranch, r *Rule) (*gitlab.ProtectedBranch, error) {
+	opts := client.ProtectBranchOptions{
+		PushAccessLevel:           role(r.Push).level(),
+		MergeAccessLevel:          role(r.Merge).level(),
+		UnprotectAccessLevel:      role(r.Unprotect).level(),
+		AllowForcePush:            r.AllowForcePush,
+		CodeOwnerApprovalRequired: r.CodeOwnerApprovalRequired,
+	}
+
+	if pb == nil {
+		return cli.ProtectBranch(project, r.Branch, opts)
+	}
+
+	return cli.UpdateProtectedBranch(project, pb, opts)
+}
+
+func ensureApprovalRule(
+	cli client.Interface, project string,
+	ar *gitlab.ProjectApprovalRule, pb *gitlab.ProtectedBranch, r *Rule,
+) error {
+	opts := client.ApprovalRuleOptions{
+		Name:              r.approvalRuleName(),
+		ApprovalsRequired: r.Approvals.Required,
+		Usernames:         r.Approvals.Users,
+		GroupIDs:          r.Approvals.GroupIDs,
+	}
+
+	var err error
+	if ar == nil {
+		_, err = cli.CreateProjectApprovalRule(project, opts, []int{pb.ID})
+	} else {
+		_, err = cli.UpdateProjectApprovalRule(project, ar.ID, opts, []int{pb.ID})
+	}
+
+	return err
+}
+
+func logDrift(log *logrus.Entry, drift []Drift) {
+	for _, d := range drift {
+		l := log.WithFields(logrus.Fields{
+			"project": d.Project,
+			"branch":  d.Branch,
+			"field":   d.Field,
+			"want":    d.Want,
+			"got":     d.Got,
+		})
+
+		if d.Fixed {
+			l.Info("fixed the drift of the protected branch")
+		} else {
+			l.Warn("found the drift of the protected branch")
+		}
+	}
+}
+
+// Task returns the periodic task reconciling all the projects of the
+// policy, which logs the drift.
+func Task(cli client.Interface, p *Policy) framework.PeriodicTask {
+	return func(ctx context.Context, log *logrus.Entry) error {
+		drift, err := Reconcile(cli, p)
+		logDrift(log, drift)
+
+		return err
+	}
+}
+
+// PushEventHandler returns the handler reconciling the project of the
+// push events to the branches of the rules, which catches the drift soon
+// after the protection of a branch is removed for pushing. The projects
+// not covered by the policy are ignored.
+func PushEventHandler(cli client.Interface, p *Policy) framework.PushEventHandler {
+	return func(ctx context.Context, e *gitlab.PushEvent, log *logrus.Entry) error {
+		project := e.Project.PathWithNamespace
+		if !p.covers(project) {
+			return nil
+		}
+
+		branch := strings.TrimPrefix(e.Ref, "refs/heads/")
+		if !slices.ContainsFunc(p.Rules, func(r Rule) bool { return matchBranch(r.Branch, branch) }) {
+			return nil
+		}
+
+		drift, err := ReconcileProject(cli, project, p)
+		logDrift(log, drift)
+
+		return err
+	}
+}
+
+// matchBranch reports whether the branch matches the name or the wildcard
+// of a protected branch, in which * matches any characters including /.
+func matchBranch(pattern, branch string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == branch
+	}
+
+	if !strings.HasPrefix(branch, parts[0]) {
+		return false
+	}
+
+	branch = branch[len(parts[0]):]
+
+	for _, p := range parts[1 : len(parts)-1] {
+		i := strings.Index(branch, p)
+		if i < 0 {
+			return false
+		}
+
+		branch = branch[i+len(p):]
+	}
+
+	return strings.HasSuffix(branch, parts[len(parts)-1])
+}
@@ -0,0 +1,133 @@
+package secrets
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+const (
+	gcpSecretManagerURL = "https://secretmanager.googleapis.com/v1/"
+	gcpMetadataTokenURL = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+)
+
+// GCPSecretManagerOptions are the options of the provider of Google Cloud
+// Secret Manager.
+type GCPSecretManagerOptions struct {
+	// Name is the version of the secret, such as
+	// projects/p/secrets/robot-token/versions/latest.
+	Name string
+
+	// TokenSource defaults to the service account of the instance or the
+	// workload identity of the pod, from the metadata server.
+	TokenSource oauth2.TokenSource
+}
+
+type gcpSecretManager struct {
+	name string
+	hc   *http.Client
+}
+
+// GCPSecretManager returns the Provider of the secret of Google Cloud
+// Secret Manager. The secret does not expire, but the latest version is
+// fetched on every refresh.
+func GCPSecretManager(opts GCPSecretManagerOptions) (Provider, error) {
+	if opts.Name == "" {
+		return nil, errors.New("missing name of the secret")
+	}
+
+	ts := opts.TokenSource
+	if ts == nil {
+		ts = oauth2.ReuseTokenSource(nil, &metadataTokenSource{
+			hc: &http.Client{Timeout: 10 * time.Second},
+		})
+	}
+
+	return &gcpSecretManager{
+		name: opts.Name,
+		hc: &http.Client{
+			Timeout:   10 * time.Second,
+			Transport: &oauth2.Transport{Source: ts},
+		},
+	}, nil
+}
+
+func (p *gcpSecretManager) Fetch(ctx context.Context) ([]byte, time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, gcpSecretManagerURL+p.name+":access", nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	resp, err := p.hc.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, 0, fmt.Errorf("access the secret %s: %s", p.name, resp.Status)
+	}
+
+	var v struct {
+		Payload struct {
+			Data string `json:"data"`
+		} `json:"payload"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&v); err != nil {
+		return nil, 0, err
+	}
+
+	b, err := base64.StdEncoding.DecodeString(v.Payload.Data)
+
+	return b, 0, err
+}
+
+// metadataTokenSource gets the access tokens of the default service
+// account from the metadata server of Google Cloud.
+type metadataTokenSource struct {
+	hc *http.Client
+}
+
+func (s *metadataTokenSource) Token() (*oauth2.Token, error) {
+	req, err := http.NewRequest(http.MethodGet, gcpMetadataTokenURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Metadata-Flavor", "Google")
+
+	resp, err := s.hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("get the token from the metadata server: %s", resp.Status)
+	}
+
+	var v struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+		TokenType   string `json:"token_type"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&v); err != nil {
+		return nil, err
+	}
+
+	return &oauth2.Token{
+		AccessToken: v.AccessToken,
+		TokenType:   v.TokenType,
+		Expiry:      time.Now().Add(time.Duration(v.ExpiresIn) * time.Second),
+	}, nil
+}
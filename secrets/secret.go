@@ -0,0 +1,148 @@
+// Package secrets fetches the secrets of the robots, such as the webhook
+// secret and the API tokens, from the secret managers like HashiCorp Vault
+// instead of the plaintext files, and renews them before they expire.
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Provider fetches a secret from where it is kept.
+type Provider interface {
+	// Fetch returns the secret, and how long it is valid, which is 0 if
+	// it does not expire.
+	Fetch(ctx context.Context) (value []byte, ttl time.Duration, err error)
+}
+
+// ProviderFunc is a Provider of a function, such as the one decrypting a
+// secret by a cloud KMS.
+type ProviderFunc func(ctx context.Context) ([]byte, time.Duration, error)
+
+func (fn ProviderFunc) Fetch(ctx context.Context) ([]byte, time.Duration, error) {
+	return fn(ctx)
+}
+
+// File returns the Provider of the secret in the file, ignoring the
+// surrounding spaces.
+func File(path string) Provider {
+	return ProviderFunc(func(context.Context) ([]byte, time.Duration, error) {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		return bytes.TrimSpace(b), 0, nil
+	})
+}
+
+const (
+	// defaultRefresh is how often the secrets not expiring are fetched
+	// again, to pick up the rotated ones.
+	defaultRefresh = 5 * time.Minute
+
+	minRetry = 5 * time.Second
+)
+
+// Secret is the current value of a secret, which Run keeps fetching. Pass
+// its Get method to the ones taking a func() []byte, such as
+// client.NewClient and framework.NewHandler.
+type Secret struct {
+	p       Provider
+	refresh time.Duration
+
+	value atomic.Pointer[[]byte]
+	ttl   time.Duration
+}
+
+// New fetches the secret from p, which is fetched again every refresh, or
+// at two thirds of its TTL if it expires earlier. refresh defaults to 5
+// minutes if it is not positive.
+func New(ctx context.Context, p Provider, refresh time.Duration) (*Secret, error) {
+	if refresh <= 0 {
+		refresh = defaultRefresh
+	}
+
+	s := &Secret{p: p, refresh: refresh}
+	if err := s.fetch(ctx); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Get returns the current value of the secret. It is safe for concurrent
+// use.
+func (s *Secret) Get() []byte {
+	return *s.value.Load()
+}
+
+func (s *Secret) fetch(ctx context.Context) error {
+	v, ttl, err := s.p.Fetch(ctx)
+	if err != nil {
+		return fmt.Errorf("fetch the secret: %w", err)
+	}
+
+	if len(v) == 0 {
+		return errors.New("fetch the secret: empty secret")
+	}
+
+	s.value.Store(&v)
+	s.ttl = ttl
+
+	return nil
+}
+
+// next returns how long to wait before fetching the secret again.
+func (s *Secret) next() time.Duration {
+	if s.ttl > 0 {
+		return min(s.refresh, s.ttl*2/3)
+	}
+
+	return s.refresh
+}
+
+// Run fetches the secret again before it expires until ctx is done. The
+// current value is kept if it fails, and the fetching is retried sooner.
+func (s *Secret) Run(ctx context.Context) {
+	log := logrus.WithField("secret-provider", fmt.Sprintf("%T", s.p))
+
+	wait := s.next()
+	retry := minRetry
+
+	for {
+		t := time.NewTimer(wait)
+
+		select {
+		case <-ctx.Done():
+			t.Stop()
+
+			return
+
+		case <-t.C:
+		}
+
+		if err := s.fetch(ctx); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+
+			log.WithError(err).Warn("renew the secret, keep the current one")
+
+			wait = min(retry, s.next())
+			retry = min(retry*2, s.refresh)
+
+			continue
+		}
+
+		wait = s.next()
+		retry = minRetry
+	}
+}
@@ -0,0 +1,215 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultKubernetesTokenFile is the token of the service account of the
+// pod, which logs in to Vault by the Kubernetes auth method.
+const defaultKubernetesTokenFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
+// VaultOptions are the options of the Vault provider.
+type VaultOptions struct {
+	// Address is the URL of Vault, such as https://vault:8200.
+	Address string
+
+	// Path is the path of the secret, such as secret/data/robot for the
+	// version 2 of the KV secrets engine.
+	Path string
+
+	// Field is the key of the secret in the data of Path.
+	Field string
+
+	// Token authenticates to Vault. The Kubernetes auth method is used
+	// if it is empty.
+	Token string
+
+	// KubernetesRole is the role of the Kubernetes auth method, which is
+	// mounted at KubernetesMount, auth/kubernetes by default. The pod
+	// logs in with its service account token, read from
+	// KubernetesTokenFile if it is not the default one.
+	KubernetesRole      string
+	KubernetesMount     string
+	KubernetesTokenFile string
+
+	// Namespace is the namespace of Vault Enterprise, if any.
+	Namespace string
+
+	// Client defaults to the one with a timeout of 10 seconds.
+	Client *http.Client
+}
+
+// vaultProvider reads a secret from Vault. It logs in again when the
+// token of the Kubernetes auth method is to expire.
+type vaultProvider struct {
+	opts VaultOptions
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// Vault returns the Provider of the field of the secret of HashiCorp Vault.
+// Both the versions of the KV secrets engine and the dynamic secrets are
+// supported. The TTL of the secret is the lease duration of it.
+func Vault(opts VaultOptions) (Provider, error) {
+	if opts.Address == "" || opts.Path == "" || opts.Field == "" {
+		return nil, errors.New("missing address, path or field of the vault secret")
+	}
+
+	if opts.Token == "" && opts.KubernetesRole == "" {
+		return nil, errors.New("missing token or kubernetes role of vault")
+	}
+
+	if opts.KubernetesMount == "" {
+		opts.KubernetesMount = "auth/kubernetes"
+	}
+
+	if opts.KubernetesTokenFile == "" {
+		opts.KubernetesTokenFile = defaultKubernetesTokenFile
+	}
+
+	if opts.Client == nil {
+		opts.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	opts.Address = strings.TrimSuffix(opts.Address, "/")
+
+	return &vaultProvider{opts: opts, token: opts.Token}, nil
+}
+
+type vaultResponse struct {
+	Data          map[string]interface{} `json:"data"`
+	LeaseDuration int                    `json:"lease_duration"`
+
+	Auth *struct {
+		ClientToken   string `json:"client_token"`
+		LeaseDuration int    `json:"lease_duration"`
+	} `json:"auth"`
+
+	Errors []string `json:"errors"`
+}
+
+func (p *vaultProvider) Fetch(ctx context.Context) ([]byte, time.Duration, error) {
+	token, err := p.login(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var v vaultResponse
+	if err := p.do(ctx, http.MethodGet, p.opts.Path, token, nil, &v); err != nil {
+		return nil, 0, err
+	}
+
+	data := v.Data
+
+	// The data of the version 2 of the KV secrets engine is nested.
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		if _, ok := data["metadata"]; ok {
+			data = nested
+		}
+	}
+
+	s, ok := data[p.opts.Field].(string)
+	if !ok {
+		return nil, 0, fmt.Errorf("no field %s of the vault secret %s", p.opts.Field, p.opts.Path)
+	}
+
+	return []byte(s), time.Duration(v.LeaseDuration) * time.Second, nil
+}
+
+// login returns the token to read the secret with, which is the static one
+// or the one of the Kubernetes auth method.
+func (p *vaultProvider) login(ctx context.Context) (string, error) {
+	if p.opts.Token != "" {
+		return p.opts.Token, nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.token != "" && time.Until(p.expires) > time.Minute {
+		return p.token, nil
+	}
+
+	jwt, err := os.ReadFile(p.opts.KubernetesTokenFile)
+	if err != nil {
+		return "", err
+	}
+
+	var v vaultResponse
+	err = p.do(ctx, http.MethodPost, p.opts.KubernetesMount+"/login", "", map[string]string{
+		"role": p.opts.KubernetesRole,
+		"jwt":  strings.TrimSpace(string(jwt)),
+	}, &v)
+	if err != nil {
+		return "", fmt.Errorf("log in to vault: %w", err)
+	}
+
+	if v.Auth == nil || v.Auth.ClientToken == "" {
+		return "", errors.New("log in to vault: no token")
+	}
+
+	p.token = v.Auth.ClientToken
+	p.expires = time.Now().Add(time.Duration(v.Auth.LeaseDuration) * time.Second)
+
+	return p.token, nil
+}
+
+func (p *vaultProvider) do(ctx context.Context, method, path, token string, body, result interface{}) error {
+	var r io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+
+		r = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, p.opts.Address+"/v1/"+strings.TrimPrefix(path, "/"), r)
+	if err != nil {
+		return err
+	}
+
+	if token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+
+	if p.opts.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.opts.Namespace)
+	}
+
+	resp, err := p.opts.Client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var v vaultResponse
+		_ = json.NewDecoder(io.LimitReader(resp.Body, 1<<16)).Decode(&v)
+
+		if method == http.MethodGet && resp.StatusCode == http.StatusForbidden && p.opts.Token == "" {
+			// The token may have been revoked, log in again next time.
+			p.mu.Lock()
+			p.token = ""
+			p.mu.Unlock()
+		}
+
+		return fmt.Errorf("%s %s: %s %s", method, path, resp.Status, strings.Join(v.Errors, "; "))
+	}
+
+	return json.NewDecoder(resp.Body).Decode(result)
+}